@@ -1,10 +1,12 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gophkeeper/gophkeeper/internal/client/cli"
 	"github.com/gophkeeper/gophkeeper/internal/client/tui"
 	"github.com/gophkeeper/gophkeeper/internal/config"
 )
@@ -15,18 +17,23 @@ var (
 )
 
 func main() {
-	// Показываем версию при запросе (до Parse, чтобы -v/--version не считались неизвестными флагами)
-	for _, arg := range os.Args[1:] {
-		if arg == "-v" || arg == "--version" || arg == "version" {
-			fmt.Printf("GophKeeper Client\nVersion: %s\nBuild Date: %s\n", version, buildDate)
-			os.Exit(0)
-		}
+	cli.Version, cli.BuildDate = version, buildDate
+	// -v/--version проверяются до Parse, чтобы не считаться неизвестными флагами, и только первым
+	// аргументом: дальше «version» может быть названием записи или значением флага команды
+	if len(os.Args) > 1 && (os.Args[1] == "-v" || os.Args[1] == "--version") {
+		cli.PrintVersion(os.Stdout)
+		os.Exit(0)
 	}
 
 	cfg := config.LoadClient()
 
+	// Команды без TUI после общих флагов: gophkeeper-client [флаги] get <id|название> -field password
+	if args := flag.Args(); len(args) > 0 {
+		os.Exit(cli.Run(cfg, args, os.Stdout, os.Stderr))
+	}
+
 	// Создаём модель приложения
-	app, err := tui.NewAppModel(cfg.Server)
+	app, err := tui.NewAppModel(cfg.Server, cfg.CacheDir, cfg.SyncInterval, cfg.EncryptMetadata, cfg.ClipboardTimeout)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error creating app: %v\n", err)
 		os.Exit(1)
@@ -1,19 +1,24 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/gophkeeper/gophkeeper/internal/blobstore"
 	"github.com/gophkeeper/gophkeeper/internal/config"
 	"github.com/gophkeeper/gophkeeper/internal/crypto"
+	domainrepo "github.com/gophkeeper/gophkeeper/internal/domain/repository"
 	"github.com/gophkeeper/gophkeeper/internal/migrations"
 	"github.com/gophkeeper/gophkeeper/internal/repository"
 	"github.com/gophkeeper/gophkeeper/internal/server"
 	"github.com/gophkeeper/gophkeeper/internal/storage"
 	"github.com/gophkeeper/gophkeeper/internal/usecase/auth"
+	"github.com/gophkeeper/gophkeeper/internal/usecase/blob"
 	"github.com/gophkeeper/gophkeeper/internal/usecase/data"
 	"github.com/gophkeeper/gophkeeper/proto"
 	"google.golang.org/grpc"
@@ -40,20 +45,36 @@ func main() {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
+	// Поисковый индекс для записей, сохранённых до его появления
+	if n, err := st.BackfillSearchIndex(); err != nil {
+		log.Fatalf("Failed to build search index: %v", err)
+	} else if n > 0 {
+		log.Printf("Indexed %d records for search", n)
+	}
+
 	// Repositories (адаптеры к storage)
 	userRepo := repository.NewUserRepository(st)
 	dataRepo := repository.NewDataRepository(st)
+	blobRepo := repository.NewBlobRepository(st)
+
+	// Хранилище содержимого файлов
+	blobStore, err := newBlobStore(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize blob store: %v", err)
+	}
 
 	// Use cases
 	authUC := auth.NewAuthUseCase(userRepo)
-	dataUC := data.NewDataUseCase(dataRepo)
+	dataUC := data.NewDataUseCase(dataRepo, cfg.TombstoneRetention)
+	blobUC := blob.NewBlobUseCase(blobRepo, blobStore, blob.DefaultMaxBlobSize, cfg.TombstoneRetention)
 
 	// Delivery: gRPC services
 	authService := server.NewAuthService(authUC)
-	dataService := server.NewDataService(dataUC)
+	dataService := server.NewDataService(dataUC, blobUC)
 
 	grpcServer := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(server.LoggingInterceptor, server.AuthInterceptor),
+		grpc.ChainStreamInterceptor(server.LoggingStreamInterceptor, server.AuthStreamInterceptor),
 	)
 
 	proto.RegisterAuthServiceServer(grpcServer, authService)
@@ -76,8 +97,56 @@ func main() {
 		}
 	}()
 
+	// Периодическая очистка отметок об удалении старше срока хранения
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	go purgeDeletedLoop(purgeCtx, dataUC, blobUC)
+
 	<-sigChan
 	log.Println("Shutting down server...")
+	stopPurge()
 	grpcServer.GracefulStop()
 	log.Println("Server stopped")
 }
+
+// newBlobStore создаёт хранилище содержимого файлов по cfg.BlobStore
+func newBlobStore(cfg *config.ServerConfig) (domainrepo.BlobStore, error) {
+	if cfg.BlobStore == config.BlobStoreS3 {
+		return blobstore.NewS3(blobstore.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		})
+	}
+	return blobstore.NewFilesystem(cfg.BlobDir)
+}
+
+// tombstonePurgeInterval — как часто сервер очищает устаревшие отметки об удалении
+const tombstonePurgeInterval = time.Hour
+
+// purgeDeletedLoop удаляет устаревшие tombstones и файлы, на которые больше не ссылаются записи,
+// при старте и затем раз в tombstonePurgeInterval
+func purgeDeletedLoop(ctx context.Context, dataUC *data.DataUseCase, blobUC *blob.BlobUseCase) {
+	ticker := time.NewTicker(tombstonePurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := dataUC.PurgeDeleted(ctx); err != nil {
+			log.Printf("Failed to purge deleted data: %v", err)
+		} else if n > 0 {
+			log.Printf("Purged %d deleted records", n)
+		}
+		if n, err := blobUC.PurgeUnreferenced(ctx); err != nil {
+			log.Printf("Failed to purge unreferenced blobs: %v", err)
+		} else if n > 0 {
+			log.Printf("Purged %d unreferenced blobs", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
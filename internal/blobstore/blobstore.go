@@ -0,0 +1,58 @@
+// Package blobstore содержит реализации domain/repository.BlobStore: файловую (Filesystem)
+// и S3-совместимую (S3). Содержимое адресуется своим SHA-256 и раскладывается по каталогам
+// (префиксам) из первых символов ключа, чтобы в одном каталоге не скапливались миллионы файлов.
+package blobstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+)
+
+// keyLen — длина ключа содержимого (hex SHA-256)
+const keyLen = sha256.Size * 2
+
+// validKey проверяет, что ключ — hex SHA-256: иначе он мог бы вывести за пределы хранилища
+func validKey(key string) error {
+	if len(key) != keyLen {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	for _, c := range key {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return fmt.Errorf("invalid blob key %q", key)
+		}
+	}
+	return nil
+}
+
+// shardedPath возвращает относительный путь содержимого: ab/cd/abcd…
+func shardedPath(key string) string {
+	return path.Join(key[0:2], key[2:4], key)
+}
+
+// spool копирует r во временный файл в dir и считает SHA-256 содержимого.
+// Возвращённый файл открыт и перемотан в начало; удалить его должен вызывающий.
+func spool(dir string, r io.Reader) (*os.File, string, int64, error) {
+	f, err := os.CreateTemp(dir, "upload-*")
+	if err != nil {
+		return nil, "", 0, err
+	}
+	discard := func(err error) (*os.File, string, int64, error) {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return nil, "", 0, err
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(f, hash), r)
+	if err != nil {
+		return discard(fmt.Errorf("read blob: %w", err))
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return discard(err)
+	}
+	return f, hex.EncodeToString(hash.Sum(nil)), size, nil
+}
@@ -0,0 +1,84 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	domainrepo "github.com/gophkeeper/gophkeeper/internal/domain/repository"
+)
+
+// Filesystem хранит содержимое файлов в локальном каталоге: root/ab/cd/<sha256>.
+// Незавершённые загрузки пишутся в root/tmp и переносятся на место переименованием,
+// поэтому читатель никогда не видит частично записанный файл.
+type Filesystem struct {
+	root string
+}
+
+var _ domainrepo.BlobStore = (*Filesystem)(nil)
+
+// NewFilesystem создаёт файловое хранилище в каталоге root (каталог создаётся при необходимости)
+func NewFilesystem(root string) (*Filesystem, error) {
+	if err := os.MkdirAll(filepath.Join(root, "tmp"), 0o700); err != nil {
+		return nil, err
+	}
+	return &Filesystem{root: root}, nil
+}
+
+// Put сохраняет содержимое под ключом его SHA-256; уже сохранённое содержимое не перезаписывается
+func (s *Filesystem) Put(ctx context.Context, r io.Reader) (string, int64, error) {
+	f, key, size, err := spool(filepath.Join(s.root, "tmp"), r)
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(f.Name())
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return "", 0, err
+	}
+	if err := f.Close(); err != nil {
+		return "", 0, err
+	}
+
+	target := s.path(key)
+	if _, err := os.Stat(target); err == nil {
+		return key, size, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+		return "", 0, err
+	}
+	if err := os.Rename(f.Name(), target); err != nil {
+		return "", 0, err
+	}
+	return key, size, nil
+}
+
+// Open открывает содержимое для чтения
+func (s *Filesystem) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := validKey(key); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, domainrepo.ErrBlobNotFound
+	}
+	return f, err
+}
+
+// Delete удаляет содержимое. Опустевшие каталоги префиксов остаются: их переиспользуют следующие загрузки.
+func (s *Filesystem) Delete(ctx context.Context, key string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *Filesystem) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(shardedPath(key)))
+}
@@ -0,0 +1,100 @@
+package blobstore_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gophkeeper/gophkeeper/internal/blobstore"
+	domainrepo "github.com/gophkeeper/gophkeeper/internal/domain/repository"
+)
+
+// errReader возвращает ошибку посреди чтения (оборванная загрузка)
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("stream aborted")
+}
+
+func sha(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestFilesystem_PutOpenDelete(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store, err := blobstore.NewFilesystem(root)
+	if err != nil {
+		t.Fatalf("NewFilesystem: %v", err)
+	}
+
+	key, size, err := store.Put(ctx, strings.NewReader("sealed content"))
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if key != sha("sealed content") || size != 14 {
+		t.Errorf("Put = %s, %d, want sha256 of content and 14", key, size)
+	}
+	// Содержимое раскладывается по каталогам префиксов ключа
+	if _, err := os.Stat(filepath.Join(root, key[:2], key[2:4], key)); err != nil {
+		t.Errorf("sharded file: %v", err)
+	}
+
+	// Повторная загрузка того же содержимого даёт тот же ключ
+	if again, _, err := store.Put(ctx, strings.NewReader("sealed content")); err != nil || again != key {
+		t.Errorf("Put again = %s, %v, want %s", again, err, key)
+	}
+
+	rc, err := store.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	content, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(content) != "sealed content" {
+		t.Errorf("content = %q", content)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Open(ctx, key); !errors.Is(err, domainrepo.ErrBlobNotFound) {
+		t.Errorf("Open after Delete = %v, want ErrBlobNotFound", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("Delete missing = %v, want nil", err)
+	}
+}
+
+func TestFilesystem_AbortedUploadLeavesNothing(t *testing.T) {
+	root := t.TempDir()
+	store, err := blobstore.NewFilesystem(root)
+	if err != nil {
+		t.Fatalf("NewFilesystem: %v", err)
+	}
+
+	if _, _, err := store.Put(context.Background(), errReader{}); err == nil {
+		t.Fatal("Put: want error")
+	}
+	entries, _ := os.ReadDir(filepath.Join(root, "tmp"))
+	if len(entries) != 0 {
+		t.Errorf("tmp = %v, want empty", entries)
+	}
+}
+
+func TestFilesystem_RejectsInvalidKey(t *testing.T) {
+	store, err := blobstore.NewFilesystem(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFilesystem: %v", err)
+	}
+	if _, err := store.Open(context.Background(), "../../etc/passwd"); err == nil || errors.Is(err, domainrepo.ErrBlobNotFound) {
+		t.Errorf("Open(traversal) = %v, want invalid key error", err)
+	}
+}
@@ -0,0 +1,153 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	domainrepo "github.com/gophkeeper/gophkeeper/internal/domain/repository"
+)
+
+// S3Config — параметры S3-совместимого хранилища (AWS S3, MinIO, Ceph RGW и т.п.)
+type S3Config struct {
+	// Endpoint — адрес сервиса, например https://s3.eu-central-1.amazonaws.com или http://localhost:9000
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// TempDir — каталог для загрузки во временный файл перед отправкой (по умолчанию os.TempDir)
+	TempDir string
+	// HTTPClient — клиент для запросов (по умолчанию http.DefaultClient)
+	HTTPClient *http.Client
+}
+
+// S3 хранит содержимое файлов в бакете S3-совместимого сервиса под ключами ab/cd/<sha256>.
+// Используются запросы в стиле пути (endpoint/bucket/key), подписанные AWS Signature Version 4.
+type S3 struct {
+	cfg      S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+var _ domainrepo.BlobStore = (*S3)(nil)
+
+// NewS3 создаёт S3-совместимое хранилище
+func NewS3(cfg S3Config) (*S3, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", cfg.Endpoint)
+	}
+	if cfg.Bucket == "" {
+		return nil, errors.New("S3 bucket is required")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &S3{cfg: cfg, endpoint: endpoint, client: client, now: time.Now}, nil
+}
+
+// Put сохраняет содержимое. Оно сначала пишется во временный файл: ключ (SHA-256) и длина
+// нужны до начала запроса, а хеш тела заодно подписывается и проверяется сервисом.
+func (s *S3) Put(ctx context.Context, r io.Reader) (string, int64, error) {
+	f, key, size, err := spool(s.cfg.TempDir, r)
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	req, err := s.request(ctx, http.MethodPut, key, nil)
+	if err != nil {
+		return "", 0, err
+	}
+	// Тело задаётся вместе с GetBody, чтобы транспорт мог повторить запрос после обрыва соединения
+	req.ContentLength = size
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(io.NewSectionReader(f, 0, size)), nil
+	}
+	req.Body = io.NopCloser(io.NewSectionReader(f, 0, size))
+	signV4(req, key, s.cfg.AccessKey, s.cfg.SecretKey, s.cfg.Region, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return "", 0, responseError("put", key, resp)
+	}
+	return key, size, nil
+}
+
+// Open открывает содержимое для чтения (тело ответа GET)
+func (s *S3) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := validKey(key); err != nil {
+		return nil, err
+	}
+	req, err := s.request(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	signV4(req, emptyPayloadHash, s.cfg.AccessKey, s.cfg.SecretKey, s.cfg.Region, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, domainrepo.ErrBlobNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, responseError("get", key, resp)
+	}
+	return resp.Body, nil
+}
+
+// Delete удаляет содержимое
+func (s *S3) Delete(ctx context.Context, key string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	req, err := s.request(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	signV4(req, emptyPayloadHash, s.cfg.AccessKey, s.cfg.SecretKey, s.cfg.Region, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusNotFound {
+		return responseError("delete", key, resp)
+	}
+	return nil
+}
+
+// request создаёт запрос к объекту key в стиле пути: endpoint/bucket/ab/cd/key
+func (s *S3) request(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.cfg.Bucket + "/" + shardedPath(key)
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+// responseError возвращает ошибку с кодом ответа и началом тела (S3 описывает ошибку в XML)
+func responseError(op, key string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("s3 %s %s: %s: %s", op, key, resp.Status, strings.TrimSpace(string(body)))
+}
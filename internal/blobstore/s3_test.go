@@ -0,0 +1,140 @@
+package blobstore_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gophkeeper/gophkeeper/internal/blobstore"
+	domainrepo "github.com/gophkeeper/gophkeeper/internal/domain/repository"
+)
+
+// fakeS3 — минимальная замена S3 для тестов: объекты в памяти, проверка заголовков подписи и хеша тела
+type fakeS3 struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	f := &fakeS3{t: t, objects: map[string][]byte{}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=access/") || !strings.Contains(auth, "/eu-test/s3/aws4_request") {
+		http.Error(w, "<Error><Code>AccessDenied</Code></Error>", http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(body)
+		if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+			http.Error(w, "<Error><Code>XAmzContentSHA256Mismatch</Code></Error>", http.StatusBadRequest)
+			return
+		}
+		f.objects[r.URL.Path] = body
+	case http.MethodGet:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func newS3Store(t *testing.T, endpoint string) *blobstore.S3 {
+	t.Helper()
+	store, err := blobstore.NewS3(blobstore.S3Config{
+		Endpoint:  endpoint,
+		Region:    "eu-test",
+		Bucket:    "vault",
+		AccessKey: "access",
+		SecretKey: "secret",
+		TempDir:   t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("NewS3: %v", err)
+	}
+	return store
+}
+
+func TestS3_PutOpenDelete(t *testing.T) {
+	ctx := context.Background()
+	fake, srv := newFakeS3(t)
+	store := newS3Store(t, srv.URL)
+
+	key, size, err := store.Put(ctx, strings.NewReader("sealed content"))
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if key != sha("sealed content") || size != 14 {
+		t.Errorf("Put = %s, %d, want sha256 of content and 14", key, size)
+	}
+	if _, ok := fake.objects["/vault/"+key[:2]+"/"+key[2:4]+"/"+key]; !ok {
+		t.Errorf("objects = %v, want sharded key in bucket vault", fake.objects)
+	}
+
+	rc, err := store.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	content, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(content) != "sealed content" {
+		t.Errorf("content = %q", content)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Open(ctx, key); !errors.Is(err, domainrepo.ErrBlobNotFound) {
+		t.Errorf("Open after Delete = %v, want ErrBlobNotFound", err)
+	}
+}
+
+func TestS3_ReportsServiceErrors(t *testing.T) {
+	_, srv := newFakeS3(t)
+	store, err := blobstore.NewS3(blobstore.S3Config{
+		Endpoint:  srv.URL,
+		Region:    "eu-test",
+		Bucket:    "vault",
+		AccessKey: "wrong",
+		SecretKey: "secret",
+	})
+	if err != nil {
+		t.Fatalf("NewS3: %v", err)
+	}
+
+	_, _, err = store.Put(context.Background(), strings.NewReader("x"))
+	if err == nil || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Errorf("Put = %v, want AccessDenied", err)
+	}
+}
+
+func TestNewS3_Validation(t *testing.T) {
+	if _, err := blobstore.NewS3(blobstore.S3Config{Endpoint: "localhost:9000", Bucket: "b"}); err == nil {
+		t.Error("endpoint without scheme: want error")
+	}
+	if _, err := blobstore.NewS3(blobstore.S3Config{Endpoint: "http://localhost:9000"}); err == nil {
+		t.Error("empty bucket: want error")
+	}
+}
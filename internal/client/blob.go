@@ -0,0 +1,173 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gophkeeper/gophkeeper/internal/client/vault"
+	"github.com/gophkeeper/gophkeeper/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// blobChunkSize — размер открытой части файла, которая шифруется отдельно
+	blobChunkSize = 64 << 10
+	// maxBlobFrameSize ограничивает размер зашифрованной части при выгрузке
+	maxBlobFrameSize = blobChunkSize + 4<<10
+)
+
+// ErrBlobCorrupted — выгруженное содержимое не совпадает с файлом, на который ссылается запись
+var ErrBlobCorrupted = errors.New("blob content does not match the record")
+
+// BlobInfo описывает загруженный файл
+type BlobInfo struct {
+	ID string
+	// Size — размер открытого содержимого
+	Size int64
+	// SHA256 — hex SHA-256 открытого содержимого; хранится в зашифрованной записи и проверяется при выгрузке
+	SHA256 string
+}
+
+// UploadBlob шифрует содержимое r частями по blobChunkSize и загружает на сервер потоком.
+// Каждая часть передаётся как длина (4 байта, big endian) и конверт vault.Encrypt.
+func (c *Client) UploadBlob(r io.Reader) (*BlobInfo, error) {
+	if c.vault == nil {
+		return nil, vault.ErrLocked
+	}
+
+	ctx, cancel := c.getStreamContext()
+	defer cancel()
+
+	stream, err := c.dataClient.UploadBlob(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	var size int64
+	buf := make([]byte, blobChunkSize)
+	for {
+		n, readErr := io.ReadFull(r, buf)
+		if n > 0 {
+			hash.Write(buf[:n])
+			size += int64(n)
+			sealed, err := c.vault.Encrypt(buf[:n])
+			if err != nil {
+				return nil, err
+			}
+			frame := binary.BigEndian.AppendUint32(make([]byte, 0, 4+len(sealed)), uint32(len(sealed)))
+			if err := stream.Send(&proto.BlobChunk{Content: append(frame, sealed...)}); err != nil {
+				return nil, err
+			}
+		}
+		if errors.Is(readErr, io.EOF) || errors.Is(readErr, io.ErrUnexpectedEOF) {
+			break
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("upload failed: %s", resp.Message)
+	}
+
+	return &BlobInfo{
+		ID:     resp.BlobId,
+		Size:   size,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// DownloadBlob выгружает файл blobID, расшифровывает и пишет его в w. Если SHA-256 содержимого
+// не совпадает с sha256Hex из записи, возвращается ErrBlobCorrupted — к этому моменту в w уже
+// могла попасть часть данных, поэтому писать стоит во временный файл.
+func (c *Client) DownloadBlob(blobID, sha256Hex string, w io.Writer) (int64, error) {
+	if c.vault == nil {
+		return 0, vault.ErrLocked
+	}
+
+	ctx, cancel := c.getStreamContext()
+	defer cancel()
+
+	stream, err := c.dataClient.DownloadBlob(ctx, &proto.DownloadBlobRequest{BlobId: blobID})
+	if err != nil {
+		return 0, err
+	}
+
+	in := bufio.NewReader(&blobStreamReader{stream: stream})
+	hash := sha256.New()
+	var size int64
+	var header [4]byte
+	for {
+		if _, err := io.ReadFull(in, header[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return size, err
+		}
+		frameSize := binary.BigEndian.Uint32(header[:])
+		if frameSize > maxBlobFrameSize {
+			return size, ErrBlobCorrupted
+		}
+		sealed := make([]byte, frameSize)
+		if _, err := io.ReadFull(in, sealed); err != nil {
+			return size, err
+		}
+		chunk, err := c.vault.Decrypt(sealed)
+		if err != nil {
+			return size, fmt.Errorf("decrypt blob %s: %w", blobID, err)
+		}
+		hash.Write(chunk)
+		n, err := w.Write(chunk)
+		size += int64(n)
+		if err != nil {
+			return size, err
+		}
+	}
+
+	if hex.EncodeToString(hash.Sum(nil)) != sha256Hex {
+		return size, ErrBlobCorrupted
+	}
+	return size, nil
+}
+
+// getStreamContext создаёт контекст с токеном авторизации для потоковых вызовов: размер файла
+// заранее не известен, поэтому времени не ограничивается
+func (c *Client) getStreamContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	md := metadata.New(map[string]string{
+		"authorization": "Bearer " + c.accessToken,
+	})
+	return metadata.NewOutgoingContext(ctx, md), cancel
+}
+
+// blobStreamReader читает части DownloadBlob как непрерывный поток байт
+type blobStreamReader struct {
+	stream grpc.ServerStreamingClient[proto.BlobChunk]
+	buf    []byte
+}
+
+func (r *blobStreamReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = chunk.Content
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
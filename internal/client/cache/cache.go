@@ -0,0 +1,481 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// ErrNotFound — записи нет в локальной копии
+var ErrNotFound = errors.New("record not found in cache")
+
+// Ключи служебных значений
+const (
+	KeyCursor     = "cursor"      // курсор последней синхронизации
+	KeyKeyCheck   = "key_check"   // блок проверки мастер-пароля для входа без связи с сервером
+	KeyVaultCheck = "vault_check" // значение, зашифрованное мастер-ключом, которым зашифрована копия
+	KeySyncedAt   = "synced_at"   // время последней успешной синхронизации (Unix, секунды)
+)
+
+// Op — вид отложенного изменения
+type Op string
+
+const (
+	OpSave   Op = "save"
+	OpDelete Op = "delete"
+)
+
+// Record — запись локальной копии. Data — proto.Data целиком, зашифрованная мастер-ключом:
+// кэш не видит ни названий, ни метаданных.
+type Record struct {
+	ID        string `gorm:"primaryKey"`
+	Data      []byte
+	UpdatedAt time.Time
+}
+
+// TableName возвращает имя таблицы
+func (Record) TableName() string {
+	return "records"
+}
+
+// OutboxEntry — изменение, которое ещё не отправлено на сервер.
+// BaseVersion — версия записи, от которой вносились изменения (0 для записи, созданной офлайн),
+// BaseData — запись в этой версии (nil для новой записи).
+// Conflict выставляется, если сервер отклонил изменение из-за конфликта версий; TheirsData — актуальная
+// копия с сервера (nil, если запись на сервере удалена).
+type OutboxEntry struct {
+	RecordID    string `gorm:"primaryKey"`
+	Op          Op
+	BaseVersion int64
+	Data        []byte
+	BaseData    []byte
+	Conflict    bool
+	TheirsData  []byte
+	LastError   string
+	QueuedAt    time.Time
+}
+
+// TableName возвращает имя таблицы
+func (OutboxEntry) TableName() string {
+	return "outbox"
+}
+
+type stateEntry struct {
+	Key   string `gorm:"primaryKey"`
+	Value []byte
+}
+
+func (stateEntry) TableName() string {
+	return "state"
+}
+
+// Changes — изменения, полученные с сервера при синхронизации
+type Changes struct {
+	Records []Record
+	Deleted []string
+	// Full — Records содержит все записи: локальные записи, которых в нём нет, удаляются
+	Full   bool
+	Cursor string
+}
+
+// Cache — локальная копия записей пользователя в SQLite
+type Cache struct {
+	db *gorm.DB
+}
+
+// PathFor возвращает путь к файлу кэша для пары сервер/логин внутри каталога dir
+func PathFor(dir, server, login string) string {
+	sum := sha256.Sum256([]byte(server + "\x00" + login))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".db")
+}
+
+// Open открывает (при необходимости создаёт) файл кэша и применяет миграции
+func Open(path string) (*Cache, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("create cache dir: %w", err)
+	}
+	// Создаём файл заранее, чтобы он был доступен только владельцу
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("create cache file: %w", err)
+	}
+	_ = f.Close()
+
+	if err := runMigrations(path); err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{
+		// Лог GORM печатается в терминал и ломает отрисовку TUI
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache: %w", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get underlying DB: %w", err)
+	}
+	// Одно соединение: запись из фоновой синхронизации и из интерфейса не конфликтует за блокировку файла
+	sqlDB.SetMaxOpenConns(1)
+
+	return &Cache{db: db}, nil
+}
+
+// Close закрывает файл кэша
+func (c *Cache) Close() error {
+	sqlDB, err := c.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// Records возвращает все записи локальной копии
+func (c *Cache) Records() ([]Record, error) {
+	var records []Record
+	if err := c.db.Order("id").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Record возвращает запись по ID или ErrNotFound
+func (c *Cache) Record(id string) (*Record, error) {
+	var r Record
+	if err := c.db.Where("id = ?", id).First(&r).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &r, nil
+}
+
+// PutRecord сохраняет запись, уже принятую сервером
+func (c *Cache) PutRecord(id string, data []byte) error {
+	return putRecord(c.db, id, data)
+}
+
+// DeleteRecord удаляет запись, уже удалённую на сервере
+func (c *Cache) DeleteRecord(id string) error {
+	return c.db.Where("id = ?", id).Delete(&Record{}).Error
+}
+
+func putRecord(tx *gorm.DB, id string, data []byte) error {
+	return tx.Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&Record{ID: id, Data: data, UpdatedAt: time.Now()}).Error
+}
+
+// QueueSave сохраняет запись локально и ставит её отправку в очередь.
+// Повторные правки одной записи объединяются: в очереди остаётся последнее содержимое
+// и исходная базовая версия.
+func (c *Cache) QueueSave(id string, baseVersion int64, data []byte) error {
+	return c.db.Transaction(func(tx *gorm.DB) error {
+		entry, err := findEntry(tx, id)
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			entry = &OutboxEntry{RecordID: id, BaseVersion: baseVersion, QueuedAt: time.Now()}
+			// Запоминаем исходную версию для трёхстороннего сравнения при конфликте
+			var base Record
+			if err := tx.Where("id = ?", id).Limit(1).Find(&base).Error; err != nil {
+				return err
+			}
+			entry.BaseData = base.Data
+		}
+		entry.Op = OpSave
+		entry.Data = data
+		if err := tx.Save(entry).Error; err != nil {
+			return err
+		}
+		return putRecord(tx, id, data)
+	})
+}
+
+// QueueDelete удаляет запись локально и ставит удаление в очередь.
+// Запись, созданная офлайн и ещё не отправленная, просто убирается из очереди.
+// Удаление снимает неразрешённый конфликт: локальной версии больше нет, и удаление отправляется
+// при следующей синхронизации.
+func (c *Cache) QueueDelete(id string, baseVersion int64) error {
+	return c.db.Transaction(func(tx *gorm.DB) error {
+		entry, err := findEntry(tx, id)
+		if err != nil {
+			return err
+		}
+		switch {
+		case entry != nil && entry.Op == OpSave && entry.BaseVersion == 0:
+			if err := tx.Delete(entry).Error; err != nil {
+				return err
+			}
+		case entry != nil:
+			entry.Op = OpDelete
+			entry.Data = nil
+			entry.BaseData = nil
+			entry.Conflict = false
+			entry.TheirsData = nil
+			entry.LastError = ""
+			if err := tx.Save(entry).Error; err != nil {
+				return err
+			}
+		default:
+			entry = &OutboxEntry{RecordID: id, Op: OpDelete, BaseVersion: baseVersion, QueuedAt: time.Now()}
+			if err := tx.Create(entry).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where("id = ?", id).Delete(&Record{}).Error
+	})
+}
+
+func findEntry(tx *gorm.DB, id string) (*OutboxEntry, error) {
+	var entry OutboxEntry
+	if err := tx.Where("record_id = ?", id).First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Outbox возвращает очередь в порядке постановки
+func (c *Cache) Outbox() ([]OutboxEntry, error) {
+	var entries []OutboxEntry
+	if err := c.db.Order("queued_at, record_id").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Pending возвращает запись очереди для id (nil, если изменений в очереди нет)
+func (c *Cache) Pending(id string) (*OutboxEntry, error) {
+	return findEntry(c.db, id)
+}
+
+// PendingCount возвращает число неотправленных изменений
+func (c *Cache) PendingCount() (int64, error) {
+	var n int64
+	err := c.db.Model(&OutboxEntry{}).Count(&n).Error
+	return n, err
+}
+
+// Ack убирает изменение из очереди после того, как сервер его принял.
+// data — запись в том виде, в каком её сохранил сервер (nil для удаления).
+func (c *Cache) Ack(id string, data []byte) error {
+	return c.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("record_id = ?", id).Delete(&OutboxEntry{}).Error; err != nil {
+			return err
+		}
+		if data == nil {
+			return nil
+		}
+		return putRecord(tx, id, data)
+	})
+}
+
+// MarkFailed запоминает, что сервер отклонил изменение; при следующей синхронизации оно будет повторено
+func (c *Cache) MarkFailed(id string, message string) error {
+	return c.db.Model(&OutboxEntry{}).
+		Where("record_id = ?", id).
+		Update("last_error", message).Error
+}
+
+// MarkConflict запоминает, что сервер отклонил изменение из-за конфликта версий.
+// theirs — актуальная копия записи с сервера (nil, если запись удалена). Изменение не отправляется,
+// пока конфликт не разрешён через Requeue или Discard.
+func (c *Cache) MarkConflict(id string, theirs []byte, message string) error {
+	return c.db.Model(&OutboxEntry{}).
+		Where("record_id = ?", id).
+		Updates(map[string]interface{}{
+			"conflict":    true,
+			"theirs_data": theirs,
+			"last_error":  message,
+		}).Error
+}
+
+// Requeue разрешает конфликт: data — итоговая запись, которая отправится поверх версии сервера
+// baseVersion (её содержимое base становится новой исходной версией)
+func (c *Cache) Requeue(id string, baseVersion int64, base, data []byte) error {
+	return c.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&OutboxEntry{}).
+			Where("record_id = ?", id).
+			Updates(map[string]interface{}{
+				"op":           OpSave,
+				"base_version": baseVersion,
+				"base_data":    base,
+				"data":         data,
+				"conflict":     false,
+				"theirs_data":  nil,
+				"last_error":   "",
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return putRecord(tx, id, data)
+	})
+}
+
+// Discard отменяет локальное изменение: запись заменяется копией с сервера theirs
+// (nil — запись удалена на сервере и удаляется локально)
+func (c *Cache) Discard(id string, theirs []byte) error {
+	return c.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("record_id = ?", id).Delete(&OutboxEntry{}).Error; err != nil {
+			return err
+		}
+		if theirs == nil {
+			return tx.Where("id = ?", id).Delete(&Record{}).Error
+		}
+		return putRecord(tx, id, theirs)
+	})
+}
+
+// ConflictCount возвращает число изменений, отклонённых из-за конфликта версий
+func (c *Cache) ConflictCount() (int64, error) {
+	var n int64
+	err := c.db.Model(&OutboxEntry{}).Where("conflict = ?", true).Count(&n).Error
+	return n, err
+}
+
+// Conflicts возвращает изменения, отклонённые из-за конфликта версий
+func (c *Cache) Conflicts() ([]OutboxEntry, error) {
+	var entries []OutboxEntry
+	if err := c.db.Where("conflict = ?", true).Order("queued_at, record_id").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Applied — что изменилось в локальной копии после ApplySync
+type Applied struct {
+	Updated []string // ID сохранённых записей
+	Removed []string // ID удалённых записей
+}
+
+// ApplySync применяет изменения с сервера и сохраняет курсор.
+// Записи с неотправленными изменениями не трогаются: сначала должна уйти локальная правка.
+func (c *Cache) ApplySync(changes Changes) (*Applied, error) {
+	applied := &Applied{}
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		var pendingIDs []string
+		if err := tx.Model(&OutboxEntry{}).Pluck("record_id", &pendingIDs).Error; err != nil {
+			return err
+		}
+		pending := make(map[string]bool, len(pendingIDs))
+		for _, id := range pendingIDs {
+			pending[id] = true
+		}
+
+		received := make(map[string]bool, len(changes.Records))
+		for _, r := range changes.Records {
+			received[r.ID] = true
+			if pending[r.ID] {
+				continue
+			}
+			if err := putRecord(tx, r.ID, r.Data); err != nil {
+				return err
+			}
+			applied.Updated = append(applied.Updated, r.ID)
+		}
+
+		stale := make([]string, 0, len(changes.Deleted))
+		for _, id := range changes.Deleted {
+			if !pending[id] {
+				stale = append(stale, id)
+			}
+		}
+		if changes.Full {
+			var localIDs []string
+			if err := tx.Model(&Record{}).Order("id").Pluck("id", &localIDs).Error; err != nil {
+				return err
+			}
+			for _, id := range localIDs {
+				if !received[id] && !pending[id] {
+					stale = append(stale, id)
+				}
+			}
+		}
+		if len(stale) > 0 {
+			if err := tx.Where("id IN ?", stale).Delete(&Record{}).Error; err != nil {
+				return err
+			}
+		}
+		applied.Removed = stale
+
+		if err := setValue(tx, KeySyncedAt, []byte(strconv.FormatInt(time.Now().Unix(), 10))); err != nil {
+			return err
+		}
+		return setValue(tx, KeyCursor, []byte(changes.Cursor))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// Value возвращает служебное значение (nil, если оно не задано)
+func (c *Cache) Value(key string) ([]byte, error) {
+	var e stateEntry
+	if err := c.db.Where("key = ?", key).First(&e).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return e.Value, nil
+}
+
+// SetValue сохраняет служебное значение
+func (c *Cache) SetValue(key string, value []byte) error {
+	return setValue(c.db, key, value)
+}
+
+func setValue(tx *gorm.DB, key string, value []byte) error {
+	return tx.Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&stateEntry{Key: key, Value: value}).Error
+}
+
+// Cursor возвращает курсор последней синхронизации (пусто, если синхронизации не было)
+func (c *Cache) Cursor() (string, error) {
+	v, err := c.Value(KeyCursor)
+	return string(v), err
+}
+
+// SyncedAt возвращает время последней успешной синхронизации (нулевое, если синхронизации не было)
+func (c *Cache) SyncedAt() (time.Time, error) {
+	v, err := c.Value(KeySyncedAt)
+	if err != nil || v == nil {
+		return time.Time{}, err
+	}
+	sec, err := strconv.ParseInt(string(v), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(sec, 0), nil
+}
+
+// Reset очищает кэш полностью (записи, очередь и служебные значения)
+func (c *Cache) Reset() error {
+	return c.db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&Record{}, &OutboxEntry{}, &stateEntry{}} {
+			if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
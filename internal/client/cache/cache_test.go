@@ -0,0 +1,265 @@
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gophkeeper/gophkeeper/internal/client/cache"
+)
+
+func openCache(t *testing.T) *cache.Cache {
+	t.Helper()
+	c, err := cache.Open(filepath.Join(t.TempDir(), "cache", "test.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestOpen_CreatesPrivateFileAndReopens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "test.db")
+	c, err := cache.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := c.PutRecord("r1", []byte("sealed")); err != nil {
+		t.Fatalf("PutRecord: %v", err)
+	}
+	_ = c.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("perm = %o, want 600", perm)
+	}
+
+	c, err = cache.Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer c.Close()
+	r, err := c.Record("r1")
+	if err != nil || string(r.Data) != "sealed" {
+		t.Errorf("Record = %v, %v", r, err)
+	}
+}
+
+func TestPathFor_DependsOnServerAndLogin(t *testing.T) {
+	a := cache.PathFor("/d", "srv:1", "alice")
+	if a != cache.PathFor("/d", "srv:1", "alice") {
+		t.Error("путь должен быть детерминированным")
+	}
+	if a == cache.PathFor("/d", "srv:2", "alice") || a == cache.PathFor("/d", "srv:1", "bob") {
+		t.Error("разные сервер и логин должны давать разные файлы")
+	}
+}
+
+func TestQueueSave_CoalescesEdits(t *testing.T) {
+	c := openCache(t)
+
+	if err := c.QueueSave("r1", 3, []byte("v1")); err != nil {
+		t.Fatalf("QueueSave: %v", err)
+	}
+	if err := c.QueueSave("r1", 4, []byte("v2")); err != nil {
+		t.Fatalf("QueueSave: %v", err)
+	}
+
+	entries, err := c.Outbox()
+	if err != nil {
+		t.Fatalf("Outbox: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("len(outbox) = %d, want 1", len(entries))
+	}
+	if e := entries[0]; e.Op != cache.OpSave || e.BaseVersion != 3 || string(e.Data) != "v2" {
+		t.Errorf("entry = %+v, want save of v2 from base 3", e)
+	}
+	if r, err := c.Record("r1"); err != nil || string(r.Data) != "v2" {
+		t.Errorf("Record = %v, %v, want v2", r, err)
+	}
+}
+
+func TestQueueDelete(t *testing.T) {
+	t.Run("unsent_create_is_dropped", func(t *testing.T) {
+		c := openCache(t)
+		_ = c.QueueSave("r1", 0, []byte("v1"))
+		if err := c.QueueDelete("r1", 0); err != nil {
+			t.Fatalf("QueueDelete: %v", err)
+		}
+		if n, _ := c.PendingCount(); n != 0 {
+			t.Errorf("PendingCount = %d, want 0", n)
+		}
+		if _, err := c.Record("r1"); err != cache.ErrNotFound {
+			t.Errorf("Record err = %v, want ErrNotFound", err)
+		}
+	})
+	t.Run("edit_becomes_delete", func(t *testing.T) {
+		c := openCache(t)
+		_ = c.QueueSave("r1", 2, []byte("v3"))
+		if err := c.QueueDelete("r1", 2); err != nil {
+			t.Fatalf("QueueDelete: %v", err)
+		}
+		entries, _ := c.Outbox()
+		if len(entries) != 1 || entries[0].Op != cache.OpDelete || entries[0].BaseVersion != 2 || entries[0].Data != nil {
+			t.Errorf("outbox = %+v, want single delete from base 2", entries)
+		}
+	})
+	t.Run("delete_clears_conflict", func(t *testing.T) {
+		c := openCache(t)
+		_ = c.QueueSave("r1", 2, []byte("v3"))
+		_ = c.MarkConflict("r1", []byte("theirs"), "conflict")
+		if err := c.QueueDelete("r1", 2); err != nil {
+			t.Fatalf("QueueDelete: %v", err)
+		}
+		if conflicts, _ := c.Conflicts(); len(conflicts) != 0 {
+			t.Errorf("conflicts = %+v, want none", conflicts)
+		}
+		entries, _ := c.Outbox()
+		if len(entries) != 1 || entries[0].Op != cache.OpDelete || entries[0].Conflict ||
+			entries[0].TheirsData != nil || entries[0].LastError != "" {
+			t.Errorf("outbox = %+v, want a plain delete", entries)
+		}
+	})
+}
+
+func TestAckAndMarkConflict(t *testing.T) {
+	c := openCache(t)
+	_ = c.QueueSave("r1", 0, []byte("local"))
+	_ = c.PutRecord("r2", []byte("base"))
+	_ = c.QueueSave("r2", 5, []byte("local"))
+
+	if err := c.Ack("r1", []byte("server")); err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	if err := c.MarkConflict("r2", []byte("theirs"), "conflict"); err != nil {
+		t.Fatalf("MarkConflict: %v", err)
+	}
+
+	if r, _ := c.Record("r1"); string(r.Data) != "server" {
+		t.Errorf("r1 = %q, want server copy", r.Data)
+	}
+	conflicts, _ := c.Conflicts()
+	if len(conflicts) != 1 {
+		t.Fatalf("conflicts = %+v, want r2", conflicts)
+	}
+	if e := conflicts[0]; e.RecordID != "r2" || string(e.BaseData) != "base" || string(e.TheirsData) != "theirs" || e.LastError != "conflict" {
+		t.Errorf("conflict = %+v, want base/theirs snapshots of r2", e)
+	}
+}
+
+func TestRequeueAndDiscard(t *testing.T) {
+	c := openCache(t)
+	_ = c.QueueSave("r1", 1, []byte("mine"))
+	_ = c.MarkConflict("r1", []byte("theirs"), "conflict")
+	_ = c.QueueSave("r2", 1, []byte("mine"))
+	_ = c.MarkConflict("r2", nil, "conflict")
+
+	if err := c.Requeue("r1", 2, []byte("theirs"), []byte("merged")); err != nil {
+		t.Fatalf("Requeue: %v", err)
+	}
+	entries, _ := c.Outbox()
+	if len(entries) != 2 {
+		t.Fatalf("outbox = %+v", entries)
+	}
+	if e := entries[0]; e.Conflict || e.BaseVersion != 2 || string(e.BaseData) != "theirs" || string(e.Data) != "merged" || e.TheirsData != nil {
+		t.Errorf("requeued = %+v, want merged save from base 2", e)
+	}
+	if r, _ := c.Record("r1"); string(r.Data) != "merged" {
+		t.Errorf("r1 = %q, want merged", r.Data)
+	}
+
+	// Запись удалена на сервере: «оставить их версию» удаляет её локально
+	if err := c.Discard("r2", nil); err != nil {
+		t.Fatalf("Discard: %v", err)
+	}
+	if _, err := c.Record("r2"); err != cache.ErrNotFound {
+		t.Errorf("r2 err = %v, want ErrNotFound", err)
+	}
+	if n, _ := c.PendingCount(); n != 1 {
+		t.Errorf("PendingCount = %d, want 1", n)
+	}
+	if err := c.Requeue("missing", 1, nil, []byte("x")); err != cache.ErrNotFound {
+		t.Errorf("Requeue(missing) = %v, want ErrNotFound", err)
+	}
+}
+
+func TestApplySync(t *testing.T) {
+	c := openCache(t)
+	_ = c.PutRecord("kept", []byte("old"))
+	_ = c.PutRecord("removed", []byte("old"))
+	_ = c.PutRecord("missing", []byte("old"))
+	_ = c.QueueSave("pending", 1, []byte("local"))
+
+	applied, err := c.ApplySync(cache.Changes{
+		Records: []cache.Record{
+			{ID: "kept", Data: []byte("new")},
+			{ID: "pending", Data: []byte("server")},
+			{ID: "added", Data: []byte("new")},
+		},
+		Deleted: []string{"removed"},
+		Cursor:  "c1",
+	})
+	if err != nil {
+		t.Fatalf("ApplySync: %v", err)
+	}
+	if !slices.Equal(applied.Updated, []string{"kept", "added"}) || !slices.Equal(applied.Removed, []string{"removed"}) {
+		t.Errorf("applied = %+v, want kept/added updated and removed removed", applied)
+	}
+	if syncedAt, err := c.SyncedAt(); err != nil || time.Since(syncedAt) > time.Minute {
+		t.Errorf("SyncedAt = %v, %v", syncedAt, err)
+	}
+
+	want := map[string]string{"kept": "new", "added": "new", "missing": "old", "pending": "local"}
+	records, _ := c.Records()
+	if len(records) != len(want) {
+		t.Fatalf("records = %d, want %d", len(records), len(want))
+	}
+	for _, r := range records {
+		if want[r.ID] != string(r.Data) {
+			t.Errorf("%s = %q, want %q", r.ID, r.Data, want[r.ID])
+		}
+	}
+	if cursor, _ := c.Cursor(); cursor != "c1" {
+		t.Errorf("Cursor = %q, want c1", cursor)
+	}
+
+	// Полный снимок удаляет записи, которых в нём нет, кроме ожидающих отправки
+	applied, err = c.ApplySync(cache.Changes{
+		Records: []cache.Record{{ID: "kept", Data: []byte("new")}},
+		Full:    true,
+		Cursor:  "c2",
+	})
+	if err != nil {
+		t.Fatalf("ApplySync full: %v", err)
+	}
+	if !slices.Equal(applied.Removed, []string{"added", "missing"}) {
+		t.Errorf("Removed = %v, want added and missing", applied.Removed)
+	}
+	records, _ = c.Records()
+	if len(records) != 2 || records[0].ID != "kept" || records[1].ID != "pending" {
+		t.Errorf("records = %+v, want kept and pending", records)
+	}
+}
+
+func TestReset(t *testing.T) {
+	c := openCache(t)
+	_ = c.PutRecord("r1", []byte("x"))
+	_ = c.QueueDelete("r2", 1)
+	_ = c.SetValue(cache.KeyCursor, []byte("c"))
+
+	if err := c.Reset(); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	records, _ := c.Records()
+	n, _ := c.PendingCount()
+	cursor, _ := c.Cursor()
+	if len(records) != 0 || n != 0 || cursor != "" {
+		t.Errorf("после Reset: records=%d pending=%d cursor=%q", len(records), n, cursor)
+	}
+}
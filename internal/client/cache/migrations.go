@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+//go:embed migrations/*.sql
+var embedMigrations embed.FS
+
+// runMigrations применяет миграции кэша. Как и на сервере, используется отдельное подключение:
+// migrate.Close() закрывает его, не затрагивая соединение GORM.
+func runMigrations(path string) error {
+	sourceDriver, err := iofs.New(embedMigrations, "migrations")
+	if err != nil {
+		return fmt.Errorf("create migration source: %w", err)
+	}
+
+	migrateConn, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return fmt.Errorf("open migration connection: %w", err)
+	}
+
+	driver, err := sqlite3.WithInstance(migrateConn, &sqlite3.Config{})
+	if err != nil {
+		_ = migrateConn.Close()
+		return fmt.Errorf("create sqlite driver: %w", err)
+	}
+	m, err := migrate.NewWithInstance("iofs", sourceDriver, "sqlite3", driver)
+	if err != nil {
+		return fmt.Errorf("create migrate instance: %w", err)
+	}
+	defer m.Close()
+
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("run cache migrations up: %w", err)
+	}
+	return nil
+}
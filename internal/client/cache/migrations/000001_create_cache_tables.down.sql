@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS state;
+DROP INDEX IF EXISTS idx_outbox_queued_at;
+DROP TABLE IF EXISTS outbox;
+DROP TABLE IF EXISTS records;
@@ -0,0 +1,25 @@
+-- Локальная копия записей: содержимое каждой строки (proto.Data целиком) зашифровано мастер-ключом
+CREATE TABLE IF NOT EXISTS records (
+    id TEXT PRIMARY KEY,
+    data BLOB NOT NULL,
+    updated_at DATETIME
+);
+
+-- Очередь изменений, сделанных без связи с сервером (одна запись очереди на запись данных)
+CREATE TABLE IF NOT EXISTS outbox (
+    record_id TEXT PRIMARY KEY,
+    op TEXT NOT NULL,
+    base_version INTEGER NOT NULL DEFAULT 0,
+    data BLOB,
+    conflict BOOLEAN NOT NULL DEFAULT FALSE,
+    last_error TEXT,
+    queued_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_queued_at ON outbox(queued_at);
+
+-- Служебные значения: курсор синхронизации, блок проверки мастер-пароля
+CREATE TABLE IF NOT EXISTS state (
+    key TEXT PRIMARY KEY,
+    value BLOB
+);
@@ -0,0 +1,2 @@
+ALTER TABLE outbox DROP COLUMN theirs_data;
+ALTER TABLE outbox DROP COLUMN base_data;
@@ -0,0 +1,3 @@
+-- Версии записи для разрешения конфликта: от которой начиналась правка и актуальная на сервере
+ALTER TABLE outbox ADD COLUMN base_data BLOB;
+ALTER TABLE outbox ADD COLUMN theirs_data BLOB;
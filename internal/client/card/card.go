@@ -0,0 +1,266 @@
+// Package card проверяет данные банковских карт: номер по алгоритму Луна, платёжную систему
+// по префиксу номера с её длинами номера и CVV, срок действия в формате MM/YY.
+package card
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Brand — платёжная система карты
+type Brand int
+
+const (
+	BrandUnknown Brand = iota
+	BrandVisa
+	BrandMastercard
+	BrandMir
+	BrandAmex
+)
+
+func (b Brand) String() string {
+	switch b {
+	case BrandVisa:
+		return "Visa"
+	case BrandMastercard:
+		return "Mastercard"
+	case BrandMir:
+		return "Mir"
+	case BrandAmex:
+		return "American Express"
+	}
+	return "unknown"
+}
+
+// brandRule — префиксы номера платёжной системы (диапазоны включительно) и допустимые длины номера и CVV
+type brandRule struct {
+	brand   Brand
+	ranges  [][2]int // первые цифры номера; у границ диапазона одинаковое число цифр
+	lengths []int
+	cvv     int
+}
+
+var brandRules = []brandRule{
+	{BrandAmex, [][2]int{{34, 34}, {37, 37}}, []int{15}, 4},
+	{BrandMir, [][2]int{{2200, 2204}}, []int{16, 17, 18, 19}, 3},
+	{BrandMastercard, [][2]int{{51, 55}, {2221, 2720}}, []int{16}, 3},
+	{BrandVisa, [][2]int{{4, 4}}, []int{13, 16, 19}, 3},
+}
+
+// Длины номера карты неизвестной платёжной системы (ISO/IEC 7812)
+const (
+	MinNumberLength = 12
+	MaxNumberLength = 19
+)
+
+var (
+	// ErrNoNumber — номер карты не указан
+	ErrNoNumber = errors.New("card number is empty")
+	// ErrNumberChar — в номере есть символ кроме цифр, пробелов и дефисов
+	ErrNumberChar = errors.New("invalid character in card number")
+	// ErrLuhn — номер не проходит проверку по алгоритму Луна (опечатка в номере)
+	ErrLuhn = errors.New("invalid card number check digit")
+	// ErrCVVDigits — в CVV есть символы кроме цифр
+	ErrCVVDigits = errors.New("CVV must contain only digits")
+	// ErrExpiryFormat — срок действия не в формате MM/YY
+	ErrExpiryFormat = errors.New("expiry must be MM/YY")
+)
+
+// NumberLengthError — длина номера не подходит платёжной системе Brand (Lengths — допустимые длины)
+// или, если система не определена, выходит за MinNumberLength–MaxNumberLength
+type NumberLengthError struct {
+	Brand   Brand
+	Lengths []int
+	Got     int
+}
+
+func (e *NumberLengthError) Error() string {
+	if e.Brand == BrandUnknown {
+		return fmt.Sprintf("card number must have %d-%d digits, got %d", MinNumberLength, MaxNumberLength, e.Got)
+	}
+	return fmt.Sprintf("%s card number must have %s digits, got %d", e.Brand, joinInts(e.Lengths), e.Got)
+}
+
+// CVVLengthError — длина CVV не подходит платёжной системе Brand (Length цифр) или, если система
+// не определена, не равна трём или четырём
+type CVVLengthError struct {
+	Brand  Brand
+	Length int
+}
+
+func (e *CVVLengthError) Error() string {
+	if e.Brand == BrandUnknown {
+		return "CVV must have 3 or 4 digits"
+	}
+	return fmt.Sprintf("%s CVV must have %d digits", e.Brand, e.Length)
+}
+
+// Digits возвращает цифры номера без пробелов и дефисов; другие символы — ошибка
+func Digits(number string) (string, error) {
+	var b strings.Builder
+	for _, r := range number {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ' || r == '-':
+		default:
+			return "", fmt.Errorf("%w %q", ErrNumberChar, r)
+		}
+	}
+	if b.Len() == 0 {
+		return "", ErrNoNumber
+	}
+	return b.String(), nil
+}
+
+// Detect определяет платёжную систему по первым цифрам номера (пробелы и дефисы не важны)
+func Detect(number string) Brand {
+	if rule := detectRule(number); rule != nil {
+		return rule.brand
+	}
+	return BrandUnknown
+}
+
+func detectRule(number string) *brandRule {
+	digits, err := Digits(number)
+	if err != nil {
+		return nil
+	}
+	for i := range brandRules {
+		for _, r := range brandRules[i].ranges {
+			width := len(strconv.Itoa(r[0]))
+			if len(digits) < width {
+				continue
+			}
+			prefix, _ := strconv.Atoi(digits[:width])
+			if prefix >= r[0] && prefix <= r[1] {
+				return &brandRules[i]
+			}
+		}
+	}
+	return nil
+}
+
+// Luhn проверяет контрольную цифру номера из одних цифр
+func Luhn(digits string) bool {
+	sum := 0
+	for i := range len(digits) {
+		d := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// ValidateNumber проверяет номер карты: только цифры (пробелы и дефисы допускаются),
+// длина по платёжной системе и контрольная цифра по алгоритму Луна
+func ValidateNumber(number string) error {
+	digits, err := Digits(number)
+	if err != nil {
+		return err
+	}
+	if rule := detectRule(digits); rule != nil {
+		if !slices.Contains(rule.lengths, len(digits)) {
+			return &NumberLengthError{Brand: rule.brand, Lengths: rule.lengths, Got: len(digits)}
+		}
+	} else if len(digits) < MinNumberLength || len(digits) > MaxNumberLength {
+		return &NumberLengthError{Brand: BrandUnknown, Got: len(digits)}
+	}
+	if !Luhn(digits) {
+		return ErrLuhn
+	}
+	return nil
+}
+
+// ValidateCVV проверяет код CVV для карты с номером number: три цифры, у American Express — четыре.
+// Для неизвестной платёжной системы подходят три или четыре цифры.
+func ValidateCVV(number, cvv string) error {
+	cvv = strings.TrimSpace(cvv)
+	if cvv == "" || strings.Trim(cvv, "0123456789") != "" {
+		return ErrCVVDigits
+	}
+	if rule := detectRule(number); rule != nil {
+		if len(cvv) != rule.cvv {
+			return &CVVLengthError{Brand: rule.brand, Length: rule.cvv}
+		}
+		return nil
+	}
+	if len(cvv) != 3 && len(cvv) != 4 {
+		return &CVVLengthError{Brand: BrandUnknown}
+	}
+	return nil
+}
+
+// ParseExpiry разбирает срок действия MM/YY (допускаются MM/YYYY и пробелы вокруг /) и возвращает
+// момент, когда карта перестаёт действовать: начало следующего месяца в UTC
+func ParseExpiry(s string) (time.Time, error) {
+	month, year, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return time.Time{}, ErrExpiryFormat
+	}
+	month, year = strings.TrimSpace(month), strings.TrimSpace(year)
+	m, err := strconv.Atoi(month)
+	if err != nil || len(month) > 2 || m < 1 || m > 12 {
+		return time.Time{}, ErrExpiryFormat
+	}
+	y, err := strconv.Atoi(year)
+	if err != nil || y < 0 {
+		return time.Time{}, ErrExpiryFormat
+	}
+	switch len(year) {
+	case 2:
+		y += 2000
+	case 4:
+	default:
+		return time.Time{}, ErrExpiryFormat
+	}
+	return time.Date(y, time.Month(m)+1, 1, 0, 0, 0, 0, time.UTC), nil
+}
+
+// Expired сообщает, истёк ли к моменту now срок действия expiry (MM/YY). Неразобранный срок не считается истёкшим.
+func Expired(expiry string, now time.Time) bool {
+	end, err := ParseExpiry(expiry)
+	return err == nil && !now.Before(end)
+}
+
+// FormatNumber разбивает номер на группы по четыре цифры (у American Express — 4-6-5)
+func FormatNumber(number string) string {
+	digits, err := Digits(number)
+	if err != nil {
+		return number
+	}
+	groups := []int{4, 4, 4, 4, 4}
+	if Detect(digits) == BrandAmex {
+		groups = []int{4, 6, 5}
+	}
+	var parts []string
+	for _, n := range groups {
+		if len(digits) == 0 {
+			break
+		}
+		n = min(n, len(digits))
+		parts = append(parts, digits[:n])
+		digits = digits[n:]
+	}
+	if digits != "" {
+		parts = append(parts, digits)
+	}
+	return strings.Join(parts, " ")
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ", ")
+}
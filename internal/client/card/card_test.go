@@ -0,0 +1,148 @@
+package card_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gophkeeper/gophkeeper/internal/client/card"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		number string
+		want   card.Brand
+	}{
+		{"4111 1111 1111 1111", card.BrandVisa},
+		{"5500-0000-0000-0004", card.BrandMastercard},
+		{"2221000000000009", card.BrandMastercard},
+		{"2720999999999996", card.BrandMastercard},
+		{"2200000000000004", card.BrandMir},
+		{"2204999999999999", card.BrandMir},
+		{"378282246310005", card.BrandAmex},
+		{"341111111111111", card.BrandAmex},
+		{"6011111111111117", card.BrandUnknown},
+		{"2205000000000000", card.BrandUnknown},
+		{"abc", card.BrandUnknown},
+	}
+	for _, tt := range tests {
+		if got := card.Detect(tt.number); got != tt.want {
+			t.Errorf("Detect(%q) = %s, want %s", tt.number, got, tt.want)
+		}
+	}
+}
+
+func TestValidateNumber(t *testing.T) {
+	valid := []string{
+		"4111 1111 1111 1111",
+		"4222222222222",
+		"5555555555554444",
+		"2200 0000 0000 0004",
+		"378282246310005",
+		"6011111111111117",
+	}
+	for _, number := range valid {
+		if err := card.ValidateNumber(number); err != nil {
+			t.Errorf("ValidateNumber(%q): %v", number, err)
+		}
+	}
+
+	if err := card.ValidateNumber("4111 1111 1111 1112"); !errors.Is(err, card.ErrLuhn) {
+		t.Errorf("wrong check digit: %v, want ErrLuhn", err)
+	}
+	for number, want := range map[string]error{
+		"":                    card.ErrNoNumber,
+		"4111 1111 1111 111x": card.ErrNumberChar,
+	} {
+		if err := card.ValidateNumber(number); !errors.Is(err, want) {
+			t.Errorf("ValidateNumber(%q) = %v, want %v", number, err, want)
+		}
+	}
+	invalidLength := map[string]card.Brand{
+		"37828224631000":       card.BrandAmex,       // Amex: 15 цифр
+		"555555555555444":      card.BrandMastercard, // Mastercard: 16 цифр
+		"60111111111":          card.BrandUnknown,    // слишком короткий
+		"60111111111111111111": card.BrandUnknown,    // слишком длинный
+	}
+	for number, brand := range invalidLength {
+		var lengthErr *card.NumberLengthError
+		if err := card.ValidateNumber(number); !errors.As(err, &lengthErr) || lengthErr.Brand != brand || lengthErr.Got != len(number) {
+			t.Errorf("ValidateNumber(%q) = %v, want NumberLengthError for %v", number, err, brand)
+		}
+	}
+}
+
+func TestValidateCVV(t *testing.T) {
+	tests := []struct {
+		number, cvv string
+		ok          bool
+	}{
+		{"4111111111111111", "123", true},
+		{"4111111111111111", "1234", false},
+		{"378282246310005", "1234", true},
+		{"378282246310005", "123", false},
+		{"6011111111111117", "123", true},
+		{"6011111111111117", "1234", true},
+		{"6011111111111117", "12", false},
+		{"4111111111111111", "12a", false},
+		{"4111111111111111", "", false},
+	}
+	for _, tt := range tests {
+		if err := card.ValidateCVV(tt.number, tt.cvv); (err == nil) != tt.ok {
+			t.Errorf("ValidateCVV(%q, %q): %v", tt.number, tt.cvv, err)
+		}
+	}
+}
+
+func TestParseExpiry(t *testing.T) {
+	tests := map[string]time.Time{
+		"12/25":      time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		"3/27":       time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC),
+		" 07 / 2030": time.Date(2030, time.August, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for s, want := range tests {
+		got, err := card.ParseExpiry(s)
+		if err != nil {
+			t.Errorf("ParseExpiry(%q): %v", s, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("ParseExpiry(%q) = %v, want %v", s, got, want)
+		}
+	}
+	for _, s := range []string{"", "1225", "13/25", "00/25", "12/5", "12/-5", "ab/cd"} {
+		if _, err := card.ParseExpiry(s); !errors.Is(err, card.ErrExpiryFormat) {
+			t.Errorf("ParseExpiry(%q): %v, want ErrExpiryFormat", s, err)
+		}
+	}
+}
+
+func TestExpired(t *testing.T) {
+	now := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+	tests := map[string]bool{
+		"09/26": true,
+		"10/26": false, // карта действует до конца месяца
+		"11/26": false,
+		"12/20": true,
+		"bad":   false,
+	}
+	for expiry, want := range tests {
+		if got := card.Expired(expiry, now); got != want {
+			t.Errorf("Expired(%q) = %v, want %v", expiry, got, want)
+		}
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := map[string]string{
+		"4111111111111111":    "4111 1111 1111 1111",
+		"378282246310005":     "3782 822463 10005",
+		"2200-0000-0000-0004": "2200 0000 0000 0004",
+		"not a number":        "not a number",
+	}
+	for number, want := range tests {
+		if got := card.FormatNumber(number); got != want {
+			t.Errorf("FormatNumber(%q) = %q, want %q", number, got, want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+// Package cli реализует команды клиента без TUI для сценариев и скриптов: вход, список, чтение,
+// добавление, изменение и удаление записей, синхронизацию и агент SSH. Команды печатают результат
+// в stdout (с флагом -json — в формате JSON), сообщения и ошибки — в stderr, и завершаются
+// с постоянными кодами выхода Exit*.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gophkeeper/gophkeeper/internal/client"
+	"github.com/gophkeeper/gophkeeper/internal/client/replica"
+	"github.com/gophkeeper/gophkeeper/internal/config"
+)
+
+// Version и BuildDate — версия и дата сборки клиента для команды version; их задаёт main
+var (
+	Version   = "dev"
+	BuildDate = "unknown"
+)
+
+// Коды выхода команд. Значения не меняются: на них опираются скрипты.
+const (
+	ExitOK = 0
+	// ExitError — прочие ошибки
+	ExitError = 1
+	// ExitUsage — неизвестная команда, неверные флаги или значения полей, не заданы учётные данные
+	ExitUsage = 2
+	// ExitAuth — неверный логин, пароль или мастер-пароль
+	ExitAuth = 3
+	// ExitNotFound — запись не найдена или названию соответствует несколько записей
+	ExitNotFound = 4
+	// ExitConflict — запись изменили на другом устройстве; правка осталась в очереди до разрешения конфликта в TUI
+	ExitConflict = 5
+	// ExitUnavailable — нет связи с сервером, а команде она нужна
+	ExitUnavailable = 6
+)
+
+// exitError — ошибка с кодом выхода
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+func usageErrorf(format string, args ...any) error {
+	return &exitError{code: ExitUsage, err: fmt.Errorf(format, args...)}
+}
+
+// ExitCode возвращает код выхода для ошибки команды
+func ExitCode(err error) int {
+	var exitErr *exitError
+	var conflict *replica.ConflictError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &exitErr):
+		return exitErr.code
+	case errors.As(err, &conflict):
+		return ExitConflict
+	case client.IsUnavailable(err):
+		return ExitUnavailable
+	}
+	return ExitError
+}
+
+// env — окружение команды
+type env struct {
+	cfg    *config.ClientConfig
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// command — подкоманда клиента
+type command struct {
+	usage   string // аргументы после имени команды
+	summary string
+	run     func(e *env, args []string) error
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"login": {"[-json]", "войти и обновить локальную копию", runLogin},
+		"list": {"[-type тип] [-search текст] [-tag тег]... [-key ключ]... [-since дата] [-json]",
+			"список записей или поиск на сервере", runList},
+		"get": {"<id|название> [-field поле] [-output каталог] [-json]",
+			"показать запись, одно её поле или сохранить прикреплённый файл", runGet},
+		"add": {"-type тип -name название [-field поле=значение]... [-field-file поле=путь]... [-meta ключ=значение]... [-file путь] [-json]",
+			"добавить запись", runAdd},
+		"edit": {"<id|название> [-name название] [-field поле=значение]... [-field-file поле=путь]... [-meta ключ=значение]... [-unset-meta ключ]... [-file путь] [-json]",
+			"изменить запись", runEdit},
+		"rm":        {"<id|название> [-json]", "удалить запись", runRemove},
+		"sync":      {"[-json]", "отправить изменения из очереди и получить изменения с сервера", runSync},
+		"ssh-agent": {"[-socket путь]", "отдавать ключи SSH через сокет ssh-agent", runSSHAgent},
+		"version":   {"", "показать версию и дату сборки", runVersion},
+	}
+}
+
+// Run выполняет команду args[0] с аргументами args[1:] и возвращает код выхода
+func Run(cfg *config.ClientConfig, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stdout)
+		return ExitOK
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "Error: неизвестная команда %q\n\n", args[0])
+		printUsage(stderr)
+		return ExitUsage
+	}
+	e := &env{cfg: cfg, stdin: os.Stdin, stdout: stdout, stderr: stderr}
+	err := cmd.run(e, args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return ExitOK
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return ExitCode(err)
+	}
+	return ExitOK
+}
+
+func printUsage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(w, "Использование: client [флаги] [команда] [аргументы]")
+	fmt.Fprintln(w, "Без команды запускается TUI. Команды:")
+	for _, name := range names {
+		cmd := commands[name]
+		fmt.Fprintf(w, "  %s %s\n        %s\n", name, cmd.usage, cmd.summary)
+	}
+	fmt.Fprintf(w, "Учётные данные: %s, %s, %s или ввод в терминале.\n", envLogin, envPassword, envMasterPassword)
+	fmt.Fprintln(w, "Коды выхода: 0 — успех, 1 — ошибка, 2 — неверные аргументы, 3 — ошибка входа,")
+	fmt.Fprintln(w, "4 — запись не найдена, 5 — конфликт версий, 6 — нет связи с сервером.")
+}
+
+// newFlagSet создаёт набор флагов команды name; ошибки разбора возвращает parseArgs, а выводит Run
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseArgs разбирает флаги команды вперемешку с позиционными аргументами (get <id> -field password)
+// и возвращает позиционные аргументы. После «--» все аргументы позиционные.
+func parseArgs(e *env, fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional, rest []string
+	for i, arg := range args {
+		if arg == "--" {
+			args, rest = args[:i], args[i+1:]
+			break
+		}
+	}
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintf(e.stdout, "Использование: client %s %s\n", fs.Name(), commands[fs.Name()].usage)
+				fs.SetOutput(e.stdout)
+				fs.PrintDefaults()
+				return nil, err
+			}
+			return nil, usageErrorf("%s: %v", fs.Name(), err)
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	return append(positional, rest...), nil
+}
+
+// listFlag — повторяемый флаг (-field a=1 -field b=2)
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *listFlag) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+// splitAssignment разбирает «ключ=значение»
+func splitAssignment(s string) (key, value string, err error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || strings.TrimSpace(key) == "" {
+		return "", "", usageErrorf("ожидается ключ=значение: %q", s)
+	}
+	return strings.TrimSpace(key), value, nil
+}
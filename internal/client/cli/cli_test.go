@@ -0,0 +1,129 @@
+package cli_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gophkeeper/gophkeeper/internal/client/cli"
+	"github.com/gophkeeper/gophkeeper/internal/client/replica"
+	"github.com/gophkeeper/gophkeeper/internal/config"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func run(t *testing.T, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	code = cli.Run(&config.ClientConfig{Server: "localhost:0", CacheDir: t.TempDir()}, args, &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestRunHelp(t *testing.T) {
+	for _, args := range [][]string{{"help"}, {"--help"}} {
+		code, stdout, _ := run(t, args...)
+		if code != cli.ExitOK {
+			t.Errorf("Run(%v) = %d, want %d", args, code, cli.ExitOK)
+		}
+		if !strings.Contains(stdout, "get <id|название>") {
+			t.Errorf("Run(%v) usage does not list commands:\n%s", args, stdout)
+		}
+	}
+
+	code, stdout, _ := run(t, "get", "-h")
+	if code != cli.ExitOK || !strings.Contains(stdout, "-field") {
+		t.Errorf("get -h = %d, %q; want flag help", code, stdout)
+	}
+}
+
+// Ошибки аргументов обнаруживаются до входа на сервер, поэтому сервер в тестах не нужен
+func TestRunUsageErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"unknown command", []string{"frobnicate"}, "неизвестная команда"},
+		{"unknown flag", []string{"list", "--color"}, "flag provided but not defined"},
+		{"unknown type", []string{"list", "--type", "note"}, "неизвестный тип"},
+		{"extra argument", []string{"sync", "now"}, "лишние аргументы"},
+		{"list bad since", []string{"list", "--tag", "work", "--since", "вчера"}, "-since"},
+		{"get without record", []string{"get", "--field", "password"}, "укажите один ID"},
+		{"get two records", []string{"get", "a", "b"}, "укажите один ID"},
+		{"get field and output", []string{"get", "a", "--field", "password", "--output", "."}, "несовместимы"},
+		{"add without type", []string{"add", "--name", "db"}, "-type"},
+		{"add without name", []string{"add", "--type", "login"}, "-name"},
+		{"add unknown field", []string{"add", "--type", "login", "--name", "db", "--field", "pin=1"}, `нет поля "pin"`},
+		{"add bad assignment", []string{"add", "--type", "login", "--name", "db", "--field", "password"}, "ключ=значение"},
+		{"add invalid card", []string{"add", "--type", "card", "--name", "c", "--field", "number=4111111111111112"}, "number:"},
+		{"add invalid cvv", []string{"add", "--type", "card", "--name", "c",
+			"--field", "number=4111111111111111", "--field", "expiry=12/30", "--field", "cvv=12"}, "cvv:"},
+		{"add file to login", []string{"add", "--type", "login", "--name", "db", "--file", "x"}, "нельзя прикрепить файл"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := run(t, tt.args...)
+			if code != cli.ExitUsage {
+				t.Errorf("Run(%v) = %d, want %d; stderr: %s", tt.args, code, cli.ExitUsage, stderr)
+			}
+			if !strings.Contains(stderr, tt.want) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr, tt.want)
+			}
+			if stdout != "" {
+				t.Errorf("stdout = %q, want empty", stdout)
+			}
+		})
+	}
+}
+
+func TestRunMissingCredentials(t *testing.T) {
+	t.Setenv("GOPHKEEPER_LOGIN", "")
+	// В тестах stdin — не терминал, поэтому учётные данные не запрашиваются
+	code, _, stderr := run(t, "list", "--json")
+	if code != cli.ExitUsage || !strings.Contains(stderr, "GOPHKEEPER_LOGIN") {
+		t.Errorf("list without credentials = %d, %q; want %d and the variable name", code, stderr, cli.ExitUsage)
+	}
+}
+
+func TestRunVersion(t *testing.T) {
+	code, stdout, _ := run(t, "version")
+	if code != cli.ExitOK || !strings.Contains(stdout, "Version: ") {
+		t.Errorf("version = %d, %q; want the version banner", code, stdout)
+	}
+}
+
+// «version» после команды — название записи или значение флага, а не запрос версии
+func TestRunVersionAsArgument(t *testing.T) {
+	t.Setenv("GOPHKEEPER_LOGIN", "")
+	for _, args := range [][]string{
+		{"get", "version", "--field", "password"},
+		{"add", "--type", "text", "--name", "version"},
+	} {
+		code, stdout, _ := run(t, args...)
+		if code == cli.ExitOK || strings.Contains(stdout, "Version: ") {
+			t.Errorf("Run(%v) = %d, %q; want the command to run, not the version banner", args, code, stdout)
+		}
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, cli.ExitOK},
+		{"other", errors.New("boom"), cli.ExitError},
+		{"conflict", fmt.Errorf("save: %w", &replica.ConflictError{Conflict: &replica.Conflict{}}), cli.ExitConflict},
+		{"unavailable", status.Error(codes.Unavailable, "connection refused"), cli.ExitUnavailable},
+		{"deadline", status.Error(codes.DeadlineExceeded, "timeout"), cli.ExitUnavailable},
+		{"internal", status.Error(codes.Internal, "db"), cli.ExitError},
+	}
+	for _, tt := range tests {
+		if got := cli.ExitCode(tt.err); got != tt.want {
+			t.Errorf("%s: ExitCode(%v) = %d, want %d", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
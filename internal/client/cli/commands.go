@@ -0,0 +1,769 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gophkeeper/gophkeeper/internal/client/format"
+	"github.com/gophkeeper/gophkeeper/proto"
+	pb "google.golang.org/protobuf/proto"
+)
+
+// record — запись в выводе -json. Fields заполняется только командой get.
+type record struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Type      string            `json:"type"`
+	Version   int64             `json:"version"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	BlobID    string            `json:"blob_id,omitempty"`
+	// Queued — изменение сохранено локально и будет отправлено при синхронизации (add, edit, rm)
+	Queued bool `json:"queued,omitempty"`
+}
+
+func newRecord(d *proto.Data) record {
+	r := record{
+		ID:        d.Id,
+		Name:      d.Name,
+		Type:      typeKey(d.Type),
+		Version:   d.Version,
+		CreatedAt: time.Unix(d.CreatedAt, 0).UTC(),
+		UpdatedAt: time.Unix(d.UpdatedAt, 0).UTC(),
+		BlobID:    d.BlobId,
+	}
+	if len(d.Metadata) > 0 {
+		r.Metadata = make(map[string]string, len(d.Metadata))
+		for _, md := range d.Metadata {
+			r.Metadata[md.Key] = md.Value
+		}
+	}
+	return r
+}
+
+// typeKey возвращает имя типа записи в командной строке и JSON
+func typeKey(dt proto.DataType) string {
+	if rt := format.LookupRecordType(dt); rt != nil {
+		return rt.Key
+	}
+	return strings.ToLower(dt.String())
+}
+
+// lookupType возвращает тип записи по имени из -type
+func lookupType(key string) (*format.RecordType, error) {
+	if rt := format.LookupRecordTypeKey(key); rt != nil {
+		return rt, nil
+	}
+	keys := make([]string, 0, len(format.RecordTypes()))
+	for _, rt := range format.RecordTypes() {
+		keys = append(keys, rt.Key)
+	}
+	return nil, usageErrorf("неизвестный тип %q, допустимые: %s", key, strings.Join(keys, ", "))
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// noArgs проверяет, что у команды нет позиционных аргументов
+func noArgs(positional []string) error {
+	if len(positional) > 0 {
+		return usageErrorf("лишние аргументы: %s", strings.Join(positional, " "))
+	}
+	return nil
+}
+
+// oneArg возвращает единственный позиционный аргумент — ID или название записи
+func oneArg(positional []string) (string, error) {
+	if len(positional) != 1 {
+		return "", usageErrorf("укажите один ID или название записи")
+	}
+	return positional[0], nil
+}
+
+// findRecord ищет запись по ID, затем по названию (сначала точному, затем без учёта регистра).
+// Если названию соответствует несколько записей, нужно указать ID.
+func findRecord(records []*proto.Data, ref string) (*proto.Data, error) {
+	for _, d := range records {
+		if d.Id == ref {
+			return d, nil
+		}
+	}
+	for _, equal := range []func(a, b string) bool{
+		func(a, b string) bool { return a == b },
+		strings.EqualFold,
+	} {
+		var found []*proto.Data
+		for _, d := range records {
+			if equal(d.Name, ref) {
+				found = append(found, d)
+			}
+		}
+		switch len(found) {
+		case 0:
+			continue
+		case 1:
+			return found[0], nil
+		}
+		ids := make([]string, len(found))
+		for i, d := range found {
+			ids[i] = d.Id
+		}
+		return nil, &exitError{code: ExitNotFound,
+			err: fmt.Errorf("названию %q соответствует несколько записей, укажите ID: %s", ref, strings.Join(ids, ", "))}
+	}
+	return nil, &exitError{code: ExitNotFound, err: fmt.Errorf("запись %q не найдена", ref)}
+}
+
+// loadRecord открывает сеанс и находит запись ref; сеанс закрывает вызывающий
+func loadRecord(e *env, ref string) (*session, *proto.Data, error) {
+	s, err := openSession(e.cfg, e.stderr)
+	if err != nil {
+		return nil, nil, err
+	}
+	records, err := s.replica.List()
+	if err == nil {
+		var d *proto.Data
+		if d, err = findRecord(records, ref); err == nil {
+			return s, d, nil
+		}
+	}
+	_ = s.Close()
+	return nil, nil, err
+}
+
+// decodeFields расшифровывает содержимое записи и раскладывает его на поля
+func (s *session) decodeFields(d *proto.Data) (map[string]string, error) {
+	payload, err := s.client.Vault().Decrypt(d.EncryptedData)
+	if err != nil {
+		return nil, fmt.Errorf("расшифровка: %w", err)
+	}
+	fields, err := format.ParsePayload(d.Type, payload)
+	if err != nil {
+		return nil, fmt.Errorf("декодирование: %w", err)
+	}
+	return fields, nil
+}
+
+// requireServer проверяет связь с сервером для команд с прикреплёнными файлами: файлы не хранятся в локальной копии
+func (s *session) requireServer() error {
+	if s.replica.Offline() {
+		return &exitError{code: ExitUnavailable, err: errors.New("для работы с файлом нужна связь с сервером")}
+	}
+	return nil
+}
+
+// attachFile загружает файл path на сервер (см. client.Client.AttachFile)
+func (s *session) attachFile(path string) (fields map[string]string, blobID string, err error) {
+	err = s.replica.Do(func() (err error) {
+		fields, blobID, err = s.client.AttachFile(path)
+		return err
+	})
+	return fields, blobID, err
+}
+
+// runLogin — команда login: вход, открытие хранилища и синхронизация локальной копии
+func runLogin(e *env, args []string) error {
+	fs := newFlagSet("login")
+	asJSON := fs.Bool("json", false, "Print the result as JSON")
+	positional, err := parseArgs(e, fs, args)
+	if err != nil {
+		return err
+	}
+	if err := noArgs(positional); err != nil {
+		return err
+	}
+
+	s, err := openSession(e.cfg, e.stderr)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	records, err := s.replica.List()
+	if err != nil {
+		return err
+	}
+	pending, err := s.replica.PendingCount()
+	if err != nil {
+		return err
+	}
+	result := struct {
+		Login   string `json:"login"`
+		Offline bool   `json:"offline"`
+		Records int    `json:"records"`
+		Pending int64  `json:"pending"`
+	}{s.login, s.replica.Offline(), len(records), pending}
+	if *asJSON {
+		return writeJSON(e.stdout, result)
+	}
+	mode := "с сервером"
+	if result.Offline {
+		mode = "без связи с сервером, по локальной копии"
+	}
+	fmt.Fprintf(e.stdout, "Вход выполнен (%s): %s, записей — %d, в очереди — %d\n", mode, result.Login, result.Records, result.Pending)
+	return nil
+}
+
+// runList — команда list: записи, при -type — только одного типа. С -search, -tag, -key или -since
+// записи ищутся на сервере (SearchData)
+func runList(e *env, args []string) error {
+	fs := newFlagSet("list")
+	typeKey := fs.String("type", "", "Show only records of this type")
+	search := fs.String("search", "", "Search the server for names containing this text")
+	var tags, keys listFlag
+	fs.Var(&tags, "tag", "Search the server for records with this tag (repeatable)")
+	fs.Var(&keys, "key", "Search the server for records with this metadata key (repeatable)")
+	since := fs.String("since", "", "Search the server for records changed since this date (YYYY-MM-DD or RFC 3339)")
+	asJSON := fs.Bool("json", false, "Print records as JSON")
+	positional, err := parseArgs(e, fs, args)
+	if err != nil {
+		return err
+	}
+	if err := noArgs(positional); err != nil {
+		return err
+	}
+	var rt *format.RecordType
+	if *typeKey != "" {
+		if rt, err = lookupType(*typeKey); err != nil {
+			return err
+		}
+	}
+	var req *proto.SearchDataRequest
+	if *search != "" || len(tags) > 0 || len(keys) > 0 || *since != "" {
+		req = &proto.SearchDataRequest{NameContains: *search, Tags: tags, MetadataKeys: keys, Sort: proto.DataSortField_SORT_NAME}
+		if rt != nil {
+			req.Types = []proto.DataType{rt.Type}
+		}
+		if *since != "" {
+			t, err := parseSince(*since)
+			if err != nil {
+				return err
+			}
+			req.UpdatedSince = t.Unix()
+		}
+	}
+
+	s, err := openSession(e.cfg, e.stderr)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	var records []*proto.Data
+	if req != nil {
+		if s.replica.Offline() {
+			return &exitError{code: ExitUnavailable, err: errors.New("для поиска нужна связь с сервером")}
+		}
+		records, err = s.replica.Search(req)
+	} else {
+		records, err = s.replica.List()
+	}
+	if err != nil {
+		return err
+	}
+	list := make([]record, 0, len(records))
+	for _, d := range records {
+		if rt == nil || d.Type == rt.Type {
+			list = append(list, newRecord(d))
+		}
+	}
+	if *asJSON {
+		return writeJSON(e.stdout, list)
+	}
+	tw := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTYPE\tNAME\tUPDATED")
+	for _, r := range list {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.ID, r.Type, r.Name, r.UpdatedAt.Local().Format("2006-01-02 15:04"))
+	}
+	return tw.Flush()
+}
+
+// parseSince разбирает значение -since: дату в местном времени или время в RFC 3339
+func parseSince(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, usageErrorf("-since: ожидается дата ГГГГ-ММ-ДД или время RFC 3339, получено %q", value)
+	}
+	return t, nil
+}
+
+// runGet — команда get: запись целиком, значение одного поля (-field) или прикреплённый файл (-output)
+func runGet(e *env, args []string) error {
+	fs := newFlagSet("get")
+	field := fs.String("field", "", "Print only the value of this field")
+	output := fs.String("output", "", "Save the attached file to this directory")
+	asJSON := fs.Bool("json", false, "Print the record as JSON")
+	positional, err := parseArgs(e, fs, args)
+	if err != nil {
+		return err
+	}
+	ref, err := oneArg(positional)
+	if err != nil {
+		return err
+	}
+	if *field != "" && *output != "" {
+		return usageErrorf("флаги -field и -output несовместимы")
+	}
+
+	s, d, err := loadRecord(e, ref)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	if *output != "" {
+		if err := s.requireServer(); err != nil {
+			return err
+		}
+		var path string
+		err := s.replica.Do(func() (err error) {
+			path, err = s.client.ExportFile(d, *output)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return writeJSON(e.stdout, struct {
+				Path string `json:"path"`
+			}{path})
+		}
+		fmt.Fprintln(e.stdout, path)
+		return nil
+	}
+
+	fields, err := s.decodeFields(d)
+	if err != nil {
+		return err
+	}
+	if *field != "" {
+		value, ok := fields[*field]
+		if !ok {
+			return usageErrorf("у записи %q нет поля %q, есть: %s", d.Name, *field, strings.Join(sortedKeys(fields), ", "))
+		}
+		if *asJSON {
+			return writeJSON(e.stdout, value)
+		}
+		// Без перевода строки в конце значение можно подставить как есть: $(client get db -field password)
+		_, err := io.WriteString(e.stdout, value)
+		return err
+	}
+	if *asJSON {
+		r := newRecord(d)
+		r.Fields = fields
+		return writeJSON(e.stdout, r)
+	}
+	fmt.Fprintf(e.stdout, "%s (%s, %s)\n", d.Name, format.DataTypeDisplayName(d.Type), d.Id)
+	fmt.Fprintln(e.stdout, format.DataContentToDisplayString(d, s.client.Vault(), true))
+	for _, line := range format.MetadataToDisplayLines(d.Metadata) {
+		fmt.Fprintln(e.stdout, line)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// changeFlags — флаги содержимого и метаданных команд add и edit
+type changeFlags struct {
+	name       *string
+	fields     listFlag
+	fieldFiles listFlag
+	meta       listFlag
+	unsetMeta  listFlag
+	file       *string
+	asJSON     *bool
+}
+
+func newChangeFlags(name string, edit bool) (*changeFlags, *flag.FlagSet) {
+	fs := newFlagSet(name)
+	f := &changeFlags{}
+	f.name = fs.String("name", "", "Record name")
+	fs.Var(&f.fields, "field", "Set a content field: key=value (repeatable)")
+	fs.Var(&f.fieldFiles, "field-file", "Set a content field from a file, - for stdin: key=path (repeatable)")
+	fs.Var(&f.meta, "meta", "Set metadata: key=value (repeatable)")
+	if edit {
+		fs.Var(&f.unsetMeta, "unset-meta", "Remove a metadata key (repeatable)")
+	}
+	f.file = fs.String("file", "", "Attach a file (binary records)")
+	f.asJSON = fs.Bool("json", false, "Print the saved record as JSON")
+	return f, fs
+}
+
+// fieldValues собирает значения полей из -field и -field-file и проверяет, что у типа rt такие поля есть
+func (f *changeFlags) fieldValues(e *env, rt *format.RecordType) (map[string]string, error) {
+	values := make(map[string]string)
+	set := func(key, value string) error {
+		if rt.Field(key) == nil {
+			keys := make([]string, len(rt.Fields))
+			for i, field := range rt.Fields {
+				keys[i] = field.Key
+			}
+			return usageErrorf("у типа %s нет поля %q, есть: %s", rt.Key, key, strings.Join(keys, ", "))
+		}
+		values[key] = value
+		return nil
+	}
+	for _, s := range f.fields {
+		key, value, err := splitAssignment(s)
+		if err != nil {
+			return nil, err
+		}
+		if err := set(key, value); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range f.fieldFiles {
+		key, path, err := splitAssignment(s)
+		if err != nil {
+			return nil, err
+		}
+		var b []byte
+		if path == "-" {
+			b, err = io.ReadAll(e.stdin)
+		} else {
+			b, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := set(key, string(b)); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// applyMetadata меняет метаданные записи по -meta и -unset-meta; ключи сравниваются без учёта регистра
+func (f *changeFlags) applyMetadata(d *proto.Data) error {
+	metadata := slices.Clone(d.Metadata)
+	for _, key := range f.unsetMeta {
+		n := len(metadata)
+		metadata = slices.DeleteFunc(metadata, func(md *proto.Metadata) bool { return strings.EqualFold(md.Key, key) })
+		if len(metadata) == n {
+			return usageErrorf("у записи нет метаданных %q", key)
+		}
+	}
+	for _, s := range f.meta {
+		key, value, err := splitAssignment(s)
+		if err != nil {
+			return err
+		}
+		i := slices.IndexFunc(metadata, func(md *proto.Metadata) bool { return strings.EqualFold(md.Key, key) })
+		if i >= 0 {
+			metadata[i] = &proto.Metadata{Key: metadata[i].Key, Value: value}
+		} else {
+			metadata = append(metadata, &proto.Metadata{Key: key, Value: value})
+		}
+	}
+	normalized, err := format.NormalizeMetadata(metadata)
+	if err != nil {
+		return &exitError{code: ExitUsage, err: err}
+	}
+	d.Metadata = normalized
+	return nil
+}
+
+// validateFields проверяет поля по описанию типа, как форма добавления
+func validateFields(rt *format.RecordType, fields map[string]string) error {
+	for _, f := range rt.Fields {
+		if f.Validate == nil {
+			continue
+		}
+		if err := f.Validate(fields[f.Key]); err != nil {
+			return usageErrorf("%s: %v", f.Key, err)
+		}
+	}
+	if rt.Validate == nil {
+		return nil
+	}
+	err := rt.Validate(fields)
+	var fieldErr *format.FieldError
+	if errors.As(err, &fieldErr) {
+		return usageErrorf("%s: %v", fieldErr.Key, fieldErr.Err)
+	}
+	if err != nil {
+		return &exitError{code: ExitUsage, err: err}
+	}
+	return nil
+}
+
+// save шифрует поля fields в запись d и сохраняет её; выводит ID или запись в JSON
+func (s *session) save(e *env, d *proto.Data, fields map[string]string, asJSON bool) error {
+	payload, err := format.BuildPayload(d.Type, fields)
+	if err != nil {
+		return err
+	}
+	if d.EncryptedData, err = s.client.Vault().Encrypt(payload); err != nil {
+		return err
+	}
+	queued, err := s.replica.Save(d)
+	if err != nil {
+		return err
+	}
+	if queued {
+		fmt.Fprintln(e.stderr, "Запись сохранена локально и будет отправлена при синхронизации")
+	}
+	if asJSON {
+		r := newRecord(d)
+		r.Queued = queued
+		return writeJSON(e.stdout, r)
+	}
+	fmt.Fprintln(e.stdout, d.Id)
+	return nil
+}
+
+// runAdd — команда add: новая запись типа -type
+func runAdd(e *env, args []string) error {
+	f, fs := newChangeFlags("add", false)
+	typeKey := fs.String("type", "", "Record type")
+	positional, err := parseArgs(e, fs, args)
+	if err != nil {
+		return err
+	}
+	if err := noArgs(positional); err != nil {
+		return err
+	}
+	if *typeKey == "" {
+		return usageErrorf("укажите тип записи: -type")
+	}
+	rt, err := lookupType(*typeKey)
+	if err != nil {
+		return err
+	}
+	name := strings.TrimSpace(*f.name)
+	if name == "" {
+		return usageErrorf("укажите название записи: -name")
+	}
+	if *f.file != "" && !rt.File {
+		return usageErrorf("к записи типа %s нельзя прикрепить файл", rt.Key)
+	}
+	fields, err := f.fieldValues(e, rt)
+	if err != nil {
+		return err
+	}
+	if *f.file != "" && len(fields) > 0 {
+		return usageErrorf("у записи с файлом -field и -field-file не указываются")
+	}
+	if *f.file == "" {
+		if err := validateFields(rt, fields); err != nil {
+			return err
+		}
+	}
+
+	s, err := openSession(e.cfg, e.stderr)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	d := format.BuildDataForSave(name, rt.Type, nil)
+	if err := f.applyMetadata(d); err != nil {
+		return err
+	}
+	if *f.file != "" {
+		if err := s.requireServer(); err != nil {
+			return err
+		}
+		if fields, d.BlobId, err = s.attachFile(*f.file); err != nil {
+			return err
+		}
+	}
+	return s.save(e, d, fields, *f.asJSON)
+}
+
+// runEdit — команда edit: меняет название, поля, метаданные или файл записи; остальное сохраняется
+func runEdit(e *env, args []string) error {
+	f, fs := newChangeFlags("edit", true)
+	positional, err := parseArgs(e, fs, args)
+	if err != nil {
+		return err
+	}
+	ref, err := oneArg(positional)
+	if err != nil {
+		return err
+	}
+
+	s, current, err := loadRecord(e, ref)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	rt := format.LookupRecordType(current.Type)
+	if rt == nil {
+		return fmt.Errorf("тип записи %s не поддерживается", current.Type)
+	}
+	if *f.file != "" && !rt.File {
+		return usageErrorf("к записи типа %s нельзя прикрепить файл", rt.Key)
+	}
+	updates, err := f.fieldValues(e, rt)
+	if err != nil {
+		return err
+	}
+	if *f.file != "" && len(updates) > 0 {
+		return usageErrorf("у записи с файлом -field и -field-file не указываются")
+	}
+
+	d := pb.Clone(current).(*proto.Data)
+	if name := strings.TrimSpace(*f.name); name != "" {
+		d.Name = name
+	}
+	if err := f.applyMetadata(d); err != nil {
+		return err
+	}
+	fields, err := s.decodeFields(current)
+	if err != nil {
+		return err
+	}
+	switch {
+	case *f.file != "":
+		if err := s.requireServer(); err != nil {
+			return err
+		}
+		if fields, d.BlobId, err = s.attachFile(*f.file); err != nil {
+			return err
+		}
+	case len(updates) > 0:
+		if rt.File {
+			// Новое содержимое вместо прикреплённого файла
+			fields, d.BlobId = map[string]string{}, ""
+		}
+		for key, value := range updates {
+			fields[key] = value
+		}
+		if err := validateFields(rt, fields); err != nil {
+			return err
+		}
+	}
+	d.UpdatedAt = time.Now().Unix()
+	return s.save(e, d, fields, *f.asJSON)
+}
+
+// runRemove — команда rm: удаляет запись
+func runRemove(e *env, args []string) error {
+	fs := newFlagSet("rm")
+	asJSON := fs.Bool("json", false, "Print the result as JSON")
+	positional, err := parseArgs(e, fs, args)
+	if err != nil {
+		return err
+	}
+	ref, err := oneArg(positional)
+	if err != nil {
+		return err
+	}
+
+	s, d, err := loadRecord(e, ref)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	queued, err := s.replica.Delete(d)
+	if err != nil {
+		return err
+	}
+	if queued {
+		fmt.Fprintln(e.stderr, "Запись удалена локально, удаление будет отправлено при синхронизации")
+	}
+	if *asJSON {
+		return writeJSON(e.stdout, struct {
+			ID     string `json:"id"`
+			Queued bool   `json:"queued,omitempty"`
+		}{d.Id, queued})
+	}
+	return nil
+}
+
+// runVersion — команда version
+func runVersion(e *env, args []string) error {
+	positional, err := parseArgs(e, newFlagSet("version"), args)
+	if err != nil {
+		return err
+	}
+	if err := noArgs(positional); err != nil {
+		return err
+	}
+	PrintVersion(e.stdout)
+	return nil
+}
+
+// PrintVersion выводит версию и дату сборки клиента
+func PrintVersion(w io.Writer) {
+	fmt.Fprintf(w, "GophKeeper Client\nVersion: %s\nBuild Date: %s\n", Version, BuildDate)
+}
+
+// runSync — команда sync: синхронизация выполняется при открытии сеанса, команда выводит её итог.
+// Без связи с сервером — код ExitUnavailable, при конфликтах версий — ExitConflict.
+func runSync(e *env, args []string) error {
+	fs := newFlagSet("sync")
+	asJSON := fs.Bool("json", false, "Print the sync report as JSON")
+	positional, err := parseArgs(e, fs, args)
+	if err != nil {
+		return err
+	}
+	if err := noArgs(positional); err != nil {
+		return err
+	}
+
+	s, err := openSession(e.cfg, e.stderr)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	if s.syncErr != nil {
+		return s.syncErr
+	}
+	if s.synced == nil {
+		return &exitError{code: ExitUnavailable, err: errors.New("нет связи с сервером")}
+	}
+	report := s.synced
+	result := struct {
+		Pushed    int      `json:"pushed"`
+		Received  int      `json:"received"`
+		Deleted   int      `json:"deleted"`
+		Pending   int64    `json:"pending"`
+		Failed    int      `json:"failed"`
+		Conflicts []string `json:"conflicts"`
+	}{report.Pushed, report.Received, report.Deleted, report.Pending, report.Failed, report.Conflicts}
+	if result.Conflicts == nil {
+		result.Conflicts = []string{}
+	}
+	if *asJSON {
+		err = writeJSON(e.stdout, result)
+	} else {
+		fmt.Fprintf(e.stdout, "Отправлено: %d, получено: %d, удалено: %d, в очереди: %d, отклонено: %d, конфликтов: %d\n",
+			result.Pushed, result.Received, result.Deleted, result.Pending, result.Failed, len(result.Conflicts))
+	}
+	if err != nil {
+		return err
+	}
+	if len(result.Conflicts) > 0 {
+		return &exitError{code: ExitConflict,
+			err: fmt.Errorf("конфликт версий у записей %s; разрешите его в TUI", strings.Join(result.Conflicts, ", "))}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/gophkeeper/gophkeeper/internal/client"
+	"github.com/gophkeeper/gophkeeper/internal/client/cache"
+	"github.com/gophkeeper/gophkeeper/internal/client/replica"
+	"github.com/gophkeeper/gophkeeper/internal/client/vault"
+	"github.com/gophkeeper/gophkeeper/internal/config"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Переменные окружения с учётными данными для команд без TUI; без них данные запрашиваются в терминале
+const (
+	envLogin          = "GOPHKEEPER_LOGIN"
+	envPassword       = "GOPHKEEPER_PASSWORD"
+	envMasterPassword = "GOPHKEEPER_MASTER_PASSWORD"
+)
+
+// session — вход и открытое хранилище ключа для команды без TUI, как после экрана входа
+type session struct {
+	login   string
+	client  *client.Client
+	replica *replica.Replica
+	// synced — отчёт синхронизации при открытии (nil, если связи с сервером нет или синхронизация не удалась),
+	// syncErr — ошибка синхронизации
+	synced  *replica.SyncReport
+	syncErr error
+}
+
+// openSession входит на сервер и открывает хранилище ключа и локальную копию. Без связи с сервером
+// открывается локальная копия; при связи она сначала синхронизируется, а ошибка синхронизации
+// выводится в stderr как предупреждение.
+func openSession(cfg *config.ClientConfig, stderr io.Writer) (*session, error) {
+	login, err := credential(envLogin, "Логин: ", false, stderr)
+	if err != nil {
+		return nil, err
+	}
+	password, err := credential(envPassword, "Пароль: ", true, stderr)
+	if err != nil {
+		return nil, err
+	}
+	masterPassword, err := credential(envMasterPassword, "Мастер-пароль: ", true, stderr)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := client.NewClient(cfg.Server)
+	if err != nil {
+		return nil, err
+	}
+	c.SetEncryptMetadata(cfg.EncryptMetadata)
+	s, err := unlock(c, cfg, login, password, masterPassword)
+	if err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	if !s.replica.Offline() {
+		if s.synced, s.syncErr = s.replica.Sync(); s.syncErr != nil {
+			fmt.Fprintf(stderr, "Синхронизация не удалась, используется локальная копия: %v\n", s.syncErr)
+		}
+	}
+	return s, nil
+}
+
+func unlock(c *client.Client, cfg *config.ClientConfig, login, password, masterPassword string) (*session, error) {
+	store, err := cache.Open(cache.PathFor(cfg.CacheDir, cfg.Server, login))
+	if err != nil {
+		return nil, err
+	}
+	if err := unlockVault(c, store, login, password, masterPassword); err != nil {
+		_ = store.Close()
+		return nil, err
+	}
+	r, err := replica.Open(c, store, login, password)
+	if err != nil {
+		_ = store.Close()
+		return nil, err
+	}
+	return &session{login: login, client: c, replica: r}, nil
+}
+
+// unlockVault входит на сервер и открывает хранилище ключа; без связи с сервером — по блоку проверки из кэша
+func unlockVault(c *client.Client, store *cache.Cache, login, password, masterPassword string) error {
+	var err error
+	if loginErr := c.Login(login, password); loginErr == nil {
+		err = c.UnlockVault(masterPassword)
+	} else if client.IsUnavailable(loginErr) {
+		keyCheck, checkErr := replica.OfflineKeyCheck(store)
+		if errors.Is(checkErr, replica.ErrNoOfflineCopy) {
+			return &exitError{code: ExitUnavailable,
+				err: errors.New("нет связи с сервером, а локальной копии для этого пользователя нет")}
+		}
+		if checkErr != nil {
+			return checkErr
+		}
+		err = c.UnlockVaultOffline(masterPassword, keyCheck)
+	} else {
+		// Отказ во входе приходит ответом без ошибки gRPC, сбой сервера — статусом
+		if st, ok := status.FromError(loginErr); !ok || st.Code() == codes.Unauthenticated {
+			return &exitError{code: ExitAuth, err: loginErr}
+		}
+		return loginErr
+	}
+	if errors.Is(err, vault.ErrWrongMasterPassword) {
+		return &exitError{code: ExitAuth, err: errors.New("неверный мастер-пароль")}
+	}
+	return err
+}
+
+// Close закрывает локальную копию и соединение с сервером
+func (s *session) Close() error {
+	_ = s.replica.Close()
+	return s.client.Close()
+}
+
+// credential берёт значение из переменной окружения env, а если её нет — спрашивает в терминале;
+// secret — ввод без эха
+func credential(env, prompt string, secret bool, stderr io.Writer) (string, error) {
+	if v := os.Getenv(env); v != "" {
+		return v, nil
+	}
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return "", &exitError{code: ExitUsage, err: fmt.Errorf("не задана переменная окружения %s", env)}
+	}
+	fmt.Fprint(stderr, prompt)
+	if secret {
+		b, err := term.ReadPassword(os.Stdin.Fd())
+		fmt.Fprintln(stderr)
+		return string(b), err
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/gophkeeper/gophkeeper/internal/client/format"
+	"github.com/gophkeeper/gophkeeper/internal/client/sshkey"
+	"github.com/gophkeeper/gophkeeper/proto"
+)
+
+// runSSHAgent — команда ssh-agent: отдаёт ключи из записей SSH_KEY через сокет протокола ssh-agent,
+// пока не получит SIGINT или SIGTERM. Переменная SSH_AUTH_SOCK для ssh выводится в stdout в формате ssh-agent.
+func runSSHAgent(e *env, args []string) error {
+	fs := newFlagSet("ssh-agent")
+	socket := fs.String("socket", defaultAgentSocket(), "Path of the ssh-agent Unix socket")
+	positional, err := parseArgs(e, fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usageErrorf("лишние аргументы: %v", positional)
+	}
+
+	keys, err := loadSSHKeys(e)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return &exitError{code: ExitNotFound, err: errors.New("в хранилище нет ключей SSH")}
+	}
+	a, err := sshkey.NewAgent(keys)
+	if err != nil {
+		return err
+	}
+
+	l, err := sshkey.Listen(*socket)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(*socket)
+
+	fmt.Fprintf(e.stdout, "SSH_AUTH_SOCK=%s; export SSH_AUTH_SOCK;\n", *socket)
+	fmt.Fprintf(e.stderr, "Агент GophKeeper: ключей — %d, Ctrl+C — остановить\n", len(keys))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return sshkey.Serve(ctx, l, a)
+}
+
+// loadSSHKeys расшифровывает записи SSH_KEY; записи, ключ которых не разбирается, пропускаются с предупреждением.
+// Комментарий ключа без своего комментария — название записи.
+func loadSSHKeys(e *env) ([]*sshkey.Key, error) {
+	s, err := openSession(e.cfg, e.stderr)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+
+	records, err := s.replica.List()
+	if err != nil {
+		return nil, err
+	}
+	var keys []*sshkey.Key
+	for _, d := range records {
+		if d.Type != proto.DataType_SSH_KEY {
+			continue
+		}
+		key, err := parseSSHKey(s, d)
+		if err != nil {
+			fmt.Fprintf(e.stderr, "Запись %q пропущена: %v\n", d.Name, err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func parseSSHKey(s *session, d *proto.Data) (*sshkey.Key, error) {
+	fields, err := s.decodeFields(d)
+	if err != nil {
+		return nil, err
+	}
+	key, err := sshkey.Parse(fields[format.FieldSSHPrivateKey], fields[format.FieldSSHPassphrase],
+		fields[format.FieldSSHPublicKey], fields[format.FieldSSHComment])
+	if err != nil {
+		return nil, err
+	}
+	if key.Comment == "" {
+		key.Comment = d.Name
+	}
+	return key, nil
+}
+
+// defaultAgentSocket — сокет агента в XDG_RUNTIME_DIR, а без него — во временном каталоге
+func defaultAgentSocket() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, fmt.Sprintf("gophkeeper-agent-%d.sock", os.Getuid()))
+}
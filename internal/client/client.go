@@ -1,14 +1,20 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/gophkeeper/gophkeeper/internal/client/vault"
 	"github.com/gophkeeper/gophkeeper/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	pb "google.golang.org/protobuf/proto"
 )
 
 // Client представляет клиент для взаимодействия с сервером
@@ -19,6 +25,9 @@ type Client struct {
 	accessToken   string
 	refreshToken  string
 	serverAddress string
+	keyCheck      []byte
+	vault         *vault.Vault
+	encryptMeta   bool
 }
 
 // NewClient создаёт новый клиент
@@ -93,10 +102,185 @@ func (c *Client) Login(login, password string) error {
 
 	c.accessToken = resp.AccessToken
 	c.refreshToken = resp.RefreshToken
+	c.keyCheck = resp.KeyCheck
+	c.vault = nil
 
 	return nil
 }
 
+// UnlockVault проверяет мастер-пароль и открывает хранилище ключа для шифрования записей.
+// Если блок проверки ещё не создан (первый вход), он создаётся из введённого пароля и сохраняется на сервере;
+// блок в устаревшем формате заменяется на обёрнутый мастер-ключ.
+func (c *Client) UnlockVault(masterPassword string) error {
+	if len(c.keyCheck) == 0 {
+		v, keyCheck, err := vault.Create(masterPassword)
+		if err != nil {
+			return err
+		}
+		if err := c.setKeyCheck(nil, keyCheck); err != nil {
+			return err
+		}
+		c.keyCheck = keyCheck
+		c.vault = v
+		return nil
+	}
+
+	v, upgraded, err := vault.Open(masterPassword, c.keyCheck)
+	if err != nil {
+		return err
+	}
+	if upgraded != nil {
+		if err := c.setKeyCheck(c.keyCheck, upgraded); err != nil {
+			return err
+		}
+		c.keyCheck = upgraded
+	}
+
+	c.vault = v
+	return nil
+}
+
+// setKeyCheck сохраняет блок проверки мастер-пароля на сервере
+func (c *Client) setKeyCheck(previous, keyCheck []byte) error {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	resp, err := c.authClient.SetKeyCheck(ctx, &proto.SetKeyCheckRequest{
+		KeyCheck:         keyCheck,
+		PreviousKeyCheck: previous,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("key check save failed: %s", resp.Message)
+	}
+
+	return nil
+}
+
+// ChangePassword меняет пароль входа и/или мастер-пароль (пустое значение — не менять).
+// При смене мастер-пароля мастер-ключ переоборачивается, а записи в устаревшем формате перешифровываются;
+// сервер применяет всё одной транзакцией и отзывает прежние refresh токены.
+func (c *Client) ChangePassword(oldPassword, newPassword, newMasterPassword string) error {
+	req := &proto.ChangePasswordRequest{
+		OldPassword: oldPassword,
+		NewPassword: newPassword,
+	}
+
+	var newVault *vault.Vault
+	if newMasterPassword != "" {
+		v, keyCheck, err := c.vault.ChangeMasterPassword(newMasterPassword)
+		if err != nil {
+			return err
+		}
+
+		records, err := c.ListData(proto.DataType_UNKNOWN)
+		if err != nil {
+			return err
+		}
+		for _, d := range records {
+			if !vault.NeedsReencrypt(d.EncryptedData) {
+				continue
+			}
+			encrypted, err := c.vault.Reencrypt(d.EncryptedData)
+			if err != nil {
+				return fmt.Errorf("re-encrypt %q: %w", d.Name, err)
+			}
+			req.Data = append(req.Data, &proto.ReencryptedData{
+				Id:            d.Id,
+				EncryptedData: encrypted,
+				Version:       d.Version,
+			})
+		}
+
+		req.KeyCheck = keyCheck
+		req.PreviousKeyCheck = c.keyCheck
+		newVault = v
+	}
+
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	resp, err := c.authClient.ChangePassword(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if !resp.Success {
+		return fmt.Errorf("password change failed: %s", resp.Message)
+	}
+
+	c.accessToken = resp.AccessToken
+	c.refreshToken = resp.RefreshToken
+	if newVault != nil {
+		c.keyCheck = req.KeyCheck
+		c.vault = newVault
+	}
+
+	return nil
+}
+
+// Vault возвращает открытое хранилище ключа (nil, если мастер-пароль ещё не введён)
+func (c *Client) Vault() *vault.Vault {
+	return c.vault
+}
+
+// IsVaultUnlocked проверяет, введён ли мастер-пароль
+func (c *Client) IsVaultUnlocked() bool {
+	return c.vault != nil
+}
+
+// KeyCheck возвращает текущий блок проверки мастер-пароля (для сохранения в локальном кэше)
+func (c *Client) KeyCheck() []byte {
+	return c.keyCheck
+}
+
+// ErrSessionChanged — после повторного входа на сервере оказался другой блок проверки мастер-пароля
+// (пароль сменили с другого устройства), мастер-пароль нужно ввести заново
+var ErrSessionChanged = errors.New("master password was changed on another device")
+
+// UnlockVaultOffline открывает хранилище по блоку проверки из локального кэша, без обращения к серверу.
+// Блок в устаревшем формате не обновляется: это сделает следующий вход с сервером.
+func (c *Client) UnlockVaultOffline(masterPassword string, keyCheck []byte) error {
+	if len(keyCheck) == 0 {
+		return vault.ErrWrongMasterPassword
+	}
+	v, _, err := vault.Open(masterPassword, keyCheck)
+	if err != nil {
+		return err
+	}
+	c.keyCheck = keyCheck
+	c.vault = v
+	return nil
+}
+
+// Resume выполняет вход, не закрывая открытое хранилище: используется, когда сеанс начат без связи
+// с сервером. Если блок проверки на сервере отличается от текущего, хранилище закрывается
+// и возвращается ErrSessionChanged.
+func (c *Client) Resume(login, password string) error {
+	keyCheck, v := c.keyCheck, c.vault
+	if err := c.Login(login, password); err != nil {
+		return err
+	}
+	if !bytes.Equal(c.keyCheck, keyCheck) {
+		return ErrSessionChanged
+	}
+	c.vault = v
+	return nil
+}
+
+// IsUnavailable сообщает, что запрос не дошёл до сервера (нет сети, сервер не запущен или не ответил вовремя)
+func IsUnavailable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
 // RefreshToken обновляет токен
 func (c *Client) RefreshToken() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -134,16 +318,62 @@ func (c *Client) IsAuthenticated() bool {
 	return c.accessToken != ""
 }
 
-// SaveData сохраняет данные
+// ErrVersionConflict — запись изменена на сервере после того, как клиент её прочитал
+var ErrVersionConflict = errors.New("data has been modified by another client")
+
+// ConflictError возвращается SaveData при конфликте версий.
+// Current — актуальная копия записи на сервере (nil, если запись удалена).
+type ConflictError struct {
+	Current *proto.Data
+}
+
+func (e *ConflictError) Error() string {
+	return ErrVersionConflict.Error()
+}
+
+func (e *ConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
+
+// SaveData сохраняет данные.
+// data.Version — версия, от которой вносились изменения (0 для новой записи); сервер отклонит
+// сохранение, если запись успела измениться, и вернёт *ConflictError с актуальной копией.
 func (c *Client) SaveData(data *proto.Data) (string, int64, error) {
+	// Записи в устаревшем формате при сохранении прозрачно перешифровываются актуальной схемой
+	if c.vault != nil {
+		encrypted, err := c.vault.Upgrade(data.EncryptedData)
+		if err != nil {
+			return "", 0, err
+		}
+		data.EncryptedData = encrypted
+	}
+
+	sent, err := c.sealMeta(data)
+	if err != nil {
+		return "", 0, err
+	}
+
 	ctx, cancel := c.getContext()
 	defer cancel()
 
 	resp, err := c.dataClient.SaveData(ctx, &proto.SaveDataRequest{
-		Data: data,
+		Data:        sent,
+		BaseVersion: data.Version,
 	})
 
 	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.Aborted {
+			conflict := &ConflictError{}
+			for _, d := range st.Details() {
+				if current, ok := d.(*proto.Data); ok {
+					conflict.Current = current
+				}
+			}
+			if err := c.openMeta(conflict.Current); err != nil {
+				return "", 0, err
+			}
+			return "", 0, conflict
+		}
 		return "", 0, err
 	}
 
@@ -171,6 +401,9 @@ func (c *Client) GetData(dataID string) (*proto.Data, error) {
 		return nil, fmt.Errorf("get failed: %s", resp.Message)
 	}
 
+	if err := c.openMeta(resp.Data); err != nil {
+		return nil, err
+	}
 	return resp.Data, nil
 }
 
@@ -194,9 +427,84 @@ func (c *Client) ListData(dataType proto.DataType) ([]*proto.Data, error) {
 	if resp.Data == nil {
 		return []*proto.Data{}, nil
 	}
+	if err := c.openMetaAll(resp.Data); err != nil {
+		return nil, err
+	}
 	return resp.Data, nil
 }
 
+// SummaryPage страница кратких описаний записей
+type SummaryPage struct {
+	Items []*proto.DataSummary
+	// NextPageToken — токен следующей страницы (пусто — страниц больше нет)
+	NextPageToken string
+}
+
+// ListDataSummaries получает страницу кратких описаний записей без содержимого; названия
+// расшифровываются. Содержимое записи запрашивается отдельно через GetData, когда её открывают.
+func (c *Client) ListDataSummaries(req *proto.ListDataSummariesRequest) (*SummaryPage, error) {
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	resp, err := c.dataClient.ListDataSummaries(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("list failed: %s", resp.Message)
+	}
+
+	for _, s := range resp.Summaries {
+		if err := c.openSummaryMeta(s); err != nil {
+			return nil, err
+		}
+	}
+	return &SummaryPage{Items: resp.Summaries, NextPageToken: resp.NextPageToken}, nil
+}
+
+// SearchData ищет записи на сервере и возвращает страницу кратких описаний с расшифрованными названиями.
+// Если названия шифруются, сервер не видит их: поиск по названию тогда идёт по точному токену,
+// который подставляется вместо name_prefix и name_contains.
+func (c *Client) SearchData(req *proto.SearchDataRequest) (*SummaryPage, error) {
+	if c.encryptMeta && req.SearchToken == "" && (req.NamePrefix != "" || req.NameContains != "") {
+		if c.vault == nil {
+			return nil, vault.ErrLocked
+		}
+		name := req.NamePrefix
+		if name == "" {
+			name = req.NameContains
+		}
+		token, err := c.vault.SearchToken(name)
+		if err != nil {
+			return nil, err
+		}
+		req = pb.Clone(req).(*proto.SearchDataRequest)
+		req.NamePrefix = ""
+		req.NameContains = ""
+		req.SearchToken = token
+	}
+
+	ctx, cancel := c.getContext()
+	defer cancel()
+
+	resp, err := c.dataClient.SearchData(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("search failed: %s", resp.Message)
+	}
+
+	for _, s := range resp.Summaries {
+		if err := c.openSummaryMeta(s); err != nil {
+			return nil, err
+		}
+	}
+	return &SummaryPage{Items: resp.Summaries, NextPageToken: resp.NextPageToken}, nil
+}
+
 // DeleteData удаляет данные
 func (c *Client) DeleteData(dataID string) error {
 	ctx, cancel := c.getContext()
@@ -217,26 +525,98 @@ func (c *Client) DeleteData(dataID string) error {
 	return nil
 }
 
-// SyncData синхронизирует данные
-func (c *Client) SyncData(lastSyncTime int64) ([]*proto.Data, int64, error) {
+// SyncResult результат синхронизации
+type SyncResult struct {
+	// Data — изменённые записи
+	Data []*proto.Data
+	// Deleted — записи, удалённые на сервере после позиции курсора
+	Deleted []*proto.Tombstone
+	// FullResync — ответ является полным снимком: локальные записи, которых в нём нет, нужно удалить
+	FullResync bool
+	// NextCursor — курсор для следующей синхронизации
+	NextCursor string
+	// HasMore — на сервере остались следующие страницы
+	HasMore bool
+}
+
+// SyncData запрашивает одну страницу изменений после позиции cursor (пусто — первая синхронизация)
+func (c *Client) SyncData(cursor string) (*SyncResult, error) {
 	ctx, cancel := c.getContext()
 	defer cancel()
 
 	resp, err := c.dataClient.SyncData(ctx, &proto.SyncDataRequest{
-		LastSyncTime: lastSyncTime,
+		Cursor: cursor,
 	})
 
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
 	if !resp.Success {
-		return nil, 0, fmt.Errorf("sync failed: %s", resp.Message)
+		return nil, fmt.Errorf("sync failed: %s", resp.Message)
 	}
 
 	data := resp.Data
 	if data == nil {
 		data = []*proto.Data{}
 	}
-	return data, resp.SyncTime, nil
+	if err := c.openMetaAll(data); err != nil {
+		return nil, err
+	}
+	return &SyncResult{
+		Data:       data,
+		Deleted:    resp.Deleted,
+		FullResync: resp.FullResync,
+		NextCursor: resp.NextCursor,
+		HasMore:    resp.HasMore,
+	}, nil
+}
+
+// SyncAll запрашивает все страницы изменений после позиции cursor и объединяет их.
+// Если запись менялась, пока шла выдача страниц, в результате остаётся её последнее состояние.
+func (c *Client) SyncAll(cursor string) (*SyncResult, error) {
+	result := &SyncResult{Data: []*proto.Data{}}
+	index := make(map[string]int)
+	deleted := make(map[string]*proto.Tombstone)
+
+	for first := true; first || result.HasMore; first = false {
+		page, err := c.SyncData(cursor)
+		if err != nil {
+			return nil, err
+		}
+		if first {
+			result.FullResync = page.FullResync
+		}
+
+		for _, d := range page.Data {
+			delete(deleted, d.Id)
+			if i, ok := index[d.Id]; ok {
+				result.Data[i] = d
+				continue
+			}
+			index[d.Id] = len(result.Data)
+			result.Data = append(result.Data, d)
+		}
+		for _, t := range page.Deleted {
+			deleted[t.Id] = t
+		}
+
+		cursor = page.NextCursor
+		result.NextCursor = page.NextCursor
+		result.HasMore = page.HasMore
+	}
+
+	if len(deleted) > 0 {
+		live := result.Data[:0]
+		for _, d := range result.Data {
+			if _, ok := deleted[d.Id]; !ok {
+				live = append(live, d)
+			}
+		}
+		result.Data = live
+		for _, t := range deleted {
+			result.Deleted = append(result.Deleted, t)
+		}
+	}
+	return result, nil
 }
@@ -1,12 +1,24 @@
 package client_test
 
 import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
 	"testing"
 
 	"github.com/gophkeeper/gophkeeper/internal/client"
 	"github.com/gophkeeper/gophkeeper/internal/client/mocks"
+	"github.com/gophkeeper/gophkeeper/internal/client/vault"
+	"github.com/gophkeeper/gophkeeper/internal/crypto"
 	"github.com/gophkeeper/gophkeeper/proto"
 	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/pbkdf2"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -173,6 +185,274 @@ func TestRefreshToken_UnsuccessfulResponse(t *testing.T) {
 	}
 }
 
+func TestUnlockVault_FirstLoginCreatesKeyCheck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	authMock := mocks.NewMockAuthServiceClient(ctrl)
+	dataMock := mocks.NewMockDataServiceClient(ctrl)
+
+	authMock.EXPECT().Login(gomock.Any(), gomock.Any()).
+		Return(&proto.LoginResponse{Success: true, AccessToken: "at", RefreshToken: "rt"}, nil)
+
+	var saved []byte
+	authMock.EXPECT().
+		SetKeyCheck(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *proto.SetKeyCheckRequest, _ ...grpc.CallOption) (*proto.SetKeyCheckResponse, error) {
+			if len(req.PreviousKeyCheck) != 0 {
+				t.Errorf("PreviousKeyCheck = %q, want empty", req.PreviousKeyCheck)
+			}
+			saved = req.KeyCheck
+			return &proto.SetKeyCheckResponse{Success: true}, nil
+		})
+
+	c := client.NewClientWithClients(authMock, dataMock)
+	_ = c.Login("u", "p")
+
+	if err := c.UnlockVault("master"); err != nil {
+		t.Fatalf("UnlockVault: %v", err)
+	}
+	if !c.IsVaultUnlocked() || len(saved) == 0 {
+		t.Fatal("хранилище должно быть открыто, а блок проверки — сохранён")
+	}
+
+	ciphertext, err := c.Vault().Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if bytes.Contains(ciphertext, []byte("secret")) {
+		t.Error("шифротекст не должен содержать открытый текст")
+	}
+	plaintext, err := c.Vault().Decrypt(ciphertext)
+	if err != nil || string(plaintext) != "secret" {
+		t.Errorf("Decrypt = %q, %v", plaintext, err)
+	}
+}
+
+func TestUnlockVault_WrongMasterPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, keyCheck, err := vault.Create("correct")
+	if err != nil {
+		t.Fatalf("vault.Create: %v", err)
+	}
+
+	authMock := mocks.NewMockAuthServiceClient(ctrl)
+	dataMock := mocks.NewMockDataServiceClient(ctrl)
+
+	authMock.EXPECT().Login(gomock.Any(), gomock.Any()).
+		Return(&proto.LoginResponse{Success: true, AccessToken: "at", RefreshToken: "rt", KeyCheck: keyCheck}, nil)
+
+	c := client.NewClientWithClients(authMock, dataMock)
+	_ = c.Login("u", "p")
+
+	err = c.UnlockVault("wrong")
+	if !errors.Is(err, vault.ErrWrongMasterPassword) {
+		t.Fatalf("err = %v, want ErrWrongMasterPassword", err)
+	}
+	if c.IsVaultUnlocked() {
+		t.Error("хранилище не должно открываться с неверным паролем")
+	}
+	if err := c.UnlockVault("correct"); err != nil {
+		t.Errorf("UnlockVault(correct): %v", err)
+	}
+}
+
+func TestUnlockVault_UpgradesLegacyKeyCheck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Блок проверки первой версии: текст, зашифрованный мастер-паролем без заголовка формата
+	salt := make([]byte, crypto.SaltSize)
+	nonce := make([]byte, crypto.NonceSize)
+	key := pbkdf2.Key([]byte("master"), salt, crypto.PBKDF2Iterations, crypto.KeySize, sha256.New)
+	block, _ := aes.NewCipher(key)
+	aesGCM, _ := cipher.NewGCM(block)
+	legacy := aesGCM.Seal(append(append([]byte{}, salt...), nonce...), nonce, []byte("gophkeeper-key-check"), nil)
+
+	authMock := mocks.NewMockAuthServiceClient(ctrl)
+	dataMock := mocks.NewMockDataServiceClient(ctrl)
+
+	authMock.EXPECT().Login(gomock.Any(), gomock.Any()).
+		Return(&proto.LoginResponse{Success: true, AccessToken: "at", RefreshToken: "rt", KeyCheck: legacy}, nil)
+
+	var upgraded []byte
+	authMock.EXPECT().
+		SetKeyCheck(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *proto.SetKeyCheckRequest, _ ...grpc.CallOption) (*proto.SetKeyCheckResponse, error) {
+			if !bytes.Equal(req.PreviousKeyCheck, legacy) {
+				t.Error("PreviousKeyCheck должен содержать старый блок")
+			}
+			upgraded = req.KeyCheck
+			return &proto.SetKeyCheckResponse{Success: true}, nil
+		})
+
+	c := client.NewClientWithClients(authMock, dataMock)
+	_ = c.Login("u", "p")
+
+	if err := c.UnlockVault("master"); err != nil {
+		t.Fatalf("UnlockVault: %v", err)
+	}
+	if _, _, err := vault.Open("master", upgraded); err != nil {
+		t.Errorf("новый блок проверки не открывается: %v", err)
+	}
+}
+
+func TestUnlockVault_UpgradesPBKDF2KeyCheck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Мастер-ключ, обёрнутый ключом из PBKDF2 (до перехода на Argon2id)
+	masterKey, _ := crypto.GenerateKey()
+	keyCheck, err := crypto.EncryptDataWithParams(masterKey, "master", crypto.PBKDF2Params())
+	if err != nil {
+		t.Fatalf("EncryptDataWithParams: %v", err)
+	}
+
+	authMock := mocks.NewMockAuthServiceClient(ctrl)
+	dataMock := mocks.NewMockDataServiceClient(ctrl)
+
+	authMock.EXPECT().Login(gomock.Any(), gomock.Any()).
+		Return(&proto.LoginResponse{Success: true, AccessToken: "at", RefreshToken: "rt", KeyCheck: keyCheck}, nil)
+
+	var upgraded []byte
+	authMock.EXPECT().
+		SetKeyCheck(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *proto.SetKeyCheckRequest, _ ...grpc.CallOption) (*proto.SetKeyCheckResponse, error) {
+			upgraded = req.KeyCheck
+			return &proto.SetKeyCheckResponse{Success: true}, nil
+		})
+
+	c := client.NewClientWithClients(authMock, dataMock)
+	_ = c.Login("u", "p")
+
+	if err := c.UnlockVault("master"); err != nil {
+		t.Fatalf("UnlockVault: %v", err)
+	}
+	h, err := crypto.ParseHeader(upgraded)
+	if err != nil {
+		t.Fatalf("ParseHeader: %v", err)
+	}
+	if h.KDF != crypto.DefaultKDFParams() {
+		t.Errorf("KDF = %+v, want Argon2id", h.KDF)
+	}
+
+	// Мастер-ключ не меняется: старые записи по-прежнему читаются
+	record, _ := crypto.SealEnvelope([]byte("payload"), masterKey)
+	if _, err := c.Vault().Decrypt(record); err != nil {
+		t.Errorf("Decrypt: %v", err)
+	}
+}
+
+func TestSaveData_UpgradesPasswordEncryptedRecord(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, keyCheck, err := vault.Create("master")
+	if err != nil {
+		t.Fatalf("vault.Create: %v", err)
+	}
+	old, err := crypto.EncryptDataWithParams([]byte("payload"), "master", crypto.PBKDF2Params())
+	if err != nil {
+		t.Fatalf("EncryptDataWithParams: %v", err)
+	}
+
+	authMock := mocks.NewMockAuthServiceClient(ctrl)
+	dataMock := mocks.NewMockDataServiceClient(ctrl)
+
+	authMock.EXPECT().Login(gomock.Any(), gomock.Any()).
+		Return(&proto.LoginResponse{Success: true, AccessToken: "at", RefreshToken: "rt", KeyCheck: keyCheck}, nil)
+	dataMock.EXPECT().
+		SaveData(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *proto.SaveDataRequest, _ ...grpc.CallOption) (*proto.SaveDataResponse, error) {
+			h, err := crypto.ParseHeader(req.Data.EncryptedData)
+			if err != nil || h.Scheme != crypto.SchemeEnvelope {
+				t.Errorf("сохранённая запись должна быть в конвертном формате: %+v, %v", h, err)
+			}
+			return &proto.SaveDataResponse{Success: true, DataId: "id1", Version: 2}, nil
+		})
+
+	c := client.NewClientWithClients(authMock, dataMock)
+	_ = c.Login("u", "p")
+	if err := c.UnlockVault("master"); err != nil {
+		t.Fatalf("UnlockVault: %v", err)
+	}
+
+	if _, _, err := c.SaveData(&proto.Data{Id: "id1", Type: proto.DataType_TEXT, EncryptedData: old}); err != nil {
+		t.Fatalf("SaveData: %v", err)
+	}
+}
+
+func TestChangePassword_RewrapsMasterKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, keyCheck, err := vault.Create("master")
+	if err != nil {
+		t.Fatalf("vault.Create: %v", err)
+	}
+	// Запись, зашифрованная мастер-паролем до появления мастер-ключа
+	legacy, err := crypto.EncryptData([]byte("legacy"), "master")
+	if err != nil {
+		t.Fatalf("EncryptData: %v", err)
+	}
+
+	authMock := mocks.NewMockAuthServiceClient(ctrl)
+	dataMock := mocks.NewMockDataServiceClient(ctrl)
+
+	authMock.EXPECT().Login(gomock.Any(), gomock.Any()).
+		Return(&proto.LoginResponse{Success: true, AccessToken: "at", RefreshToken: "rt", KeyCheck: keyCheck}, nil)
+
+	c := client.NewClientWithClients(authMock, dataMock)
+	_ = c.Login("u", "p")
+	if err := c.UnlockVault("master"); err != nil {
+		t.Fatalf("UnlockVault: %v", err)
+	}
+	envelope, err := c.Vault().Encrypt([]byte("envelope"))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	dataMock.EXPECT().ListData(gomock.Any(), gomock.Any()).
+		Return(&proto.ListDataResponse{
+			Success: true,
+			Data: []*proto.Data{
+				{Id: "1", EncryptedData: envelope, Version: 1},
+				{Id: "2", EncryptedData: legacy, Version: 4},
+			},
+		}, nil)
+
+	var sent *proto.ChangePasswordRequest
+	authMock.EXPECT().
+		ChangePassword(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *proto.ChangePasswordRequest, _ ...grpc.CallOption) (*proto.ChangePasswordResponse, error) {
+			sent = req
+			return &proto.ChangePasswordResponse{Success: true, AccessToken: "at2", RefreshToken: "rt2"}, nil
+		})
+
+	if err := c.ChangePassword("p", "p2", "master2"); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+
+	if sent.NewPassword != "p2" || !bytes.Equal(sent.PreviousKeyCheck, keyCheck) {
+		t.Errorf("request = %+v", sent)
+	}
+	if _, _, err := vault.Open("master2", sent.KeyCheck); err != nil {
+		t.Errorf("новый блок проверки не открывается новым мастер-паролем: %v", err)
+	}
+	if len(sent.Data) != 1 || sent.Data[0].Id != "2" || sent.Data[0].Version != 4 {
+		t.Fatalf("перешифровать нужно только запись в устаревшем формате: %+v", sent.Data)
+	}
+
+	// Новое хранилище читает и старые envelope-записи, и перешифрованные
+	for _, ct := range [][]byte{envelope, sent.Data[0].EncryptedData} {
+		if _, err := c.Vault().Decrypt(ct); err != nil {
+			t.Errorf("Decrypt после смены пароля: %v", err)
+		}
+	}
+}
+
 func TestSaveData_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -224,6 +504,103 @@ func TestSaveData_UnsuccessfulResponse(t *testing.T) {
 	}
 }
 
+func TestSaveData_VersionConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	authMock := mocks.NewMockAuthServiceClient(ctrl)
+	dataMock := mocks.NewMockDataServiceClient(ctrl)
+
+	authMock.EXPECT().Login(gomock.Any(), gomock.Any()).
+		Return(&proto.LoginResponse{Success: true, AccessToken: "at", RefreshToken: "rt"}, nil)
+
+	st, err := status.New(codes.Aborted, "data version conflict").
+		WithDetails(&proto.Data{Id: "id1", Name: "server copy", Version: 3})
+	if err != nil {
+		t.Fatalf("WithDetails: %v", err)
+	}
+	dataMock.EXPECT().
+		SaveData(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *proto.SaveDataRequest, _ ...grpc.CallOption) (*proto.SaveDataResponse, error) {
+			if req.BaseVersion != 2 {
+				t.Errorf("BaseVersion = %d, want 2", req.BaseVersion)
+			}
+			return nil, st.Err()
+		})
+
+	c := client.NewClientWithClients(authMock, dataMock)
+	_ = c.Login("u", "p")
+
+	_, _, err = c.SaveData(&proto.Data{Id: "id1", Type: proto.DataType_TEXT, EncryptedData: []byte("x"), Version: 2})
+	if !errors.Is(err, client.ErrVersionConflict) {
+		t.Fatalf("err = %v, want ErrVersionConflict", err)
+	}
+	var conflict *client.ConflictError
+	if !errors.As(err, &conflict) || conflict.Current == nil || conflict.Current.Version != 3 {
+		t.Errorf("conflict = %+v, want server copy", conflict)
+	}
+}
+
+func TestSaveData_EncryptsNameAndMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	authMock := mocks.NewMockAuthServiceClient(ctrl)
+	dataMock := mocks.NewMockDataServiceClient(ctrl)
+
+	authMock.EXPECT().Login(gomock.Any(), gomock.Any()).
+		Return(&proto.LoginResponse{Success: true, AccessToken: "at", RefreshToken: "rt"}, nil)
+	authMock.EXPECT().SetKeyCheck(gomock.Any(), gomock.Any()).
+		Return(&proto.SetKeyCheckResponse{Success: true}, nil)
+
+	var stored *proto.Data
+	dataMock.EXPECT().
+		SaveData(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *proto.SaveDataRequest, _ ...grpc.CallOption) (*proto.SaveDataResponse, error) {
+			stored = req.Data
+			return &proto.SaveDataResponse{Success: true, DataId: req.Data.Id, Version: 1}, nil
+		})
+	dataMock.EXPECT().
+		GetData(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, *proto.GetDataRequest, ...grpc.CallOption) (*proto.GetDataResponse, error) {
+			return &proto.GetDataResponse{Success: true, Data: stored}, nil
+		})
+
+	c := client.NewClientWithClients(authMock, dataMock)
+	_ = c.Login("u", "p")
+	if err := c.UnlockVault("master"); err != nil {
+		t.Fatalf("UnlockVault: %v", err)
+	}
+	c.SetEncryptMetadata(true)
+
+	data := &proto.Data{
+		Id:       "id1",
+		Name:     "Банк",
+		Type:     proto.DataType_TEXT,
+		Metadata: []*proto.Metadata{{Key: "url", Value: "https://bank.example"}},
+	}
+	if _, _, err := c.SaveData(data); err != nil {
+		t.Fatalf("SaveData: %v", err)
+	}
+	if stored.Name != "" || len(stored.Metadata) != 0 || len(stored.EncryptedMeta) == 0 {
+		t.Errorf("на сервер ушли открытые название или метаданные: %+v", stored)
+	}
+	if want, _ := c.Vault().SearchToken("банк"); stored.SearchToken != want {
+		t.Errorf("SearchToken = %q, want token of the name", stored.SearchToken)
+	}
+	if data.Name != "Банк" {
+		t.Error("запись вызывающего не должна меняться")
+	}
+
+	got, err := c.GetData("id1")
+	if err != nil {
+		t.Fatalf("GetData: %v", err)
+	}
+	if got.Name != "Банк" || len(got.Metadata) != 1 || got.Metadata[0].Value != "https://bank.example" {
+		t.Errorf("GetData = %+v, want decrypted name and metadata", got)
+	}
+}
+
 func TestGetData_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -283,6 +660,116 @@ func TestListData_Success(t *testing.T) {
 	}
 }
 
+func TestListDataSummaries_DecryptsNames(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	authMock := mocks.NewMockAuthServiceClient(ctrl)
+	dataMock := mocks.NewMockDataServiceClient(ctrl)
+
+	authMock.EXPECT().Login(gomock.Any(), gomock.Any()).
+		Return(&proto.LoginResponse{Success: true, AccessToken: "at", RefreshToken: "rt"}, nil)
+	authMock.EXPECT().SetKeyCheck(gomock.Any(), gomock.Any()).
+		Return(&proto.SetKeyCheckResponse{Success: true}, nil)
+
+	var stored *proto.Data
+	dataMock.EXPECT().
+		SaveData(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *proto.SaveDataRequest, _ ...grpc.CallOption) (*proto.SaveDataResponse, error) {
+			stored = req.Data
+			return &proto.SaveDataResponse{Success: true, DataId: req.Data.Id, Version: 1}, nil
+		})
+	dataMock.EXPECT().
+		ListDataSummaries(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *proto.ListDataSummariesRequest, _ ...grpc.CallOption) (*proto.ListDataSummariesResponse, error) {
+			if req.Sort != proto.DataSortField_SORT_NAME || req.PageToken != "page-2" {
+				t.Errorf("request = %+v, want name sort and page-2", req)
+			}
+			return &proto.ListDataSummariesResponse{
+				Success: true,
+				Summaries: []*proto.DataSummary{
+					{Id: "id1", Type: proto.DataType_TEXT, EncryptedMeta: stored.EncryptedMeta, Size: 42},
+					{Id: "id2", Type: proto.DataType_TEXT, Name: "Открытое"},
+				},
+				NextPageToken: "page-3",
+			}, nil
+		})
+
+	c := client.NewClientWithClients(authMock, dataMock)
+	_ = c.Login("u", "p")
+	if err := c.UnlockVault("master"); err != nil {
+		t.Fatalf("UnlockVault: %v", err)
+	}
+	c.SetEncryptMetadata(true)
+	if _, _, err := c.SaveData(&proto.Data{Id: "id1", Name: "Банк", Type: proto.DataType_TEXT}); err != nil {
+		t.Fatalf("SaveData: %v", err)
+	}
+
+	page, err := c.ListDataSummaries(&proto.ListDataSummariesRequest{
+		Sort:      proto.DataSortField_SORT_NAME,
+		PageToken: "page-2",
+	})
+	if err != nil {
+		t.Fatalf("ListDataSummaries: %v", err)
+	}
+	if len(page.Items) != 2 || page.NextPageToken != "page-3" {
+		t.Fatalf("page = %+v", page)
+	}
+	if page.Items[0].Name != "Банк" || len(page.Items[0].EncryptedMeta) != 0 || page.Items[0].Size != 42 {
+		t.Errorf("items[0] = %+v, want decrypted name", page.Items[0])
+	}
+	if page.Items[1].Name != "Открытое" {
+		t.Errorf("items[1].Name = %q", page.Items[1].Name)
+	}
+}
+
+// При шифровании метаданных название ищется по токену: сервер не видит названий
+func TestSearchData_UsesSearchTokenForEncryptedNames(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	authMock := mocks.NewMockAuthServiceClient(ctrl)
+	dataMock := mocks.NewMockDataServiceClient(ctrl)
+
+	authMock.EXPECT().Login(gomock.Any(), gomock.Any()).
+		Return(&proto.LoginResponse{Success: true, AccessToken: "at", RefreshToken: "rt"}, nil)
+	authMock.EXPECT().SetKeyCheck(gomock.Any(), gomock.Any()).
+		Return(&proto.SetKeyCheckResponse{Success: true}, nil)
+
+	c := client.NewClientWithClients(authMock, dataMock)
+	_ = c.Login("u", "p")
+	if err := c.UnlockVault("master"); err != nil {
+		t.Fatalf("UnlockVault: %v", err)
+	}
+	c.SetEncryptMetadata(true)
+	token, _ := c.Vault().SearchToken("Банк")
+
+	dataMock.EXPECT().
+		SearchData(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *proto.SearchDataRequest, _ ...grpc.CallOption) (*proto.SearchDataResponse, error) {
+			if req.NameContains != "" || req.SearchToken != token || len(req.Tags) != 1 {
+				t.Errorf("request = %+v, want the search token instead of the name", req)
+			}
+			return &proto.SearchDataResponse{
+				Success:       true,
+				Summaries:     []*proto.DataSummary{{Id: "id1", Type: proto.DataType_TEXT, Name: "Банк"}},
+				NextPageToken: "page-2",
+			}, nil
+		})
+
+	req := &proto.SearchDataRequest{NameContains: "Банк", Tags: []string{"work"}}
+	page, err := c.SearchData(req)
+	if err != nil {
+		t.Fatalf("SearchData: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].Id != "id1" || page.NextPageToken != "page-2" {
+		t.Errorf("page = %+v", page)
+	}
+	if req.NameContains != "Банк" || req.SearchToken != "" {
+		t.Errorf("SearchData changed the caller's request: %+v", req)
+	}
+}
+
 func TestDeleteData_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -315,25 +802,92 @@ func TestSyncData_Success(t *testing.T) {
 	authMock.EXPECT().Login(gomock.Any(), gomock.Any()).
 		Return(&proto.LoginResponse{Success: true, AccessToken: "at", RefreshToken: "rt"}, nil)
 	dataMock.EXPECT().
-		SyncData(gomock.Any(), gomock.Any()).
+		SyncData(gomock.Any(), &syncRequestMatcher{cursor: "c1"}).
 		Return(&proto.SyncDataResponse{
 			Success:    true,
 			Data:       []*proto.Data{{Id: "1", Version: 2}},
-			SyncTime:   200,
+			Deleted:    []*proto.Tombstone{{Id: "2", DeletedAt: 150, Version: 4}},
+			NextCursor: "c2",
 		}, nil)
 
 	c := client.NewClientWithClients(authMock, dataMock)
 	_ = c.Login("u", "p")
 
-	data, syncTime, err := c.SyncData(100)
+	res, err := c.SyncData("c1")
 	if err != nil {
 		t.Fatalf("SyncData: %v", err)
 	}
-	if len(data) != 1 || data[0].Id != "1" || syncTime != 200 {
-		t.Errorf("data=%+v syncTime=%d", data, syncTime)
+	if len(res.Data) != 1 || res.Data[0].Id != "1" || res.NextCursor != "c2" {
+		t.Errorf("data=%+v cursor=%q", res.Data, res.NextCursor)
+	}
+	if len(res.Deleted) != 1 || res.Deleted[0].Id != "2" {
+		t.Errorf("deleted=%+v", res.Deleted)
+	}
+}
+
+func TestSyncAll_FollowsPages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	authMock := mocks.NewMockAuthServiceClient(ctrl)
+	dataMock := mocks.NewMockDataServiceClient(ctrl)
+
+	authMock.EXPECT().Login(gomock.Any(), gomock.Any()).
+		Return(&proto.LoginResponse{Success: true, AccessToken: "at", RefreshToken: "rt"}, nil)
+	gomock.InOrder(
+		dataMock.EXPECT().
+			SyncData(gomock.Any(), &syncRequestMatcher{cursor: ""}).
+			Return(&proto.SyncDataResponse{
+				Success:    true,
+				Data:       []*proto.Data{{Id: "1", Version: 1}, {Id: "2", Version: 1}, {Id: "3", Version: 1}},
+				FullResync: true,
+				NextCursor: "p2",
+				HasMore:    true,
+			}, nil),
+		// Пока шла выдача, запись 1 изменили, а запись 2 удалили
+		dataMock.EXPECT().
+			SyncData(gomock.Any(), &syncRequestMatcher{cursor: "p2"}).
+			Return(&proto.SyncDataResponse{
+				Success:    true,
+				Data:       []*proto.Data{{Id: "1", Version: 2}},
+				Deleted:    []*proto.Tombstone{{Id: "2", Version: 2}},
+				FullResync: true,
+				NextCursor: "p3",
+			}, nil),
+	)
+
+	c := client.NewClientWithClients(authMock, dataMock)
+	_ = c.Login("u", "p")
+
+	res, err := c.SyncAll("")
+	if err != nil {
+		t.Fatalf("SyncAll: %v", err)
+	}
+	if !res.FullResync || res.HasMore || res.NextCursor != "p3" {
+		t.Errorf("result = %+v", res)
+	}
+	if len(res.Data) != 2 || res.Data[0].Id != "1" || res.Data[0].Version != 2 || res.Data[1].Id != "3" {
+		t.Errorf("data = %+v", res.Data)
+	}
+	if len(res.Deleted) != 1 || res.Deleted[0].Id != "2" {
+		t.Errorf("deleted = %+v", res.Deleted)
 	}
 }
 
+// syncRequestMatcher сравнивает курсор в SyncDataRequest
+type syncRequestMatcher struct {
+	cursor string
+}
+
+func (m *syncRequestMatcher) Matches(x any) bool {
+	req, ok := x.(*proto.SyncDataRequest)
+	return ok && req.Cursor == m.cursor
+}
+
+func (m *syncRequestMatcher) String() string {
+	return "sync request with cursor " + m.cursor
+}
+
 func TestClose_NoConnection(t *testing.T) {
 	c := client.NewClientWithClients(nil, nil)
 	// Close при отсутствии conn не должен паниковать
@@ -341,3 +895,152 @@ func TestClose_NoConnection(t *testing.T) {
 		t.Errorf("Close: %v", err)
 	}
 }
+
+func TestResume_KeepsOfflineVault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, keyCheck, err := vault.Create("master")
+	if err != nil {
+		t.Fatalf("vault.Create: %v", err)
+	}
+
+	authMock := mocks.NewMockAuthServiceClient(ctrl)
+	dataMock := mocks.NewMockDataServiceClient(ctrl)
+	c := client.NewClientWithClients(authMock, dataMock)
+
+	if err := c.UnlockVaultOffline("wrong", keyCheck); !errors.Is(err, vault.ErrWrongMasterPassword) {
+		t.Fatalf("UnlockVaultOffline(wrong) = %v, want ErrWrongMasterPassword", err)
+	}
+	if err := c.UnlockVaultOffline("master", keyCheck); err != nil {
+		t.Fatalf("UnlockVaultOffline: %v", err)
+	}
+	if c.IsAuthenticated() || !c.IsVaultUnlocked() {
+		t.Fatal("хранилище должно быть открыто без входа на сервер")
+	}
+
+	authMock.EXPECT().Login(gomock.Any(), gomock.Any()).
+		Return(&proto.LoginResponse{Success: true, AccessToken: "at", RefreshToken: "rt", KeyCheck: keyCheck}, nil)
+	if err := c.Resume("u", "p"); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	if !c.IsAuthenticated() || !c.IsVaultUnlocked() {
+		t.Error("после Resume клиент должен быть авторизован с открытым хранилищем")
+	}
+}
+
+func TestResume_KeyCheckChangedElsewhere(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, keyCheck, _ := vault.Create("master")
+	_, otherKeyCheck, _ := vault.Create("other")
+
+	authMock := mocks.NewMockAuthServiceClient(ctrl)
+	authMock.EXPECT().Login(gomock.Any(), gomock.Any()).
+		Return(&proto.LoginResponse{Success: true, AccessToken: "at", KeyCheck: otherKeyCheck}, nil)
+
+	c := client.NewClientWithClients(authMock, mocks.NewMockDataServiceClient(ctrl))
+	_ = c.UnlockVaultOffline("master", keyCheck)
+
+	if err := c.Resume("u", "p"); !errors.Is(err, client.ErrSessionChanged) {
+		t.Fatalf("Resume = %v, want ErrSessionChanged", err)
+	}
+	if c.IsVaultUnlocked() {
+		t.Error("хранилище должно закрыться, мастер-пароль нужно ввести заново")
+	}
+}
+
+func TestIsUnavailable(t *testing.T) {
+	if !client.IsUnavailable(status.Error(codes.Unavailable, "down")) || !client.IsUnavailable(status.Error(codes.DeadlineExceeded, "slow")) {
+		t.Error("Unavailable и DeadlineExceeded означают отсутствие связи")
+	}
+	if client.IsUnavailable(status.Error(codes.PermissionDenied, "no")) || client.IsUnavailable(nil) {
+		t.Error("остальные ошибки не означают отсутствие связи")
+	}
+}
+
+// fakeUploadStream собирает части, отправленные UploadBlob
+type fakeUploadStream struct {
+	grpc.ClientStream
+	content []byte
+}
+
+func (s *fakeUploadStream) Send(chunk *proto.BlobChunk) error {
+	s.content = append(s.content, chunk.Content...)
+	return nil
+}
+
+func (s *fakeUploadStream) CloseAndRecv() (*proto.UploadBlobResponse, error) {
+	return &proto.UploadBlobResponse{Success: true, BlobId: "blob-1", Size: int64(len(s.content))}, nil
+}
+
+// fakeDownloadStream отдаёт содержимое частями заданного размера
+type fakeDownloadStream struct {
+	grpc.ClientStream
+	content []byte
+	chunk   int
+}
+
+func (s *fakeDownloadStream) Recv() (*proto.BlobChunk, error) {
+	if len(s.content) == 0 {
+		return nil, io.EOF
+	}
+	n := min(s.chunk, len(s.content))
+	chunk := &proto.BlobChunk{Content: s.content[:n]}
+	s.content = s.content[n:]
+	return chunk, nil
+}
+
+func TestUploadAndDownloadBlob_RoundTrip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	authMock := mocks.NewMockAuthServiceClient(ctrl)
+	dataMock := mocks.NewMockDataServiceClient(ctrl)
+	authMock.EXPECT().Login(gomock.Any(), gomock.Any()).
+		Return(&proto.LoginResponse{Success: true, AccessToken: "at", RefreshToken: "rt"}, nil)
+	authMock.EXPECT().SetKeyCheck(gomock.Any(), gomock.Any()).
+		Return(&proto.SetKeyCheckResponse{Success: true}, nil)
+
+	c := client.NewClientWithClients(authMock, dataMock)
+	_ = c.Login("u", "p")
+	if err := c.UnlockVault("master"); err != nil {
+		t.Fatalf("UnlockVault: %v", err)
+	}
+
+	// Файл больше одной части шифрования
+	file := bytes.Repeat([]byte("0123456789abcdef"), 10000)
+	upload := &fakeUploadStream{}
+	dataMock.EXPECT().UploadBlob(gomock.Any()).Return(upload, nil)
+	info, err := c.UploadBlob(bytes.NewReader(file))
+	if err != nil {
+		t.Fatalf("UploadBlob: %v", err)
+	}
+	sum := sha256.Sum256(file)
+	if info.ID != "blob-1" || info.Size != int64(len(file)) || info.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("info = %+v, want blob-1 with size and hash of the file", info)
+	}
+	if bytes.Contains(upload.content, file[:64]) {
+		t.Error("сервер получил файл в открытом виде")
+	}
+
+	dataMock.EXPECT().DownloadBlob(gomock.Any(), &proto.DownloadBlobRequest{BlobId: "blob-1"}).
+		Return(&fakeDownloadStream{content: upload.content, chunk: 1000}, nil)
+	var out bytes.Buffer
+	n, err := c.DownloadBlob("blob-1", info.SHA256, &out)
+	if err != nil {
+		t.Fatalf("DownloadBlob: %v", err)
+	}
+	if n != int64(len(file)) || !bytes.Equal(out.Bytes(), file) {
+		t.Errorf("downloaded %d bytes, want the original file", n)
+	}
+
+	// Подмена содержимого другим файлом обнаруживается по хешу из записи
+	dataMock.EXPECT().DownloadBlob(gomock.Any(), gomock.Any()).
+		Return(&fakeDownloadStream{content: upload.content, chunk: 1000}, nil)
+	other := sha256.Sum256([]byte("другой файл"))
+	if _, err := c.DownloadBlob("blob-1", hex.EncodeToString(other[:]), io.Discard); !errors.Is(err, client.ErrBlobCorrupted) {
+		t.Errorf("DownloadBlob with wrong hash = %v, want ErrBlobCorrupted", err)
+	}
+}
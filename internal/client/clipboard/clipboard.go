@@ -0,0 +1,112 @@
+// Package clipboard копирует секреты в буфер обмена: системный (xclip, xsel, wl-copy, pbcopy и т.п.)
+// или буфер терминала через escape-последовательность OSC52, которая работает и по SSH.
+package clipboard
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// Method — способ, которым текст попал в буфер обмена
+type Method int
+
+const (
+	MethodNone Method = iota
+	MethodSystem
+	MethodOSC52
+)
+
+// System — системный буфер обмена (реализуется пакетом atotto/clipboard)
+type System interface {
+	ReadAll() (string, error)
+	WriteAll(text string) error
+}
+
+// Clipboard копирует текст в системный буфер, а в удалённом сеансе или без системного буфера —
+// в буфер терминала через OSC52. Не безопасен для одновременного использования из нескольких горутин.
+type Clipboard struct {
+	system System
+	term   io.Writer
+	getenv func(string) string
+	// last — способ последнего копирования: им же буфер и очищается
+	last Method
+}
+
+// New создаёт Clipboard, который пишет последовательности OSC52 в term (обычно os.Stderr)
+func New(term io.Writer) *Clipboard {
+	var system System
+	if !clipboard.Unsupported {
+		system = atottoClipboard{}
+	}
+	return NewWith(system, term, os.Getenv)
+}
+
+// NewWith создаёт Clipboard с заданными системным буфером (nil — его нет), терминалом и окружением
+func NewWith(system System, term io.Writer, getenv func(string) string) *Clipboard {
+	return &Clipboard{system: system, term: term, getenv: getenv}
+}
+
+// Copy помещает text в буфер обмена и возвращает использованный способ.
+// В сеансе SSH системный буфер удалённой машины пользователю недоступен, поэтому сразу используется OSC52;
+// OSC52 используется и тогда, когда системный буфер не сработал.
+func (c *Clipboard) Copy(text string) (Method, error) {
+	if c.system != nil && !c.remote() {
+		if err := c.system.WriteAll(text); err == nil {
+			c.last = MethodSystem
+			return MethodSystem, nil
+		}
+	}
+	if c.term == nil {
+		return MethodNone, errors.New("буфер обмена недоступен")
+	}
+	if _, err := c.sequence(osc52.New(text)).WriteTo(c.term); err != nil {
+		return MethodNone, err
+	}
+	c.last = MethodOSC52
+	return MethodOSC52, nil
+}
+
+// Clear очищает буфер обмена после Copy(text). Системный буфер очищается, только если в нём
+// по-прежнему text, чтобы не стереть то, что пользователь скопировал позже; содержимое буфера
+// терминала прочитать нельзя, поэтому он очищается всегда.
+func (c *Clipboard) Clear(text string) error {
+	method := c.last
+	c.last = MethodNone
+	switch method {
+	case MethodSystem:
+		if current, err := c.system.ReadAll(); err == nil && current != text {
+			return nil
+		}
+		return c.system.WriteAll("")
+	case MethodOSC52:
+		_, err := c.sequence(osc52.Clear()).WriteTo(c.term)
+		return err
+	}
+	return nil
+}
+
+// remote сообщает, что клиент запущен в сеансе SSH
+func (c *Clipboard) remote() bool {
+	return c.getenv("SSH_TTY") != "" || c.getenv("SSH_CONNECTION") != ""
+}
+
+// sequence оборачивает OSC52 для tmux и screen, которые иначе не передают её терминалу
+func (c *Clipboard) sequence(seq osc52.Sequence) osc52.Sequence {
+	switch {
+	case c.getenv("TMUX") != "":
+		return seq.Tmux()
+	case c.getenv("STY") != "":
+		return seq.Screen()
+	}
+	return seq
+}
+
+// atottoClipboard — системный буфер обмена через внешние утилиты
+type atottoClipboard struct{}
+
+func (atottoClipboard) ReadAll() (string, error)   { return clipboard.ReadAll() }
+func (atottoClipboard) WriteAll(text string) error { return clipboard.WriteAll(text) }
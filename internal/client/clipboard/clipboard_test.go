@@ -0,0 +1,114 @@
+package clipboard_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gophkeeper/gophkeeper/internal/client/clipboard"
+)
+
+type fakeSystem struct {
+	content  string
+	writeErr error
+}
+
+func (f *fakeSystem) ReadAll() (string, error) { return f.content, nil }
+
+func (f *fakeSystem) WriteAll(text string) error {
+	if f.writeErr != nil {
+		return f.writeErr
+	}
+	f.content = text
+	return nil
+}
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func TestCopy_SystemClipboard(t *testing.T) {
+	system := &fakeSystem{}
+	var term bytes.Buffer
+	c := clipboard.NewWith(system, &term, env(nil))
+
+	method, err := c.Copy("s3cret")
+	if err != nil || method != clipboard.MethodSystem {
+		t.Fatalf("Copy: method %v, err %v", method, err)
+	}
+	if system.content != "s3cret" || term.Len() != 0 {
+		t.Fatalf("system %q, term %q", system.content, term.String())
+	}
+
+	if err := c.Clear("s3cret"); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if system.content != "" {
+		t.Errorf("clipboard not cleared: %q", system.content)
+	}
+}
+
+func TestClear_KeepsLaterCopy(t *testing.T) {
+	system := &fakeSystem{}
+	c := clipboard.NewWith(system, nil, env(nil))
+
+	if _, err := c.Copy("s3cret"); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	system.content = "copied by user"
+	if err := c.Clear("s3cret"); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if system.content != "copied by user" {
+		t.Errorf("later copy overwritten: %q", system.content)
+	}
+}
+
+func TestCopy_OSC52OverSSH(t *testing.T) {
+	system := &fakeSystem{}
+	var term bytes.Buffer
+	c := clipboard.NewWith(system, &term, env(map[string]string{"SSH_TTY": "/dev/pts/1"}))
+
+	method, err := c.Copy("s3cret")
+	if err != nil || method != clipboard.MethodOSC52 {
+		t.Fatalf("Copy: method %v, err %v", method, err)
+	}
+	if system.content != "" {
+		t.Errorf("remote system clipboard used: %q", system.content)
+	}
+	want := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte("s3cret")) + "\x07"
+	if term.String() != want {
+		t.Errorf("sequence %q, want %q", term.String(), want)
+	}
+
+	term.Reset()
+	if err := c.Clear("s3cret"); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if !strings.HasPrefix(term.String(), "\x1b]52;c;") || strings.Contains(term.String(), want) {
+		t.Errorf("clear sequence %q", term.String())
+	}
+}
+
+func TestCopy_FallsBackToOSC52(t *testing.T) {
+	system := &fakeSystem{writeErr: errors.New("no xclip")}
+	var term bytes.Buffer
+	c := clipboard.NewWith(system, &term, env(map[string]string{"TMUX": "/tmp/tmux-1000/default"}))
+
+	method, err := c.Copy("s3cret")
+	if err != nil || method != clipboard.MethodOSC52 {
+		t.Fatalf("Copy: method %v, err %v", method, err)
+	}
+	if !strings.HasPrefix(term.String(), "\x1bPtmux;") {
+		t.Errorf("sequence not wrapped for tmux: %q", term.String())
+	}
+}
+
+func TestCopy_Unavailable(t *testing.T) {
+	c := clipboard.NewWith(nil, nil, env(nil))
+	if _, err := c.Copy("s3cret"); err == nil {
+		t.Fatal("expected error without system clipboard and terminal")
+	}
+}
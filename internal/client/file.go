@@ -0,0 +1,90 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gophkeeper/gophkeeper/internal/client/format"
+	"github.com/gophkeeper/gophkeeper/proto"
+)
+
+// AttachFile шифрует и загружает файл path на сервер. Возвращает поля содержимого записи
+// (имя, права, размер и хеш файла) и ID загруженного содержимого для Data.BlobId.
+func (c *Client) AttachFile(path string) (map[string]string, string, error) {
+	if !c.IsAuthenticated() {
+		return nil, "", errors.New("для прикрепления файла нужно подключение к серверу")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, "", err
+	}
+	if info.IsDir() {
+		return nil, "", fmt.Errorf("%s — каталог, а не файл", path)
+	}
+
+	blob, err := c.UploadBlob(f)
+	if err != nil {
+		return nil, "", fmt.Errorf("загрузка файла: %w", err)
+	}
+	return format.FileFields(filepath.Base(path), info.Mode(), blob.Size, blob.SHA256), blob.ID, nil
+}
+
+// ExportFile выгружает файл записи в каталог dir под исходным именем и с исходными правами.
+// Содержимое сначала пишется во временный файл и переименовывается только после проверки хеша;
+// существующий файл не перезаписывается. Возвращает путь к сохранённому файлу.
+func (c *Client) ExportFile(data *proto.Data, dir string) (string, error) {
+	payload, err := c.Vault().Decrypt(data.EncryptedData)
+	if err != nil {
+		return "", fmt.Errorf("расшифровка записи: %w", err)
+	}
+	fields, err := format.ParsePayload(data.Type, payload)
+	if err != nil {
+		return "", fmt.Errorf("декодирование записи: %w", err)
+	}
+	if data.BlobId == "" || fields[format.FieldFileName] == "" {
+		return "", errors.New("к записи не прикреплён файл")
+	}
+
+	// Имя из записи не должно выводить за пределы выбранного каталога
+	name := filepath.Base(fields[format.FieldFileName])
+	if name == "." || name == ".." || name == string(filepath.Separator) {
+		return "", fmt.Errorf("недопустимое имя файла %q", fields[format.FieldFileName])
+	}
+	target := filepath.Join(dir, name)
+	if _, err := os.Lstat(target); err == nil {
+		return "", fmt.Errorf("файл %s уже существует", target)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+name+".*.part")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := c.DownloadBlob(data.BlobId, fields[format.FieldFileSHA256], tmp); err != nil {
+		_ = tmp.Close()
+		if errors.Is(err, ErrBlobCorrupted) {
+			return "", errors.New("содержимое файла на сервере повреждено или подменено")
+		}
+		return "", fmt.Errorf("выгрузка файла: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(tmp.Name(), format.FileMode(fields)); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return "", err
+	}
+	return target, nil
+}
@@ -1,52 +1,95 @@
 package format
 
 import (
-	"encoding/json"
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/gophkeeper/gophkeeper/proto"
 )
 
-// DataContentToDisplayString декодирует EncryptedData и возвращает читаемый текст по типу записи.
-func DataContentToDisplayString(data *proto.Data) string {
+// Decrypter расшифровывает содержимое записи (реализуется vault.Vault).
+type Decrypter interface {
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// DataContentToDisplayString расшифровывает и декодирует EncryptedData и возвращает читаемый текст по типу записи.
+// Без reveal секретные поля (пароль, номер карты кроме последних цифр, CVV, ключ TOTP) заменяются маской.
+func DataContentToDisplayString(data *proto.Data, dec Decrypter, reveal bool) string {
 	if data == nil || len(data.EncryptedData) == 0 {
 		return ""
 	}
-	payload := data.EncryptedData
+	if dec == nil {
+		return "  (хранилище заблокировано: введите мастер-пароль)"
+	}
+	payload, err := dec.Decrypt(data.EncryptedData)
+	if err != nil {
+		return fmt.Sprintf("  (ошибка расшифровки: %v)", err)
+	}
 
-	switch data.Type {
-	case proto.DataType_LOGIN_PASSWORD:
-		var v struct {
-			Login    string `json:"login"`
-			Password string `json:"password"`
-		}
-		if err := json.Unmarshal(payload, &v); err != nil {
-			return fmt.Sprintf("  (ошибка декодирования: %v)", err)
-		}
-		return fmt.Sprintf("  Логин:    %s\n  Пароль:   %s", v.Login, v.Password)
-	case proto.DataType_TEXT:
-		var v struct {
-			Text string `json:"text"`
-		}
-		if err := json.Unmarshal(payload, &v); err != nil {
-			return fmt.Sprintf("  %s", string(payload))
-		}
-		return "  " + v.Text
-	case proto.DataType_BANK_CARD:
-		var v struct {
-			Number string `json:"number"`
-			Expiry string `json:"expiry"`
-			CVV    string `json:"cvv"`
-			Holder string `json:"holder"`
+	rt := LookupRecordType(data.Type)
+	if rt == nil {
+		return "  " + string(payload)
+	}
+	fields, err := ParsePayload(data.Type, payload)
+	if err != nil {
+		return fmt.Sprintf("  (ошибка декодирования: %v)", err)
+	}
+	if rt.Display != nil {
+		return rt.Display(fields, reveal)
+	}
+	return fieldsContent(rt, fields, reveal)
+}
+
+// fieldsContent показывает поля по порядку с выровненными подписями. Единственное многострочное
+// поле (текст) показывается без подписи, многострочные поля среди других — с отступом под подписью.
+func fieldsContent(rt *RecordType, fields map[string]string, reveal bool) string {
+	if len(rt.Fields) == 1 && rt.Fields[0].Multiline {
+		return indent(fields[rt.Fields[0].Key], "  ")
+	}
+	width := 0
+	for _, f := range rt.Fields {
+		width = max(width, utf8.RuneCountInString(f.Label)+1)
+	}
+	lines := make([]string, 0, len(rt.Fields))
+	for _, f := range rt.Fields {
+		value := f.MaskValue(fields[f.Key], reveal)
+		if f.Multiline {
+			lines = append(lines, "  "+f.Label+":", indent(value, "    "))
+			continue
 		}
-		if err := json.Unmarshal(payload, &v); err != nil {
-			return fmt.Sprintf("  (ошибка декодирования: %v)", err)
+		label := f.Label + ":" + strings.Repeat(" ", width-utf8.RuneCountInString(f.Label)-1)
+		lines = append(lines, "  "+label+" "+value)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indent добавляет prefix к каждой строке s
+func indent(s, prefix string) string {
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
+
+// secretMask заменяет скрытое значение; длина маски не зависит от длины значения
+const secretMask = "••••••••"
+
+// MaskSecret скрывает значение секретного поля (пустое значение остаётся пустым).
+func MaskSecret(s string) string {
+	if s == "" {
+		return ""
+	}
+	return secretMask
+}
+
+// MaskCardNumber скрывает номер карты, оставляя последние четыре цифры.
+func MaskCardNumber(number string) string {
+	digits := make([]rune, 0, len(number))
+	for _, r := range number {
+		if r >= '0' && r <= '9' {
+			digits = append(digits, r)
 		}
-		return fmt.Sprintf("  Номер:    %s\n  Срок:     %s\n  CVV:      %s\n  Держатель: %s",
-			v.Number, v.Expiry, v.CVV, v.Holder)
-	case proto.DataType_BINARY:
-		return "  " + string(payload)
-	default:
-		return "  " + string(payload)
 	}
+	if len(digits) <= 4 {
+		return MaskSecret(number)
+	}
+	return "•••• " + string(digits[len(digits)-4:])
 }
@@ -7,7 +7,8 @@ import (
 	"github.com/gophkeeper/gophkeeper/proto"
 )
 
-// BuildDataForSave создаёт *proto.Data для сохранения (id, timestamps, version).
+// BuildDataForSave создаёт *proto.Data для новой записи (id, timestamps).
+// Version остаётся 0: при сохранении она передаётся серверу как базовая, а 0 означает создание записи.
 func BuildDataForSave(name string, dataType proto.DataType, encryptedPayload []byte) *proto.Data {
 	now := time.Now().Unix()
 	return &proto.Data{
@@ -17,6 +18,5 @@ func BuildDataForSave(name string, dataType proto.DataType, encryptedPayload []b
 		EncryptedData: encryptedPayload,
 		CreatedAt:     now,
 		UpdatedAt:     now,
-		Version:       1,
 	}
 }
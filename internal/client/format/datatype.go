@@ -4,55 +4,49 @@ import "github.com/gophkeeper/gophkeeper/proto"
 
 // DataTypeDisplayName возвращает человекочитаемое имя типа данных.
 func DataTypeDisplayName(dt proto.DataType) string {
-	switch dt {
-	case proto.DataType_LOGIN_PASSWORD:
-		return "Логин/Пароль"
-	case proto.DataType_TEXT:
-		return "Текст"
-	case proto.DataType_BINARY:
-		return "Бинарные"
-	case proto.DataType_BANK_CARD:
-		return "Банковская карта"
-	default:
-		return "Неизвестно"
+	if rt := LookupRecordType(dt); rt != nil {
+		return rt.Name
 	}
+	return "Неизвестно"
 }
 
 // DataTypeDisplayNames возвращает список отображаемых имён типов в порядке,
-// соответствующем индексу выбора в UI (Логин/Пароль, Текст, Бинарные данные, Банковская карта).
+// соответствующем индексу выбора в UI (порядок регистрации типов).
 func DataTypeDisplayNames() []string {
-	return []string{
-		"Логин/Пароль",
-		"Текст",
-		"Бинарные данные",
-		"Банковская карта",
+	names := make([]string, 0, len(recordTypes))
+	for _, rt := range recordTypes {
+		names = append(names, rt.DisplayFormName())
 	}
+	return names
 }
 
-// DataTypeFromIndex возвращает proto.DataType по индексу в списке типов (0..3).
+// DataTypeFromIndex возвращает proto.DataType по индексу в списке типов.
 func DataTypeFromIndex(i int) proto.DataType {
-	switch i {
-	case 0:
-		return proto.DataType_LOGIN_PASSWORD
-	case 1:
-		return proto.DataType_TEXT
-	case 2:
-		return proto.DataType_BINARY
-	case 3:
-		return proto.DataType_BANK_CARD
+	if i < 0 || i >= len(recordTypes) {
+		return proto.DataType_UNKNOWN
 	}
-	return proto.DataType_UNKNOWN
+	return recordTypes[i].Type
+}
+
+// DataTypeIndex возвращает индекс типа в списке типов — обратное к DataTypeFromIndex (-1 для неизвестного типа).
+func DataTypeIndex(dt proto.DataType) int {
+	for i, rt := range recordTypes {
+		if rt.Type == dt {
+			return i
+		}
+	}
+	return -1
 }
 
 // FieldCount возвращает количество полей ввода для типа данных (для формы добавления).
+// У записи с файлом одно поле — путь к файлу.
 func FieldCount(dt proto.DataType) int {
-	switch dt {
-	case proto.DataType_LOGIN_PASSWORD:
-		return 2
-	case proto.DataType_TEXT, proto.DataType_BINARY:
+	rt := LookupRecordType(dt)
+	switch {
+	case rt == nil:
+		return 0
+	case rt.File:
 		return 1
-	case proto.DataType_BANK_CARD:
-		return 4
 	}
-	return 0
+	return len(rt.Fields)
 }
@@ -2,6 +2,7 @@ package format
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/gophkeeper/gophkeeper/proto"
 )
@@ -20,3 +21,31 @@ func MetadataToDisplayLines(metadata []*proto.Metadata) []string {
 	}
 	return lines
 }
+
+// CommonMetadataKeys — часто используемые ключи метаданных (подсказки в редакторе).
+var CommonMetadataKeys = []string{"url", "username", "notes", "tags"}
+
+// NormalizeMetadata проверяет метаданные перед сохранением: пробелы по краям ключа убираются,
+// пустые строки пропускаются. Ошибка, если у значения нет ключа или ключ повторяется (без учёта регистра).
+func NormalizeMetadata(metadata []*proto.Metadata) ([]*proto.Metadata, error) {
+	result := make([]*proto.Metadata, 0, len(metadata))
+	seen := make(map[string]bool, len(metadata))
+	for _, md := range metadata {
+		if md == nil {
+			continue
+		}
+		key := strings.TrimSpace(md.Key)
+		if key == "" {
+			if strings.TrimSpace(md.Value) == "" {
+				continue
+			}
+			return nil, fmt.Errorf("у значения %q не указан ключ", md.Value)
+		}
+		if seen[strings.ToLower(key)] {
+			return nil, fmt.Errorf("ключ %q указан несколько раз", key)
+		}
+		seen[strings.ToLower(key)] = true
+		result = append(result, &proto.Metadata{Key: key, Value: md.Value})
+	}
+	return result, nil
+}
@@ -2,6 +2,8 @@ package format
 
 import (
 	"encoding/json"
+	"io/fs"
+	"strconv"
 
 	"github.com/gophkeeper/gophkeeper/proto"
 )
@@ -16,28 +18,118 @@ const (
 	FieldExpiry   = "expiry"
 	FieldCVV      = "cvv"
 	FieldHolder   = "holder"
+	FieldOTP      = "otp" // otpauth:// URI или секрет Base32
+
+	// Поля записи SSH_KEY; ключи в формате OpenSSH
+	FieldSSHPrivateKey = "private_key"
+	FieldSSHPublicKey  = "public_key"
+	FieldSSHPassphrase = "passphrase"
+	FieldSSHComment    = "comment"
+
+	// Поля записи BINARY с прикреплённым файлом; само содержимое хранится отдельно (Data.BlobId)
+	FieldFileName   = "file_name"
+	FieldFileMode   = "file_mode"
+	FieldFileSize   = "file_size"
+	FieldFileSHA256 = "file_sha256"
 )
 
-// BuildPayload собирает EncryptedData из полей формы по типу данных.
+// fileFieldKeys — поля содержимого записи с прикреплённым файлом
+var fileFieldKeys = []string{FieldFileName, FieldFileMode, FieldFileSize, FieldFileSHA256}
+
+// fileFieldLabels — подписи полей прикреплённого файла
+var fileFieldLabels = map[string]string{
+	FieldFileName:   "Файл",
+	FieldFileMode:   "Права",
+	FieldFileSize:   "Размер",
+	FieldFileSHA256: "SHA-256",
+}
+
+// BuildPayload собирает EncryptedData из полей формы по типу данных: JSON с полями типа.
 // fields — значения полей по ключам (FieldLogin, FieldPassword, FieldText и т.д.).
+// У записи с файлом сохраняются поля файла, а без файла — содержимое RawField как есть.
 func BuildPayload(dataType proto.DataType, fields map[string]string) ([]byte, error) {
-	switch dataType {
-	case proto.DataType_LOGIN_PASSWORD:
-		return json.Marshal(map[string]string{
-			"login":    fields[FieldLogin],
-			"password": fields[FieldPassword],
-		})
-	case proto.DataType_TEXT:
-		return json.Marshal(map[string]string{"text": fields[FieldText]})
-	case proto.DataType_BINARY:
-		return []byte(fields[FieldBinary]), nil
-	case proto.DataType_BANK_CARD:
-		return json.Marshal(map[string]string{
-			"number": fields[FieldNumber],
-			"expiry": fields[FieldExpiry],
-			"cvv":    fields[FieldCVV],
-			"holder": fields[FieldHolder],
-		})
-	}
-	return []byte{}, nil
+	rt := LookupRecordType(dataType)
+	if rt == nil {
+		return []byte{}, nil
+	}
+	if rt.File {
+		if fields[FieldFileName] == "" {
+			return []byte(fields[rt.RawField]), nil
+		}
+		values := make(map[string]string, len(fileFieldKeys))
+		for _, key := range fileFieldKeys {
+			values[key] = fields[key]
+		}
+		return json.Marshal(values)
+	}
+	values := make(map[string]string, len(rt.Fields))
+	for _, f := range rt.Fields {
+		values[f.Key] = fields[f.Key]
+	}
+	return json.Marshal(values)
+}
+
+// PayloadFields возвращает ключи полей содержимого для типа данных в порядке отображения.
+func PayloadFields(dataType proto.DataType) []string {
+	rt := LookupRecordType(dataType)
+	if rt == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(rt.Fields))
+	for _, f := range rt.Fields {
+		keys = append(keys, f.Key)
+	}
+	return keys
+}
+
+// FieldLabel возвращает подпись поля содержимого.
+func FieldLabel(key string) string {
+	if label, ok := fileFieldLabels[key]; ok {
+		return label
+	}
+	for _, rt := range recordTypes {
+		if f := rt.Field(key); f != nil {
+			return f.Label
+		}
+	}
+	return key
+}
+
+// ParsePayload раскладывает расшифрованное содержимое на поля — обратное к BuildPayload.
+// Содержимое не в JSON возвращается целиком в RawField типа (текст, данные BINARY без файла);
+// у типов без RawField это ошибка.
+func ParsePayload(dataType proto.DataType, payload []byte) (map[string]string, error) {
+	rt := LookupRecordType(dataType)
+	if rt == nil {
+		return map[string]string{}, nil
+	}
+	var fields map[string]string
+	err := json.Unmarshal(payload, &fields)
+	if rt.RawField != "" && (err != nil || rt.File && fields[FieldFileName] == "") {
+		return map[string]string{rt.RawField: string(payload)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// FileFields возвращает поля содержимого записи BINARY для прикреплённого файла.
+// Права хранятся восьмеричной строкой, размер — в байтах.
+func FileFields(name string, mode fs.FileMode, size int64, sha256Hex string) map[string]string {
+	return map[string]string{
+		FieldFileName:   name,
+		FieldFileMode:   strconv.FormatUint(uint64(mode.Perm()), 8),
+		FieldFileSize:   strconv.FormatInt(size, 10),
+		FieldFileSHA256: sha256Hex,
+	}
+}
+
+// FileMode возвращает права прикреплённого файла из полей содержимого (0600, если они не указаны)
+func FileMode(fields map[string]string) fs.FileMode {
+	mode, err := strconv.ParseUint(fields[FieldFileMode], 8, 32)
+	if err != nil {
+		return 0o600
+	}
+	return fs.FileMode(mode).Perm()
 }
@@ -0,0 +1,161 @@
+package format
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gophkeeper/gophkeeper/proto"
+)
+
+// Field описывает поле содержимого записи: по нему строятся поле ввода формы, строка просмотра
+// и клавиша копирования.
+type Field struct {
+	Key   string
+	Label string
+	// Placeholder — подсказка в пустом поле ввода (по умолчанию Label)
+	Placeholder string
+	// Secret — значение вводится скрыто (в однострочном поле), при просмотре заменяется маской
+	// (Mask, по умолчанию MaskSecret)
+	Secret bool
+	Mask   func(string) string
+	// Multiline — многострочное значение (ввод в textarea)
+	Multiline bool
+	// CharLimit — ограничение длины значения (0 — по умолчанию)
+	CharLimit int
+	// Validate проверяет значение перед сохранением (nil — подходит любое)
+	Validate func(string) error
+	// Preview — строка под полем ввода, например текущий код для ключа TOTP ("" — не показывать)
+	Preview func(string) string
+	// Generate — для поля доступен генератор паролей
+	Generate bool
+	// CopyKey — клавиша копирования при просмотре записи; не должна совпадать с общими клавишами
+	// просмотра (e, x, d, v, q). CopyLabel — название в сообщении о копировании (по умолчанию Label),
+	// CopyValue — что копировать вместо значения (nil — само значение)
+	CopyKey   string
+	CopyLabel string
+	CopyValue func(string) (string, error)
+}
+
+// MaskValue возвращает значение для просмотра: секрет без reveal заменяется маской
+func (f *Field) MaskValue(value string, reveal bool) string {
+	if !f.Secret || reveal {
+		return value
+	}
+	if f.Mask != nil {
+		return f.Mask(value)
+	}
+	return MaskSecret(value)
+}
+
+// CopyName возвращает название поля в сообщении о копировании
+func (f *Field) CopyName() string {
+	if f.CopyLabel != "" {
+		return f.CopyLabel
+	}
+	return f.Label
+}
+
+// RecordType описывает тип записи на клиенте: название, поля содержимого и их отображение.
+// Формы добавления и редактирования, просмотр и копирование строятся по этому описанию.
+type RecordType struct {
+	Type proto.DataType
+	// Key — латинское имя типа в командной строке и JSON (login, card, ...)
+	Key string
+	// Name — короткое название в списке и при просмотре; FormName — в выборе типа при добавлении (по умолчанию Name)
+	Name     string
+	FormName string
+	Fields   []Field
+	// RawField — поле, в которое попадает содержимое, сохранённое не в JSON (записи старого формата)
+	RawField string
+	// File — содержимое записи — прикреплённый файл: форма предлагает выбрать файл вместо ввода полей,
+	// а содержимое без файла сохраняется как есть в RawField
+	File bool
+	// Display — собственное отображение расшифрованных полей (nil — поля по порядку с подписями)
+	Display func(fields map[string]string, reveal bool) string
+	// Live — отображение меняется со временем (код TOTP): экран просмотра перерисовывается каждую секунду
+	Live bool
+	// Validate проверяет поля вместе, например CVV по платёжной системе номера (nil — только Field.Validate).
+	// Ошибка *FieldError указывает поле, к которому она относится
+	Validate func(fields map[string]string) error
+	// Badge — пометка записи в списке по содержимому, например «истёк» у карты ("" — без пометки)
+	Badge func(fields map[string]string, now time.Time) string
+}
+
+// FieldError — ошибка проверки значения поля Key
+type FieldError struct {
+	Key string
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Field возвращает описание поля key или nil
+func (t *RecordType) Field(key string) *Field {
+	for i := range t.Fields {
+		if t.Fields[i].Key == key {
+			return &t.Fields[i]
+		}
+	}
+	return nil
+}
+
+// CopyFields возвращает поля, которые копируются в буфер обмена при просмотре записи
+func (t *RecordType) CopyFields() []*Field {
+	var fields []*Field
+	for i := range t.Fields {
+		if t.Fields[i].CopyKey != "" {
+			fields = append(fields, &t.Fields[i])
+		}
+	}
+	return fields
+}
+
+// DisplayFormName возвращает название типа для выбора при добавлении
+func (t *RecordType) DisplayFormName() string {
+	if t.FormName != "" {
+		return t.FormName
+	}
+	return t.Name
+}
+
+var (
+	recordTypes  []*RecordType
+	recordByType = map[proto.DataType]*RecordType{}
+	recordByKey  = map[string]*RecordType{}
+)
+
+// Register добавляет тип записи. Порядок регистрации задаёт порядок типов в формах и фильтрах.
+// Вызывается из init; повторная регистрация типа — ошибка программы.
+func Register(t RecordType) {
+	if _, ok := recordByType[t.Type]; ok {
+		panic(fmt.Sprintf("format: тип записи %s уже зарегистрирован", t.Type))
+	}
+	if _, ok := recordByKey[t.Key]; ok || t.Key == "" {
+		panic(fmt.Sprintf("format: у типа записи %s пустое или повторяющееся имя %q", t.Type, t.Key))
+	}
+	rt := &t
+	recordTypes = append(recordTypes, rt)
+	recordByType[t.Type] = rt
+	recordByKey[t.Key] = rt
+}
+
+// RecordTypes возвращает зарегистрированные типы записей в порядке регистрации.
+func RecordTypes() []*RecordType {
+	return recordTypes
+}
+
+// LookupRecordType возвращает описание типа записи или nil, если тип не зарегистрирован.
+func LookupRecordType(dt proto.DataType) *RecordType {
+	return recordByType[dt]
+}
+
+// LookupRecordTypeKey возвращает описание типа записи по имени RecordType.Key или nil.
+func LookupRecordTypeKey(key string) *RecordType {
+	return recordByKey[key]
+}
@@ -0,0 +1,240 @@
+// Package generator создаёт случайные пароли и парольные фразы (diceware) и оценивает их энтропию.
+// Случайность берётся из crypto/rand.
+package generator
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// Наборы символов пароля
+const (
+	lowerChars  = "abcdefghijklmnopqrstuvwxyz"
+	upperChars  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars  = "0123456789"
+	symbolChars = "!@#$%^&*()-_=+[]{};:,.<>/?~"
+	// ambiguousChars легко спутать при чтении с экрана или вводе вручную
+	ambiguousChars = "Il1O0o"
+)
+
+// Ограничения параметров
+const (
+	MinLength = 4
+	MaxLength = 128
+	MinWords  = 3
+	MaxWords  = 20
+)
+
+var (
+	// ErrNoCharClasses — не выбран ни один класс символов
+	ErrNoCharClasses = errors.New("no character classes selected")
+	// ErrLength — длина пароля вне MinLength–MaxLength
+	ErrLength = fmt.Errorf("password length must be between %d and %d", MinLength, MaxLength)
+	// ErrWords — число слов парольной фразы вне MinWords–MaxWords
+	ErrWords = fmt.Errorf("passphrase must have between %d and %d words", MinWords, MaxWords)
+)
+
+// Options — параметры пароля из случайных символов
+type Options struct {
+	Length  int
+	Lower   bool
+	Upper   bool
+	Digits  bool
+	Symbols bool
+	// ExcludeAmbiguous — не использовать похожие символы (I, l, 1, O, 0, o)
+	ExcludeAmbiguous bool
+}
+
+// DefaultOptions — 20 символов всех классов без похожих символов
+func DefaultOptions() Options {
+	return Options{Length: 20, Lower: true, Upper: true, Digits: true, Symbols: true, ExcludeAmbiguous: true}
+}
+
+// classes возвращает наборы символов выбранных классов
+func (o Options) classes() []string {
+	var classes []string
+	for _, c := range []struct {
+		on    bool
+		chars string
+	}{
+		{o.Lower, lowerChars},
+		{o.Upper, upperChars},
+		{o.Digits, digitChars},
+		{o.Symbols, symbolChars},
+	} {
+		if !c.on {
+			continue
+		}
+		chars := c.chars
+		if o.ExcludeAmbiguous {
+			chars = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(ambiguousChars, r) {
+					return -1
+				}
+				return r
+			}, chars)
+		}
+		classes = append(classes, chars)
+	}
+	return classes
+}
+
+func (o Options) validate() error {
+	if o.Length < MinLength || o.Length > MaxLength {
+		return ErrLength
+	}
+	if len(o.classes()) == 0 {
+		return ErrNoCharClasses
+	}
+	return nil
+}
+
+// Password создаёт пароль: в нём есть хотя бы один символ каждого выбранного класса,
+// остальные символы выбираются из объединения классов, затем порядок перемешивается.
+func Password(o Options) (string, error) {
+	if err := o.validate(); err != nil {
+		return "", err
+	}
+	classes := o.classes()
+	pool := strings.Join(classes, "")
+
+	password := make([]byte, 0, o.Length)
+	for _, class := range classes {
+		c, err := pick(class)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+	for len(password) < o.Length {
+		c, err := pick(pool)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+	// Перемешивание Фишера — Йетса, чтобы обязательные символы не стояли в начале
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := randInt(i + 1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+	return string(password), nil
+}
+
+// Entropy возвращает энтропию пароля с параметрами o в битах: длина × log2(размер набора символов).
+// Обязательный символ каждого класса немного уменьшает её, но не больше чем на несколько бит.
+func (o Options) Entropy() float64 {
+	pool := len(strings.Join(o.classes(), ""))
+	if pool == 0 || o.Length <= 0 {
+		return 0
+	}
+	return float64(o.Length) * math.Log2(float64(pool))
+}
+
+// PassphraseOptions — параметры парольной фразы из слов списка EFF
+type PassphraseOptions struct {
+	Words     int
+	Separator string
+	// Capitalize — каждое слово с заглавной буквы (энтропию не увеличивает)
+	Capitalize bool
+}
+
+// DefaultPassphraseOptions — шесть слов через дефис (≈77 бит)
+func DefaultPassphraseOptions() PassphraseOptions {
+	return PassphraseOptions{Words: 6, Separator: "-"}
+}
+
+// Passphrase создаёт парольную фразу из случайных слов списка EFF (7776 слов)
+func Passphrase(o PassphraseOptions) (string, error) {
+	if o.Words < MinWords || o.Words > MaxWords {
+		return "", ErrWords
+	}
+	list := wordlist()
+	words := make([]string, o.Words)
+	for i := range words {
+		n, err := randInt(len(list))
+		if err != nil {
+			return "", err
+		}
+		words[i] = list[n]
+		if o.Capitalize {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+	}
+	return strings.Join(words, o.Separator), nil
+}
+
+// Entropy возвращает энтропию парольной фразы в битах: число слов × log2(размер списка)
+func (o PassphraseOptions) Entropy() float64 {
+	if o.Words <= 0 {
+		return 0
+	}
+	return float64(o.Words) * math.Log2(float64(len(wordlist())))
+}
+
+// Estimate оценивает энтропию произвольного пароля, например введённого вручную, по длине
+// и встретившимся классам символов. Это верхняя оценка: словарные слова и шаблоны она не учитывает.
+func Estimate(password string) float64 {
+	var lower, upper, digits, symbols, other bool
+	length := 0
+	for _, r := range password {
+		length++
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digits = true
+		case r < unicode.MaxASCII:
+			symbols = true
+		default:
+			other = true
+		}
+	}
+	pool := 0
+	for _, c := range []struct {
+		on   bool
+		size int
+	}{
+		{lower, len(lowerChars)},
+		{upper, len(upperChars)},
+		{digits, len(digitChars)},
+		{symbols, 33}, // печатные символы ASCII, кроме букв и цифр
+		{other, 66},   // буквы кириллицы и прочие символы
+	} {
+		if c.on {
+			pool += c.size
+		}
+	}
+	if pool == 0 {
+		return 0
+	}
+	return float64(length) * math.Log2(float64(pool))
+}
+
+// pick возвращает случайный символ из chars
+func pick(chars string) (byte, error) {
+	i, err := randInt(len(chars))
+	if err != nil {
+		return 0, err
+	}
+	return chars[i], nil
+}
+
+// randInt возвращает равномерно распределённое случайное число из [0, n)
+func randInt(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("random number generator: %w", err)
+	}
+	return int(v.Int64()), nil
+}
@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestWordlist(t *testing.T) {
+	words := wordlist()
+	if len(words) != 7776 {
+		t.Fatalf("wordlist has %d words, want 7776", len(words))
+	}
+	seen := make(map[string]bool, len(words))
+	for _, w := range words {
+		if w == "" || seen[w] {
+			t.Fatalf("empty or duplicate word %q", w)
+		}
+		seen[w] = true
+	}
+}
+
+func TestPassword_ContainsEveryClass(t *testing.T) {
+	o := DefaultOptions()
+	for range 50 {
+		p, err := Password(o)
+		if err != nil {
+			t.Fatalf("Password: %v", err)
+		}
+		if len(p) != o.Length {
+			t.Fatalf("length %d, want %d", len(p), o.Length)
+		}
+		for _, class := range []string{lowerChars, upperChars, digitChars, symbolChars} {
+			if !strings.ContainsAny(p, class) {
+				t.Fatalf("%q has no characters from %q", p, class)
+			}
+		}
+		if strings.ContainsAny(p, ambiguousChars) {
+			t.Fatalf("%q contains ambiguous characters", p)
+		}
+	}
+}
+
+func TestPassword_SingleClass(t *testing.T) {
+	p, err := Password(Options{Length: 12, Digits: true})
+	if err != nil {
+		t.Fatalf("Password: %v", err)
+	}
+	if strings.Trim(p, digitChars) != "" {
+		t.Errorf("%q is not digits only", p)
+	}
+}
+
+func TestPassword_InvalidOptions(t *testing.T) {
+	if _, err := Password(Options{Length: 12}); !errors.Is(err, ErrNoCharClasses) {
+		t.Errorf("no classes: err %v", err)
+	}
+	if _, err := Password(Options{Length: MinLength - 1, Lower: true}); !errors.Is(err, ErrLength) {
+		t.Errorf("short length: err %v, want ErrLength", err)
+	}
+	if _, err := Password(Options{Length: MaxLength + 1, Lower: true}); !errors.Is(err, ErrLength) {
+		t.Errorf("long length: err %v, want ErrLength", err)
+	}
+}
+
+func TestPassphrase(t *testing.T) {
+	o := PassphraseOptions{Words: 5, Separator: " ", Capitalize: true}
+	p, err := Passphrase(o)
+	if err != nil {
+		t.Fatalf("Passphrase: %v", err)
+	}
+	words := strings.Split(p, " ")
+	if len(words) != 5 {
+		t.Fatalf("%q has %d words", p, len(words))
+	}
+	for _, w := range words {
+		if w[:1] != strings.ToUpper(w[:1]) {
+			t.Errorf("word %q not capitalized", w)
+		}
+	}
+	if _, err := Passphrase(PassphraseOptions{Words: MinWords - 1}); !errors.Is(err, ErrWords) {
+		t.Errorf("too few words: err %v, want ErrWords", err)
+	}
+}
+
+func TestEntropy(t *testing.T) {
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"digits", Options{Length: 10, Digits: true}.Entropy(), 10 * math.Log2(10)},
+		{"lower without ambiguous", Options{Length: 8, Lower: true, ExcludeAmbiguous: true}.Entropy(), 8 * math.Log2(24)},
+		{"passphrase", PassphraseOptions{Words: 6}.Entropy(), 6 * math.Log2(7776)},
+		{"estimate lower+digits", Estimate("abc123"), 6 * math.Log2(36)},
+		{"estimate empty", Estimate(""), 0},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > 1e-9 {
+			t.Errorf("%s: entropy %.2f, want %.2f", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package generator
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+)
+
+// effLargeWordlist — большой список слов EFF для diceware (7776 слов, строки «броски<TAB>слово»),
+// https://www.eff.org/deeplinks/2016/07/new-wordlists-random-passphrases, лицензия CC BY 3.0 US
+//
+//go:embed eff_large_wordlist.txt
+var effLargeWordlist string
+
+// wordlist возвращает слова списка EFF по порядку бросков
+var wordlist = sync.OnceValue(func() []string {
+	lines := strings.Split(strings.TrimSpace(effLargeWordlist), "\n")
+	words := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if _, word, ok := strings.Cut(line, "\t"); ok {
+			words = append(words, strings.TrimSpace(word))
+		}
+	}
+	return words
+})
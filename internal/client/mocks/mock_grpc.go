@@ -42,6 +42,26 @@ func (m *MockAuthServiceClient) EXPECT() *MockAuthServiceClientMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockAuthServiceClient) ChangePassword(ctx context.Context, in *proto.ChangePasswordRequest, opts ...grpc.CallOption) (*proto.ChangePasswordResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ChangePassword", varargs...)
+	ret0, _ := ret[0].(*proto.ChangePasswordResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockAuthServiceClientMockRecorder) ChangePassword(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockAuthServiceClient)(nil).ChangePassword), varargs...)
+}
+
 // Login mocks base method.
 func (m *MockAuthServiceClient) Login(ctx context.Context, in *proto.LoginRequest, opts ...grpc.CallOption) (*proto.LoginResponse, error) {
 	m.ctrl.T.Helper()
@@ -102,6 +122,26 @@ func (mr *MockAuthServiceClientMockRecorder) Register(ctx, in any, opts ...any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuthServiceClient)(nil).Register), varargs...)
 }
 
+// SetKeyCheck mocks base method.
+func (m *MockAuthServiceClient) SetKeyCheck(ctx context.Context, in *proto.SetKeyCheckRequest, opts ...grpc.CallOption) (*proto.SetKeyCheckResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SetKeyCheck", varargs...)
+	ret0, _ := ret[0].(*proto.SetKeyCheckResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetKeyCheck indicates an expected call of SetKeyCheck.
+func (mr *MockAuthServiceClientMockRecorder) SetKeyCheck(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKeyCheck", reflect.TypeOf((*MockAuthServiceClient)(nil).SetKeyCheck), varargs...)
+}
+
 // MockAuthServiceServer is a mock of AuthServiceServer interface.
 type MockAuthServiceServer struct {
 	ctrl     *gomock.Controller
@@ -126,6 +166,21 @@ func (m *MockAuthServiceServer) EXPECT() *MockAuthServiceServerMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockAuthServiceServer) ChangePassword(arg0 context.Context, arg1 *proto.ChangePasswordRequest) (*proto.ChangePasswordResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", arg0, arg1)
+	ret0, _ := ret[0].(*proto.ChangePasswordResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockAuthServiceServerMockRecorder) ChangePassword(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockAuthServiceServer)(nil).ChangePassword), arg0, arg1)
+}
+
 // Login mocks base method.
 func (m *MockAuthServiceServer) Login(arg0 context.Context, arg1 *proto.LoginRequest) (*proto.LoginResponse, error) {
 	m.ctrl.T.Helper()
@@ -171,6 +226,21 @@ func (mr *MockAuthServiceServerMockRecorder) Register(arg0, arg1 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuthServiceServer)(nil).Register), arg0, arg1)
 }
 
+// SetKeyCheck mocks base method.
+func (m *MockAuthServiceServer) SetKeyCheck(arg0 context.Context, arg1 *proto.SetKeyCheckRequest) (*proto.SetKeyCheckResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetKeyCheck", arg0, arg1)
+	ret0, _ := ret[0].(*proto.SetKeyCheckResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetKeyCheck indicates an expected call of SetKeyCheck.
+func (mr *MockAuthServiceServerMockRecorder) SetKeyCheck(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKeyCheck", reflect.TypeOf((*MockAuthServiceServer)(nil).SetKeyCheck), arg0, arg1)
+}
+
 // mustEmbedUnimplementedAuthServiceServer mocks base method.
 func (m *MockAuthServiceServer) mustEmbedUnimplementedAuthServiceServer() {
 	m.ctrl.T.Helper()
@@ -263,6 +333,26 @@ func (mr *MockDataServiceClientMockRecorder) DeleteData(ctx, in any, opts ...any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteData", reflect.TypeOf((*MockDataServiceClient)(nil).DeleteData), varargs...)
 }
 
+// DownloadBlob mocks base method.
+func (m *MockDataServiceClient) DownloadBlob(ctx context.Context, in *proto.DownloadBlobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[proto.BlobChunk], error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DownloadBlob", varargs...)
+	ret0, _ := ret[0].(grpc.ServerStreamingClient[proto.BlobChunk])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadBlob indicates an expected call of DownloadBlob.
+func (mr *MockDataServiceClientMockRecorder) DownloadBlob(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadBlob", reflect.TypeOf((*MockDataServiceClient)(nil).DownloadBlob), varargs...)
+}
+
 // GetData mocks base method.
 func (m *MockDataServiceClient) GetData(ctx context.Context, in *proto.GetDataRequest, opts ...grpc.CallOption) (*proto.GetDataResponse, error) {
 	m.ctrl.T.Helper()
@@ -303,6 +393,26 @@ func (mr *MockDataServiceClientMockRecorder) ListData(ctx, in any, opts ...any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListData", reflect.TypeOf((*MockDataServiceClient)(nil).ListData), varargs...)
 }
 
+// ListDataSummaries mocks base method.
+func (m *MockDataServiceClient) ListDataSummaries(ctx context.Context, in *proto.ListDataSummariesRequest, opts ...grpc.CallOption) (*proto.ListDataSummariesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListDataSummaries", varargs...)
+	ret0, _ := ret[0].(*proto.ListDataSummariesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDataSummaries indicates an expected call of ListDataSummaries.
+func (mr *MockDataServiceClientMockRecorder) ListDataSummaries(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDataSummaries", reflect.TypeOf((*MockDataServiceClient)(nil).ListDataSummaries), varargs...)
+}
+
 // SaveData mocks base method.
 func (m *MockDataServiceClient) SaveData(ctx context.Context, in *proto.SaveDataRequest, opts ...grpc.CallOption) (*proto.SaveDataResponse, error) {
 	m.ctrl.T.Helper()
@@ -323,6 +433,26 @@ func (mr *MockDataServiceClientMockRecorder) SaveData(ctx, in any, opts ...any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveData", reflect.TypeOf((*MockDataServiceClient)(nil).SaveData), varargs...)
 }
 
+// SearchData mocks base method.
+func (m *MockDataServiceClient) SearchData(ctx context.Context, in *proto.SearchDataRequest, opts ...grpc.CallOption) (*proto.SearchDataResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SearchData", varargs...)
+	ret0, _ := ret[0].(*proto.SearchDataResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchData indicates an expected call of SearchData.
+func (mr *MockDataServiceClientMockRecorder) SearchData(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchData", reflect.TypeOf((*MockDataServiceClient)(nil).SearchData), varargs...)
+}
+
 // SyncData mocks base method.
 func (m *MockDataServiceClient) SyncData(ctx context.Context, in *proto.SyncDataRequest, opts ...grpc.CallOption) (*proto.SyncDataResponse, error) {
 	m.ctrl.T.Helper()
@@ -343,6 +473,26 @@ func (mr *MockDataServiceClientMockRecorder) SyncData(ctx, in any, opts ...any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncData", reflect.TypeOf((*MockDataServiceClient)(nil).SyncData), varargs...)
 }
 
+// UploadBlob mocks base method.
+func (m *MockDataServiceClient) UploadBlob(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[proto.BlobChunk, proto.UploadBlobResponse], error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UploadBlob", varargs...)
+	ret0, _ := ret[0].(grpc.ClientStreamingClient[proto.BlobChunk, proto.UploadBlobResponse])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadBlob indicates an expected call of UploadBlob.
+func (mr *MockDataServiceClientMockRecorder) UploadBlob(ctx any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadBlob", reflect.TypeOf((*MockDataServiceClient)(nil).UploadBlob), varargs...)
+}
+
 // MockDataServiceServer is a mock of DataServiceServer interface.
 type MockDataServiceServer struct {
 	ctrl     *gomock.Controller
@@ -382,6 +532,20 @@ func (mr *MockDataServiceServerMockRecorder) DeleteData(arg0, arg1 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteData", reflect.TypeOf((*MockDataServiceServer)(nil).DeleteData), arg0, arg1)
 }
 
+// DownloadBlob mocks base method.
+func (m *MockDataServiceServer) DownloadBlob(arg0 *proto.DownloadBlobRequest, arg1 grpc.ServerStreamingServer[proto.BlobChunk]) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadBlob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DownloadBlob indicates an expected call of DownloadBlob.
+func (mr *MockDataServiceServerMockRecorder) DownloadBlob(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadBlob", reflect.TypeOf((*MockDataServiceServer)(nil).DownloadBlob), arg0, arg1)
+}
+
 // GetData mocks base method.
 func (m *MockDataServiceServer) GetData(arg0 context.Context, arg1 *proto.GetDataRequest) (*proto.GetDataResponse, error) {
 	m.ctrl.T.Helper()
@@ -412,6 +576,21 @@ func (mr *MockDataServiceServerMockRecorder) ListData(arg0, arg1 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListData", reflect.TypeOf((*MockDataServiceServer)(nil).ListData), arg0, arg1)
 }
 
+// ListDataSummaries mocks base method.
+func (m *MockDataServiceServer) ListDataSummaries(arg0 context.Context, arg1 *proto.ListDataSummariesRequest) (*proto.ListDataSummariesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDataSummaries", arg0, arg1)
+	ret0, _ := ret[0].(*proto.ListDataSummariesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDataSummaries indicates an expected call of ListDataSummaries.
+func (mr *MockDataServiceServerMockRecorder) ListDataSummaries(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDataSummaries", reflect.TypeOf((*MockDataServiceServer)(nil).ListDataSummaries), arg0, arg1)
+}
+
 // SaveData mocks base method.
 func (m *MockDataServiceServer) SaveData(arg0 context.Context, arg1 *proto.SaveDataRequest) (*proto.SaveDataResponse, error) {
 	m.ctrl.T.Helper()
@@ -427,6 +606,21 @@ func (mr *MockDataServiceServerMockRecorder) SaveData(arg0, arg1 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveData", reflect.TypeOf((*MockDataServiceServer)(nil).SaveData), arg0, arg1)
 }
 
+// SearchData mocks base method.
+func (m *MockDataServiceServer) SearchData(arg0 context.Context, arg1 *proto.SearchDataRequest) (*proto.SearchDataResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchData", arg0, arg1)
+	ret0, _ := ret[0].(*proto.SearchDataResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchData indicates an expected call of SearchData.
+func (mr *MockDataServiceServerMockRecorder) SearchData(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchData", reflect.TypeOf((*MockDataServiceServer)(nil).SearchData), arg0, arg1)
+}
+
 // SyncData mocks base method.
 func (m *MockDataServiceServer) SyncData(arg0 context.Context, arg1 *proto.SyncDataRequest) (*proto.SyncDataResponse, error) {
 	m.ctrl.T.Helper()
@@ -442,6 +636,20 @@ func (mr *MockDataServiceServerMockRecorder) SyncData(arg0, arg1 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncData", reflect.TypeOf((*MockDataServiceServer)(nil).SyncData), arg0, arg1)
 }
 
+// UploadBlob mocks base method.
+func (m *MockDataServiceServer) UploadBlob(arg0 grpc.ClientStreamingServer[proto.BlobChunk, proto.UploadBlobResponse]) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadBlob", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UploadBlob indicates an expected call of UploadBlob.
+func (mr *MockDataServiceServerMockRecorder) UploadBlob(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadBlob", reflect.TypeOf((*MockDataServiceServer)(nil).UploadBlob), arg0)
+}
+
 // mustEmbedUnimplementedDataServiceServer mocks base method.
 func (m *MockDataServiceServer) mustEmbedUnimplementedDataServiceServer() {
 	m.ctrl.T.Helper()
@@ -0,0 +1,171 @@
+// Package otp вычисляет одноразовые коды TOTP (RFC 6238) по ключу из otpauth:// URI или секрета Base32.
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Параметры по умолчанию, которые используют Google Authenticator и большинство сервисов
+const (
+	DefaultDigits    = 6
+	DefaultPeriod    = 30 * time.Second
+	DefaultAlgorithm = "SHA1"
+)
+
+var (
+	// ErrEmptySecret — не указан секрет
+	ErrEmptySecret = errors.New("secret is empty")
+	// ErrSecretEncoding — секрет не в формате Base32
+	ErrSecretEncoding = errors.New("secret is not Base32")
+	// ErrInvalidURI — otpauth:// URI не разбирается
+	ErrInvalidURI = errors.New("invalid otpauth URI")
+	// ErrUnsupportedType — тип ключа в URI не totp
+	ErrUnsupportedType = errors.New("unsupported OTP type, want totp")
+	// ErrUnsupportedAlgorithm — алгоритм не SHA1, SHA256 или SHA512
+	ErrUnsupportedAlgorithm = errors.New("unsupported algorithm")
+	// ErrDigits — число цифр кода не от 6 до 8
+	ErrDigits = errors.New("digits must be between 6 and 8")
+	// ErrPeriod — период не положительное целое число секунд
+	ErrPeriod = errors.New("invalid period")
+)
+
+// Key — ключ TOTP
+type Key struct {
+	Issuer    string
+	Account   string
+	Secret    []byte
+	Algorithm string // SHA1, SHA256 или SHA512
+	Digits    int
+	Period    time.Duration
+}
+
+// Parse разбирает otpauth://totp/... URI или секрет Base32 (регистр, пробелы и дефисы не важны).
+// Для секрета без URI используются параметры по умолчанию.
+func Parse(s string) (*Key, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(strings.ToLower(s), "otpauth://") {
+		return parseURI(s)
+	}
+	secret, err := decodeSecret(s)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{Secret: secret, Algorithm: DefaultAlgorithm, Digits: DefaultDigits, Period: DefaultPeriod}, nil
+}
+
+// parseURI разбирает URI формата Key Uri Format:
+// otpauth://totp/Issuer:account?secret=...&issuer=...&algorithm=...&digits=...&period=...
+func parseURI(s string) (*Key, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURI, err)
+	}
+	if !strings.EqualFold(u.Host, "totp") {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedType, u.Host)
+	}
+	q := u.Query()
+	secret, err := decodeSecret(q.Get("secret"))
+	if err != nil {
+		return nil, err
+	}
+	key := &Key{Secret: secret, Algorithm: DefaultAlgorithm, Digits: DefaultDigits, Period: DefaultPeriod}
+
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		key.Issuer, key.Account = strings.TrimSpace(issuer), strings.TrimSpace(account)
+	} else {
+		key.Account = label
+	}
+	if issuer := q.Get("issuer"); issuer != "" {
+		key.Issuer = issuer
+	}
+	if alg := q.Get("algorithm"); alg != "" {
+		key.Algorithm = strings.ToUpper(alg)
+		if newHash(key.Algorithm) == nil {
+			return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, alg)
+		}
+	}
+	if d := q.Get("digits"); d != "" {
+		n, err := strconv.Atoi(d)
+		if err != nil || n < 6 || n > 8 {
+			return nil, fmt.Errorf("%w: %q", ErrDigits, d)
+		}
+		key.Digits = n
+	}
+	if p := q.Get("period"); p != "" {
+		n, err := strconv.Atoi(p)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%w: %q", ErrPeriod, p)
+		}
+		key.Period = time.Duration(n) * time.Second
+	}
+	return key, nil
+}
+
+// decodeSecret декодирует секрет Base32 без учёта регистра, пробелов, дефисов и дополнения
+func decodeSecret(s string) ([]byte, error) {
+	s = strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "=", "").Replace(s))
+	if s == "" {
+		return nil, ErrEmptySecret
+	}
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSecretEncoding, err)
+	}
+	return secret, nil
+}
+
+// Code возвращает код для момента t
+func (k *Key) Code(t time.Time) string {
+	counter := uint64(t.Unix()) / uint64(k.Period/time.Second)
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(newHash(k.Algorithm), k.Secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// Динамическое усечение (RFC 4226, раздел 5.3)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for range k.Digits {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", k.Digits, value%mod)
+}
+
+// Remaining возвращает, сколько ещё действует код момента t
+func (k *Key) Remaining(t time.Time) time.Duration {
+	period := k.Period.Nanoseconds()
+	return time.Duration(period - t.UnixNano()%period)
+}
+
+// SecretString возвращает секрет в Base32 без дополнения
+func (k *Key) SecretString() string {
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(k.Secret)
+}
+
+func newHash(algorithm string) func() hash.Hash {
+	switch algorithm {
+	case "SHA1":
+		return sha1.New
+	case "SHA256":
+		return sha256.New
+	case "SHA512":
+		return sha512.New
+	}
+	return nil
+}
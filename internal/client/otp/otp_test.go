@@ -0,0 +1,100 @@
+package otp_test
+
+import (
+	"encoding/base32"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gophkeeper/gophkeeper/internal/client/otp"
+)
+
+// Тестовые векторы RFC 6238, приложение B (8 цифр)
+func TestCode_RFC6238(t *testing.T) {
+	secrets := map[string]string{
+		"SHA1":   "12345678901234567890",
+		"SHA256": "12345678901234567890123456789012",
+		"SHA512": "1234567890123456789012345678901234567890123456789012345678901234",
+	}
+	tests := []struct {
+		unix int64
+		alg  string
+		want string
+	}{
+		{59, "SHA1", "94287082"},
+		{59, "SHA256", "46119246"},
+		{59, "SHA512", "90693936"},
+		{1111111109, "SHA1", "07081804"},
+		{1111111111, "SHA256", "67062674"},
+		{1234567890, "SHA512", "93441116"},
+		{2000000000, "SHA1", "69279037"},
+		{20000000000, "SHA256", "77737706"},
+	}
+	for _, tt := range tests {
+		uri := "otpauth://totp/Test?digits=8&algorithm=" + tt.alg +
+			"&secret=" + base32.StdEncoding.EncodeToString([]byte(secrets[tt.alg]))
+		key, err := otp.Parse(uri)
+		if err != nil {
+			t.Fatalf("Parse: %v", err)
+		}
+		if got := key.Code(time.Unix(tt.unix, 0)); got != tt.want {
+			t.Errorf("%s at %d: code %s, want %s", tt.alg, tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestParse_URI(t *testing.T) {
+	key, err := otp.Parse("otpauth://totp/ACME%20Co:john.doe@email.com?secret=HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ&issuer=ACME%20Co&period=60")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if key.Issuer != "ACME Co" || key.Account != "john.doe@email.com" {
+		t.Errorf("issuer %q, account %q", key.Issuer, key.Account)
+	}
+	if key.Period != time.Minute || key.Digits != 6 || key.Algorithm != "SHA1" {
+		t.Errorf("period %v, digits %d, algorithm %s", key.Period, key.Digits, key.Algorithm)
+	}
+	if key.SecretString() != "HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ" {
+		t.Errorf("secret %s", key.SecretString())
+	}
+}
+
+func TestParse_BareSecret(t *testing.T) {
+	key, err := otp.Parse("hxdm vjec jjws rb3h wizr 4ifu gftm xboz")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if key.Digits != otp.DefaultDigits || key.Period != otp.DefaultPeriod {
+		t.Errorf("digits %d, period %v", key.Digits, key.Period)
+	}
+	if len(key.Code(time.Now())) != 6 {
+		t.Errorf("code %q", key.Code(time.Now()))
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for s, want := range map[string]error{
+		"":            otp.ErrEmptySecret,
+		"not base32!": otp.ErrSecretEncoding,
+		"otpauth://hotp/Test?secret=JBSWY3DP&counter=1":     otp.ErrUnsupportedType,
+		"otpauth://totp/Test":                               otp.ErrEmptySecret,
+		"otpauth://totp/Test?secret=JBSWY3DP&algorithm=MD5": otp.ErrUnsupportedAlgorithm,
+		"otpauth://totp/Test?secret=JBSWY3DP&digits=4":      otp.ErrDigits,
+		"otpauth://totp/Test?secret=JBSWY3DP&period=0":      otp.ErrPeriod,
+		"otpauth://totp/%zz":                                otp.ErrInvalidURI,
+	} {
+		if _, err := otp.Parse(s); !errors.Is(err, want) {
+			t.Errorf("Parse(%q) = %v, want %v", s, err, want)
+		}
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	key, err := otp.Parse("JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := key.Remaining(time.Unix(65, 0)); got != 25*time.Second {
+		t.Errorf("remaining %v, want 25s", got)
+	}
+}
@@ -0,0 +1,223 @@
+package replica
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/gophkeeper/gophkeeper/internal/client"
+	"github.com/gophkeeper/gophkeeper/internal/client/cache"
+	"github.com/gophkeeper/gophkeeper/proto"
+)
+
+// ErrNoConflict — у записи нет неразрешённого конфликта
+var ErrNoConflict = errors.New("record has no unresolved conflict")
+
+// Conflict — изменение, отклонённое сервером из-за того, что запись успели изменить на другом устройстве
+type Conflict struct {
+	ID string
+	// Base — версия, от которой начиналась локальная правка (nil для записи, созданной на этом устройстве)
+	Base *proto.Data
+	// Mine — локальная версия
+	Mine *proto.Data
+	// Theirs — актуальная версия на сервере (nil, если запись там удалена)
+	Theirs *proto.Data
+}
+
+// ConflictError возвращается Save и Resolve, когда сервер отклонил сохранение из-за конфликта версий.
+// Изменение остаётся в очереди, пока конфликт не разрешат через Resolve или KeepTheirs.
+type ConflictError struct {
+	Conflict *Conflict
+}
+
+func (e *ConflictError) Error() string {
+	return client.ErrVersionConflict.Error()
+}
+
+func (e *ConflictError) Unwrap() error {
+	return client.ErrVersionConflict
+}
+
+// Conflicts возвращает неразрешённые конфликты в порядке постановки изменений в очередь
+func (r *Replica) Conflicts() ([]*Conflict, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries, err := r.cache.Conflicts()
+	if err != nil {
+		return nil, err
+	}
+	conflicts := make([]*Conflict, 0, len(entries))
+	for _, e := range entries {
+		c, err := r.openConflict(e)
+		if err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, c)
+	}
+	return conflicts, nil
+}
+
+// Resolve разрешает конфликт записи id: resolved (своя версия или результат слияния) сохраняется
+// поверх актуальной версии сервера. Если запись на сервере удалена, resolved сохраняется как новая
+// запись с новым ID. Поля Id и Version у resolved обновляются; queued = true, если отправить
+// изменение сразу не удалось и оно осталось в очереди.
+func (r *Replica) Resolve(id string, resolved *proto.Data) (queued bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, err := r.conflictEntry(id)
+	if err != nil {
+		return false, err
+	}
+
+	if entry.TheirsData == nil {
+		// Удалённую на сервере запись нельзя восстановить под прежним ID
+		if err := r.cache.Discard(id, nil); err != nil {
+			return false, err
+		}
+		resolved.Id = uuid.New().String()
+		resolved.Version = 0
+		sealed, err := r.seal(resolved)
+		if err != nil {
+			return false, err
+		}
+		if err := r.cache.QueueSave(resolved.Id, 0, sealed); err != nil {
+			return false, err
+		}
+	} else {
+		theirs, err := r.open(entry.TheirsData)
+		if err != nil {
+			return false, err
+		}
+		resolved.Id = id
+		resolved.Version = theirs.Version
+		sealed, err := r.seal(resolved)
+		if err != nil {
+			return false, err
+		}
+		if err := r.cache.Requeue(id, theirs.Version, entry.TheirsData, sealed); err != nil {
+			return false, err
+		}
+	}
+
+	if !r.client.IsAuthenticated() {
+		return true, nil
+	}
+	pending, err := r.cache.Pending(resolved.Id)
+	if err != nil {
+		return false, err
+	}
+	saved, sendErr, err := r.send(*pending)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case sendErr == nil:
+		r.offline = false
+		resolved.Version = saved.Version
+		return false, nil
+	case client.IsUnavailable(sendErr):
+		r.offline = true
+		return true, nil
+	case errors.Is(sendErr, client.ErrVersionConflict):
+		// Запись успели изменить ещё раз, пока конфликт разрешался
+		entry, err := r.conflictEntry(resolved.Id)
+		if err != nil {
+			return true, err
+		}
+		c, err := r.openConflict(*entry)
+		if err != nil {
+			return true, err
+		}
+		return true, &ConflictError{Conflict: c}
+	default:
+		return true, sendErr
+	}
+}
+
+// KeepTheirs разрешает конфликт записи id в пользу версии сервера: локальное изменение отбрасывается.
+// Возвращает версию сервера (nil, если запись там удалена и удалена локально).
+func (r *Replica) KeepTheirs(id string) (*proto.Data, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, err := r.conflictEntry(id)
+	if err != nil {
+		return nil, err
+	}
+	theirs, err := r.openOptional(entry.TheirsData)
+	if err != nil {
+		return nil, err
+	}
+	return theirs, r.cache.Discard(id, entry.TheirsData)
+}
+
+// queueConflict ставит правку, отклонённую сервером, в очередь как неразрешённый конфликт
+func (r *Replica) queueConflict(data *proto.Data, conflict *client.ConflictError) error {
+	sealed, err := r.seal(data)
+	if err != nil {
+		return err
+	}
+	if err := r.cache.QueueSave(data.Id, data.Version, sealed); err != nil {
+		return err
+	}
+	theirs, err := r.sealOptional(conflict.Current)
+	if err != nil {
+		return err
+	}
+	if err := r.cache.MarkConflict(data.Id, theirs, conflict.Error()); err != nil {
+		return err
+	}
+
+	entry, err := r.conflictEntry(data.Id)
+	if err != nil {
+		return err
+	}
+	c, err := r.openConflict(*entry)
+	if err != nil {
+		return err
+	}
+	return &ConflictError{Conflict: c}
+}
+
+func (r *Replica) conflictEntry(id string) (*cache.OutboxEntry, error) {
+	entry, err := r.cache.Pending(id)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil || !entry.Conflict {
+		return nil, ErrNoConflict
+	}
+	return entry, nil
+}
+
+func (r *Replica) openConflict(e cache.OutboxEntry) (*Conflict, error) {
+	c := &Conflict{ID: e.RecordID}
+	var err error
+	if c.Base, err = r.openOptional(e.BaseData); err != nil {
+		return nil, err
+	}
+	if c.Mine, err = r.openOptional(e.Data); err != nil {
+		return nil, err
+	}
+	if c.Theirs, err = r.openOptional(e.TheirsData); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// sealOptional шифрует запись; для nil возвращает nil
+func (r *Replica) sealOptional(d *proto.Data) ([]byte, error) {
+	if d == nil {
+		return nil, nil
+	}
+	return r.seal(d)
+}
+
+// openOptional расшифровывает запись; для пустых данных возвращает nil
+func (r *Replica) openOptional(sealed []byte) (*proto.Data, error) {
+	if len(sealed) == 0 {
+		return nil, nil
+	}
+	return r.open(sealed)
+}
@@ -0,0 +1,190 @@
+package replica_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/gophkeeper/gophkeeper/internal/client/replica"
+	"github.com/gophkeeper/gophkeeper/proto"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// conflictErr — ответ сервера на сохранение поверх устаревшей версии
+func conflictErr(t *testing.T, current *proto.Data) error {
+	t.Helper()
+	st := status.New(codes.Aborted, "conflict")
+	if current != nil {
+		var err error
+		if st, err = st.WithDetails(current); err != nil {
+			t.Fatalf("WithDetails: %v", err)
+		}
+	}
+	return st.Err()
+}
+
+func TestSave_ConflictThenResolve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c, _, dataMock := onlineClient(t, ctrl)
+	r, err := replica.Open(c, openCache(t, filepath.Join(t.TempDir(), "cache.db")), "user", "pass")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	dataMock.EXPECT().SyncData(gomock.Any(), gomock.Any()).
+		Return(&proto.SyncDataResponse{
+			Success:    true,
+			Data:       []*proto.Data{{Id: "d1", Name: "Исходная", Version: 3}},
+			FullResync: true,
+			NextCursor: "c1",
+		}, nil)
+	if _, err := r.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	dataMock.EXPECT().SaveData(gomock.Any(), gomock.Any()).
+		Return(nil, conflictErr(t, &proto.Data{Id: "d1", Name: "С другого устройства", Version: 4}))
+	queued, err := r.Save(&proto.Data{Id: "d1", Name: "Моя", Version: 3})
+	var ce *replica.ConflictError
+	if !errors.As(err, &ce) || !queued {
+		t.Fatalf("Save = %v, %v, want queued *ConflictError", queued, err)
+	}
+	cf := ce.Conflict
+	if cf.Base.GetName() != "Исходная" || cf.Mine.GetName() != "Моя" || cf.Theirs.GetName() != "С другого устройства" {
+		t.Errorf("conflict = %+v, want base/mine/theirs versions", cf)
+	}
+	if conflicts, _ := r.Conflicts(); len(conflicts) != 1 || conflicts[0].ID != "d1" {
+		t.Errorf("Conflicts = %v, want d1", conflicts)
+	}
+
+	var pushed *proto.SaveDataRequest
+	dataMock.EXPECT().SaveData(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *proto.SaveDataRequest, _ ...grpc.CallOption) (*proto.SaveDataResponse, error) {
+			pushed = req
+			return &proto.SaveDataResponse{Success: true, DataId: req.Data.Id, Version: 5}, nil
+		})
+	merged := &proto.Data{Name: "Слияние"}
+	queued, err = r.Resolve("d1", merged)
+	if err != nil || queued {
+		t.Fatalf("Resolve = %v, %v, want sent", queued, err)
+	}
+	if pushed.BaseVersion != 4 || pushed.Data.Id != "d1" || pushed.Data.Name != "Слияние" {
+		t.Errorf("pushed = %v, want merge of d1 over server version 4", pushed)
+	}
+	if merged.Version != 5 {
+		t.Errorf("merged.Version = %d, want 5", merged.Version)
+	}
+	if n, _ := r.PendingCount(); n != 0 {
+		t.Errorf("PendingCount = %d, want 0", n)
+	}
+	if _, err := r.KeepTheirs("d1"); !errors.Is(err, replica.ErrNoConflict) {
+		t.Errorf("KeepTheirs after resolve = %v, want ErrNoConflict", err)
+	}
+}
+
+func TestKeepTheirs_DeletedOnServer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c, _, dataMock := onlineClient(t, ctrl)
+	r, err := replica.Open(c, openCache(t, filepath.Join(t.TempDir(), "cache.db")), "user", "pass")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	dataMock.EXPECT().SaveData(gomock.Any(), gomock.Any()).Return(nil, conflictErr(t, nil))
+	if _, err := r.Save(&proto.Data{Id: "d1", Name: "Моя", Version: 2}); err == nil {
+		t.Fatal("Save: want conflict")
+	}
+
+	theirs, err := r.KeepTheirs("d1")
+	if err != nil || theirs != nil {
+		t.Fatalf("KeepTheirs = %v, %v, want deleted", theirs, err)
+	}
+	list, _ := r.List()
+	n, _ := r.PendingCount()
+	if len(list) != 0 || n != 0 {
+		t.Errorf("list = %v, pending = %d, want record dropped", list, n)
+	}
+}
+
+func TestDelete_DropsConflictAndPushesDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c, _, dataMock := onlineClient(t, ctrl)
+	r, err := replica.Open(c, openCache(t, filepath.Join(t.TempDir(), "cache.db")), "user", "pass")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	dataMock.EXPECT().SaveData(gomock.Any(), gomock.Any()).
+		Return(nil, conflictErr(t, &proto.Data{Id: "d1", Name: "С другого устройства", Version: 4}))
+	if _, err := r.Save(&proto.Data{Id: "d1", Name: "Моя", Version: 3}); err == nil {
+		t.Fatal("Save: want conflict")
+	}
+
+	// Запись с конфликтом в очереди, поэтому удаление тоже ставится в очередь
+	queued, err := r.Delete(&proto.Data{Id: "d1", Version: 3})
+	if err != nil || !queued {
+		t.Fatalf("Delete = %v, %v, want queued", queued, err)
+	}
+	conflicts, err := r.Conflicts()
+	if err != nil || len(conflicts) != 0 {
+		t.Fatalf("Conflicts = %v, %v, want none after delete", conflicts, err)
+	}
+
+	dataMock.EXPECT().DeleteData(gomock.Any(), &proto.DeleteDataRequest{DataId: "d1"}).
+		Return(&proto.DeleteDataResponse{Success: true}, nil)
+	dataMock.EXPECT().SyncData(gomock.Any(), gomock.Any()).
+		Return(&proto.SyncDataResponse{Success: true, NextCursor: "c1"}, nil)
+	report, err := r.Sync()
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if report.Pushed != 1 || report.Pending != 0 || len(report.Conflicts) != 0 {
+		t.Errorf("report = %+v, want delete pushed", report)
+	}
+}
+
+func TestResolve_DeletedOnServerSavesAsNewRecord(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c, _, dataMock := onlineClient(t, ctrl)
+	r, err := replica.Open(c, openCache(t, filepath.Join(t.TempDir(), "cache.db")), "user", "pass")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	dataMock.EXPECT().SaveData(gomock.Any(), gomock.Any()).Return(nil, conflictErr(t, nil))
+	_, _ = r.Save(&proto.Data{Id: "d1", Name: "Моя", Version: 2})
+
+	// Без связи решение остаётся в очереди
+	dataMock.EXPECT().SaveData(gomock.Any(), gomock.Any()).Return(nil, errUnavailable)
+	mine := &proto.Data{Name: "Моя"}
+	queued, err := r.Resolve("d1", mine)
+	if err != nil || !queued {
+		t.Fatalf("Resolve = %v, %v, want queued", queued, err)
+	}
+	if mine.Id == "" || mine.Id == "d1" || mine.Version != 0 {
+		t.Errorf("resolved = %v, want new record", mine)
+	}
+	list, _ := r.List()
+	if len(list) != 1 || list[0].Id != mine.Id {
+		t.Errorf("List = %v, want only the new record", list)
+	}
+	if conflicts, _ := r.Conflicts(); len(conflicts) != 0 {
+		t.Errorf("Conflicts = %v, want none", conflicts)
+	}
+}
@@ -0,0 +1,633 @@
+// Package replica объединяет клиент сервера и локальный кэш: записи читаются из кэша,
+// изменения без связи с сервером копятся в очереди и отправляются при следующей синхронизации.
+package replica
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gophkeeper/gophkeeper/internal/client"
+	"github.com/gophkeeper/gophkeeper/internal/client/cache"
+	"github.com/gophkeeper/gophkeeper/internal/client/vault"
+	"github.com/gophkeeper/gophkeeper/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pb "google.golang.org/protobuf/proto"
+)
+
+// ErrNoOfflineCopy — сервер недоступен, а локальной копии для этого пользователя нет
+var ErrNoOfflineCopy = errors.New("server is unreachable and there is no offline copy")
+
+// vaultCheckPlaintext шифруется мастер-ключом при создании кэша: если расшифровать его не удаётся,
+// кэш зашифрован другим ключом и собирается заново
+const vaultCheckPlaintext = "gophkeeper-cache"
+
+// Replica — локальная копия записей пользователя, синхронизируемая с сервером
+type Replica struct {
+	mu       sync.Mutex
+	client   *client.Client
+	cache    *cache.Cache
+	login    string
+	password string
+	offline  bool
+}
+
+// SyncReport — итог синхронизации
+type SyncReport struct {
+	// Pushed — сколько изменений из очереди принял сервер
+	Pushed int
+	// Conflicts — ID записей, изменения которых сервер отклонил из-за конфликта версий
+	Conflicts []string
+	// Failed — сколько изменений сервер отклонил по другим причинам (они останутся в очереди)
+	Failed int
+	// Received — сколько записей получено с сервера
+	Received int
+	// Deleted — сколько удалений получено с сервера
+	Deleted int
+	// Pending — сколько изменений осталось в очереди
+	Pending int64
+	// Changed — записи, изменившиеся в локальной копии (получены с сервера или приняты им)
+	Changed []*proto.Data
+	// Removed — ID записей, удалённых из локальной копии
+	Removed []string
+}
+
+// OfflineKeyCheck возвращает блок проверки мастер-пароля, сохранённый в кэше при последнем входе с сервером
+func OfflineKeyCheck(store *cache.Cache) ([]byte, error) {
+	keyCheck, err := store.Value(cache.KeyKeyCheck)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyCheck) == 0 {
+		return nil, ErrNoOfflineCopy
+	}
+	return keyCheck, nil
+}
+
+// Open связывает клиент с открытым хранилищем ключа и кэш. login и password нужны, чтобы войти
+// на сервер при синхронизации, если сеанс начат без связи с ним.
+func Open(c *client.Client, store *cache.Cache, login, password string) (*Replica, error) {
+	v := c.Vault()
+	if v == nil {
+		return nil, vault.ErrLocked
+	}
+
+	if err := ensureVaultCheck(store, v); err != nil {
+		return nil, err
+	}
+
+	r := &Replica{
+		client:   c,
+		cache:    store,
+		login:    login,
+		password: password,
+		offline:  !c.IsAuthenticated(),
+	}
+	if c.IsAuthenticated() {
+		if err := r.RememberKeyCheck(); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// ensureVaultCheck проверяет, что кэш зашифрован мастер-ключом v. Новый кэш или кэш под другим
+// мастер-ключом (например, после повторной регистрации с тем же логином) очищается.
+func ensureVaultCheck(store *cache.Cache, v *vault.Vault) error {
+	check, err := store.Value(cache.KeyVaultCheck)
+	if err != nil {
+		return err
+	}
+	if check != nil {
+		if plaintext, err := v.Decrypt(check); err == nil && string(plaintext) == vaultCheckPlaintext {
+			return nil
+		}
+	}
+
+	if err := store.Reset(); err != nil {
+		return err
+	}
+	check, err = v.Encrypt([]byte(vaultCheckPlaintext))
+	if err != nil {
+		return err
+	}
+	return store.SetValue(cache.KeyVaultCheck, check)
+}
+
+// Close закрывает кэш
+func (r *Replica) Close() error {
+	// Синхронизация или запрос, который ещё идёт, завершается раньше, чем закрывается кэш
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cache.Close()
+}
+
+// RememberKeyCheck сохраняет текущий блок проверки мастер-пароля для входа без связи с сервером
+func (r *Replica) RememberKeyCheck() error {
+	return r.cache.SetValue(cache.KeyKeyCheck, r.client.KeyCheck())
+}
+
+// SetPassword запоминает новый пароль входа после его смены: с ним выполняется повторный вход,
+// когда сервер не принимает токены сеанса
+func (r *Replica) SetPassword(password string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.password = password
+}
+
+// Offline сообщает, что последнее обращение к серверу не удалось из-за отсутствия связи
+func (r *Replica) Offline() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.offline
+}
+
+// LastSync возвращает время последней успешной синхронизации (нулевое, если её не было)
+func (r *Replica) LastSync() (time.Time, error) {
+	return r.cache.SyncedAt()
+}
+
+// PendingCount возвращает число изменений, ещё не отправленных на сервер
+func (r *Replica) PendingCount() (int64, error) {
+	return r.cache.PendingCount()
+}
+
+// ConflictCount возвращает число неразрешённых конфликтов версий
+func (r *Replica) ConflictCount() (int64, error) {
+	return r.cache.ConflictCount()
+}
+
+// List возвращает записи локальной копии, упорядоченные по названию
+func (r *Replica) List() ([]*proto.Data, error) {
+	records, err := r.cache.Records()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*proto.Data, 0, len(records))
+	for _, rec := range records {
+		d, err := r.open(rec.Data)
+		if err != nil {
+			return nil, fmt.Errorf("open cached record %s: %w", rec.ID, err)
+		}
+		list = append(list, d)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return strings.ToLower(list[i].Name) < strings.ToLower(list[j].Name)
+	})
+	return list, nil
+}
+
+// InitialList возвращает список для показа сразу после входа. Пока устройство ни разу не синхронизировалось,
+// локальной копии нет, а первая синхронизация загружает все записи целиком; тогда список строится
+// из кратких описаний с сервера (ListDataSummaries) — без содержимого, его по одной записи загружает Get.
+func (r *Replica) InitialList() ([]*proto.Data, error) {
+	cursor, err := r.cache.Cursor()
+	if err != nil {
+		return nil, err
+	}
+	if cursor != "" || r.Offline() {
+		return r.List()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []*proto.Data
+	req := &proto.ListDataSummariesRequest{Sort: proto.DataSortField_SORT_NAME}
+	for {
+		var page *client.SummaryPage
+		if err := r.withAuth(func() (err error) {
+			page, err = r.client.ListDataSummaries(req)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		for _, s := range page.Items {
+			list = append(list, summaryData(s))
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		req.PageToken = page.NextPageToken
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return strings.ToLower(list[i].Name) < strings.ToLower(list[j].Name)
+	})
+	return list, nil
+}
+
+// Search ищет записи на сервере (SearchData) и возвращает найденные, все страницы подряд.
+// Записи, которые уже есть в локальной копии, возвращаются из неё целиком, остальные — краткими описаниями.
+func (r *Replica) Search(req *proto.SearchDataRequest) ([]*proto.Data, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	req = pb.Clone(req).(*proto.SearchDataRequest)
+	var list []*proto.Data
+	for {
+		var page *client.SummaryPage
+		if err := r.withAuth(func() (err error) {
+			page, err = r.client.SearchData(req)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		for _, s := range page.Items {
+			rec, err := r.cache.Record(s.Id)
+			switch {
+			case err == nil:
+				d, err := r.open(rec.Data)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, d)
+			case errors.Is(err, cache.ErrNotFound):
+				list = append(list, summaryData(s))
+			default:
+				return nil, err
+			}
+		}
+		if page.NextPageToken == "" {
+			return list, nil
+		}
+		req.PageToken = page.NextPageToken
+	}
+}
+
+// summaryData — запись без содержимого из краткого описания (см. Partial)
+func summaryData(s *proto.DataSummary) *proto.Data {
+	return &proto.Data{
+		Id:        s.Id,
+		Type:      s.Type,
+		Name:      s.Name,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+		Version:   s.Version,
+		BlobId:    s.BlobId,
+	}
+}
+
+// Partial сообщает, что у записи из InitialList есть только краткое описание, а содержимое нужно загрузить через Get
+func Partial(d *proto.Data) bool {
+	return len(d.EncryptedData) == 0
+}
+
+// Get возвращает запись целиком: из локальной копии, а если её там ещё нет — с сервера (GetData),
+// и сохраняет полученную запись в локальной копии
+func (r *Replica) Get(id string) (*proto.Data, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec, err := r.cache.Record(id)
+	if err == nil {
+		return r.open(rec.Data)
+	}
+	if !errors.Is(err, cache.ErrNotFound) {
+		return nil, err
+	}
+	var d *proto.Data
+	if err := r.withAuth(func() (err error) {
+		d, err = r.client.GetData(id)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	sealed, err := r.seal(d)
+	if err != nil {
+		return nil, err
+	}
+	return d, r.cache.PutRecord(id, sealed)
+}
+
+// Save сохраняет запись. Если сервер недоступен (или у записи уже есть неотправленные изменения),
+// запись сохраняется локально и ставится в очередь; тогда queued = true.
+// Новой записи ID назначается на клиенте, чтобы он не менялся при отложенной отправке.
+func (r *Replica) Save(data *proto.Data) (queued bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if data.Id == "" {
+		data.Id = uuid.New().String()
+	}
+
+	pending, err := r.cache.Pending(data.Id)
+	if err != nil {
+		return false, err
+	}
+	// Правки записи, которая уже ждёт в очереди, тоже идут через очередь, чтобы не нарушить порядок
+	if pending == nil && r.client.IsAuthenticated() {
+		var id string
+		var version int64
+		err := r.withAuth(func() (err error) {
+			id, version, err = r.client.SaveData(data)
+			return err
+		})
+		if err == nil {
+			r.offline = false
+			data.Id = id
+			data.Version = version
+			sealed, err := r.seal(data)
+			if err != nil {
+				return false, err
+			}
+			return false, r.cache.PutRecord(id, sealed)
+		}
+		var conflict *client.ConflictError
+		if errors.As(err, &conflict) {
+			// Правка не теряется: она остаётся в очереди, пока конфликт не разрешат
+			return true, r.queueConflict(data, conflict)
+		}
+		if !client.IsUnavailable(err) {
+			return false, err
+		}
+		r.offline = true
+	}
+
+	sealed, err := r.seal(data)
+	if err != nil {
+		return false, err
+	}
+	return true, r.cache.QueueSave(data.Id, data.Version, sealed)
+}
+
+// Delete удаляет запись; без связи с сервером удаление ставится в очередь (queued = true)
+func (r *Replica) Delete(data *proto.Data) (queued bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending, err := r.cache.Pending(data.Id)
+	if err != nil {
+		return false, err
+	}
+	if pending == nil && r.client.IsAuthenticated() {
+		err := r.withAuth(func() error { return r.client.DeleteData(data.Id) })
+		if err == nil || status.Code(err) == codes.NotFound {
+			r.offline = false
+			return false, r.cache.DeleteRecord(data.Id)
+		}
+		if !client.IsUnavailable(err) {
+			return false, err
+		}
+		r.offline = true
+	}
+
+	return true, r.cache.QueueDelete(data.Id, data.Version)
+}
+
+// Do выполняет запрос к серверу call, который не проходит через локальную копию (загрузка файла,
+// смена пароля), с обновлением токена доступа, как у Save, Delete и Sync
+func (r *Replica) Do(call func() error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.withAuth(call)
+}
+
+// withAuth выполняет запрос call. Если сервер отклонил токен доступа (он живёт ACCESS_TOKEN_EXPIRY),
+// токен обновляется и запрос повторяется один раз. Вызывается под r.mu.
+func (r *Replica) withAuth(call func() error) error {
+	err := call()
+	if status.Code(err) != codes.Unauthenticated {
+		return err
+	}
+	if err := r.reauthenticate(); err != nil {
+		return err
+	}
+	return call()
+}
+
+// reauthenticate обновляет токен доступа по refresh-токену, а если сервер его не принял
+// (истёк или отозван сменой пароля на другом устройстве), входит заново с логином и паролем сеанса
+func (r *Replica) reauthenticate() error {
+	err := r.client.RefreshToken()
+	if err == nil || client.IsUnavailable(err) {
+		return err
+	}
+	if err := r.client.Resume(r.login, r.password); err != nil {
+		return err
+	}
+	return r.RememberKeyCheck()
+}
+
+// Sync отправляет очередь на сервер и получает изменения после сохранённого курсора.
+// Если сеанс начат без связи с сервером, перед этим выполняется вход.
+func (r *Replica) Sync() (*SyncReport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report, err := r.sync()
+	if client.IsUnavailable(err) {
+		r.offline = true
+	} else if err == nil {
+		r.offline = false
+	}
+	return report, err
+}
+
+func (r *Replica) sync() (*SyncReport, error) {
+	if !r.client.IsAuthenticated() {
+		if err := r.client.Resume(r.login, r.password); err != nil {
+			return nil, err
+		}
+		if err := r.RememberKeyCheck(); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &SyncReport{}
+	if err := r.push(report); err != nil {
+		return nil, err
+	}
+	if err := r.pull(report); err != nil {
+		return nil, err
+	}
+
+	pending, err := r.cache.PendingCount()
+	if err != nil {
+		return nil, err
+	}
+	report.Pending = pending
+	return report, nil
+}
+
+// push отправляет очередь по порядку. Отказ сервера помечает изменение и не останавливает отправку;
+// потеря связи прерывает её — оставшиеся изменения уйдут при следующей синхронизации.
+func (r *Replica) push(report *SyncReport) error {
+	entries, err := r.cache.Outbox()
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if e.Conflict {
+			report.Conflicts = append(report.Conflicts, e.RecordID)
+			continue
+		}
+
+		changed, sendErr, err := r.send(e)
+		if err != nil {
+			return err
+		}
+		if sendErr == nil {
+			report.Pushed++
+			if changed != nil {
+				report.Changed = append(report.Changed, changed)
+			}
+			continue
+		}
+		if client.IsUnavailable(sendErr) {
+			return sendErr
+		}
+		if errors.Is(sendErr, client.ErrVersionConflict) {
+			report.Conflicts = append(report.Conflicts, e.RecordID)
+		} else {
+			report.Failed++
+		}
+	}
+	return nil
+}
+
+// send отправляет одно изменение из очереди. sendErr — ответ сервера: при отказе изменение
+// помечается в очереди (при потере связи остаётся как есть); err — ошибка локальной копии.
+// changed — сохранённая запись с новой версией.
+func (r *Replica) send(e cache.OutboxEntry) (changed *proto.Data, sendErr, err error) {
+	switch e.Op {
+	case cache.OpSave:
+		data, err := r.open(e.Data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open queued record %s: %w", e.RecordID, err)
+		}
+		data.Version = e.BaseVersion
+		var version int64
+		if sendErr = r.withAuth(func() (err error) {
+			_, version, err = r.client.SaveData(data)
+			return err
+		}); sendErr == nil {
+			data.Version = version
+			sealed, err := r.seal(data)
+			if err != nil {
+				return nil, nil, err
+			}
+			return data, nil, r.cache.Ack(e.RecordID, sealed)
+		}
+	case cache.OpDelete:
+		sendErr = r.withAuth(func() error { return r.client.DeleteData(e.RecordID) })
+		if sendErr == nil || status.Code(sendErr) == codes.NotFound {
+			return nil, nil, r.cache.Ack(e.RecordID, nil)
+		}
+	}
+
+	if client.IsUnavailable(sendErr) {
+		return nil, sendErr, nil
+	}
+	var conflict *client.ConflictError
+	if errors.As(sendErr, &conflict) {
+		theirs, err := r.sealOptional(conflict.Current)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, sendErr, r.cache.MarkConflict(e.RecordID, theirs, sendErr.Error())
+	}
+	return nil, sendErr, r.cache.MarkFailed(e.RecordID, sendErr.Error())
+}
+
+// pull получает изменения после сохранённого курсора и применяет их к кэшу
+func (r *Replica) pull(report *SyncReport) error {
+	cursor, err := r.cache.Cursor()
+	if err != nil {
+		return err
+	}
+	var res *client.SyncResult
+	err = r.withAuth(func() (err error) {
+		res, err = r.client.SyncAll(cursor)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]*proto.Data, len(res.Data))
+	changes := cache.Changes{
+		Records: make([]cache.Record, 0, len(res.Data)),
+		Deleted: make([]string, 0, len(res.Deleted)),
+		Full:    res.FullResync,
+		Cursor:  res.NextCursor,
+	}
+	for _, d := range res.Data {
+		sealed, err := r.seal(d)
+		if err != nil {
+			return err
+		}
+		changes.Records = append(changes.Records, cache.Record{ID: d.Id, Data: sealed})
+		byID[d.Id] = d
+	}
+	for _, t := range res.Deleted {
+		changes.Deleted = append(changes.Deleted, t.Id)
+	}
+	applied, err := r.cache.ApplySync(changes)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range applied.Updated {
+		report.Changed = append(report.Changed, byID[id])
+	}
+	report.Removed = applied.Removed
+	report.Received = len(res.Data)
+	report.Deleted = len(res.Deleted)
+	return nil
+}
+
+// Merge применяет изменения к уже показанному списку: изменённые записи заменяются на месте,
+// новые добавляются в конец, удалённые убираются. Порядок остальных записей сохраняется.
+func Merge(list []*proto.Data, changed []*proto.Data, removedIDs []string) []*proto.Data {
+	removed := make(map[string]bool, len(removedIDs))
+	for _, id := range removedIDs {
+		removed[id] = true
+	}
+
+	index := make(map[string]int, len(list))
+	merged := make([]*proto.Data, 0, len(list)+len(changed))
+	for _, d := range list {
+		if removed[d.Id] {
+			continue
+		}
+		index[d.Id] = len(merged)
+		merged = append(merged, d)
+	}
+	for _, d := range changed {
+		if i, ok := index[d.Id]; ok {
+			merged[i] = d
+			continue
+		}
+		index[d.Id] = len(merged)
+		merged = append(merged, d)
+	}
+	return merged
+}
+
+// seal сериализует запись целиком и шифрует её мастер-ключом
+func (r *Replica) seal(d *proto.Data) ([]byte, error) {
+	raw, err := pb.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.Vault().Encrypt(raw)
+}
+
+// open расшифровывает запись кэша
+func (r *Replica) open(sealed []byte) (*proto.Data, error) {
+	raw, err := r.client.Vault().Decrypt(sealed)
+	if err != nil {
+		return nil, err
+	}
+	d := &proto.Data{}
+	if err := pb.Unmarshal(raw, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
@@ -0,0 +1,574 @@
+package replica_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gophkeeper/gophkeeper/internal/client"
+	"github.com/gophkeeper/gophkeeper/internal/client/cache"
+	"github.com/gophkeeper/gophkeeper/internal/client/mocks"
+	"github.com/gophkeeper/gophkeeper/internal/client/replica"
+	"github.com/gophkeeper/gophkeeper/proto"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var errUnavailable = status.Error(codes.Unavailable, "connection refused")
+
+// onlineClient возвращает клиент, вошедший на сервер и открывший хранилище (первый вход)
+func onlineClient(t *testing.T, ctrl *gomock.Controller) (*client.Client, *mocks.MockAuthServiceClient, *mocks.MockDataServiceClient) {
+	t.Helper()
+	authMock := mocks.NewMockAuthServiceClient(ctrl)
+	dataMock := mocks.NewMockDataServiceClient(ctrl)
+
+	authMock.EXPECT().Login(gomock.Any(), gomock.Any()).
+		Return(&proto.LoginResponse{Success: true, AccessToken: "at", RefreshToken: "rt"}, nil)
+	authMock.EXPECT().SetKeyCheck(gomock.Any(), gomock.Any()).
+		Return(&proto.SetKeyCheckResponse{Success: true}, nil)
+
+	c := client.NewClientWithClients(authMock, dataMock)
+	if err := c.Login("user", "pass"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if err := c.UnlockVault("master"); err != nil {
+		t.Fatalf("UnlockVault: %v", err)
+	}
+	return c, authMock, dataMock
+}
+
+func openCache(t *testing.T, path string) *cache.Cache {
+	t.Helper()
+	store, err := cache.Open(path)
+	if err != nil {
+		t.Fatalf("cache.Open: %v", err)
+	}
+	return store
+}
+
+func TestList_AvailableOfflineAfterSync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	c, _, dataMock := onlineClient(t, ctrl)
+	encrypted, _ := c.Vault().Encrypt([]byte("secret"))
+	dataMock.EXPECT().SyncData(gomock.Any(), gomock.Any()).
+		Return(&proto.SyncDataResponse{
+			Success:    true,
+			Data:       []*proto.Data{{Id: "d1", Name: "Почта", Type: proto.DataType_TEXT, EncryptedData: encrypted, Version: 2}},
+			FullResync: true,
+			NextCursor: "c1",
+		}, nil)
+
+	r, err := replica.Open(c, openCache(t, path), "user", "pass")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if _, err := r.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	_ = r.Close()
+
+	// Новый сеанс без связи: хранилище открывается по блоку проверки из кэша
+	offline := client.NewClientWithClients(mocks.NewMockAuthServiceClient(ctrl), mocks.NewMockDataServiceClient(ctrl))
+	store := openCache(t, path)
+	keyCheck, err := replica.OfflineKeyCheck(store)
+	if err != nil {
+		t.Fatalf("OfflineKeyCheck: %v", err)
+	}
+	if err := offline.UnlockVaultOffline("master", keyCheck); err != nil {
+		t.Fatalf("UnlockVaultOffline: %v", err)
+	}
+	r, err = replica.Open(offline, store, "user", "pass")
+	if err != nil {
+		t.Fatalf("Open offline: %v", err)
+	}
+	defer r.Close()
+
+	list, err := r.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 1 || list[0].Id != "d1" || list[0].Name != "Почта" || list[0].Version != 2 {
+		t.Fatalf("List = %v", list)
+	}
+	plaintext, err := offline.Vault().Decrypt(list[0].EncryptedData)
+	if err != nil || string(plaintext) != "secret" {
+		t.Errorf("Decrypt = %q, %v", plaintext, err)
+	}
+	if !r.Offline() {
+		t.Error("сеанс без входа на сервер должен считаться офлайн")
+	}
+}
+
+// На устройстве без локальной копии список строится из кратких описаний, а запись загружается при открытии
+func TestInitialList_SummariesUntilFirstSync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c, _, dataMock := onlineClient(t, ctrl)
+	r, err := replica.Open(c, openCache(t, filepath.Join(t.TempDir(), "cache.db")), "user", "pass")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	gomock.InOrder(
+		dataMock.EXPECT().ListDataSummaries(gomock.Any(), &proto.ListDataSummariesRequest{Sort: proto.DataSortField_SORT_NAME}).
+			Return(&proto.ListDataSummariesResponse{
+				Success:       true,
+				Summaries:     []*proto.DataSummary{{Id: "d2", Type: proto.DataType_TEXT, Name: "Почта", Version: 1}},
+				NextPageToken: "p2",
+			}, nil),
+		dataMock.EXPECT().ListDataSummaries(gomock.Any(), &proto.ListDataSummariesRequest{Sort: proto.DataSortField_SORT_NAME, PageToken: "p2"}).
+			Return(&proto.ListDataSummariesResponse{
+				Success:   true,
+				Summaries: []*proto.DataSummary{{Id: "d1", Type: proto.DataType_TEXT, Name: "Банк", Version: 3}},
+			}, nil),
+	)
+	list, err := r.InitialList()
+	if err != nil {
+		t.Fatalf("InitialList: %v", err)
+	}
+	if len(list) != 2 || list[0].Id != "d1" || list[1].Id != "d2" || !replica.Partial(list[0]) {
+		t.Fatalf("InitialList = %v, want summaries of d1 and d2 by name", list)
+	}
+
+	encrypted, _ := c.Vault().Encrypt([]byte("secret"))
+	dataMock.EXPECT().GetData(gomock.Any(), &proto.GetDataRequest{DataId: "d1"}).
+		Return(&proto.GetDataResponse{
+			Success: true,
+			Data:    &proto.Data{Id: "d1", Type: proto.DataType_TEXT, Name: "Банк", EncryptedData: encrypted, Version: 3},
+		}, nil)
+	for i := 0; i < 2; i++ {
+		// Второй раз запись читается из локальной копии
+		d, err := r.Get("d1")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if replica.Partial(d) || d.Version != 3 {
+			t.Errorf("Get = %v, want the full record", d)
+		}
+	}
+
+	dataMock.EXPECT().SyncData(gomock.Any(), gomock.Any()).
+		Return(&proto.SyncDataResponse{
+			Success:    true,
+			Data:       []*proto.Data{{Id: "d1", Name: "Банк", EncryptedData: encrypted, Version: 3}},
+			FullResync: true,
+			NextCursor: "c1",
+		}, nil)
+	if _, err := r.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	// После синхронизации список читается из локальной копии, без запросов к серверу
+	if list, err := r.InitialList(); err != nil || len(list) != 1 || replica.Partial(list[0]) {
+		t.Errorf("InitialList after sync = %v, %v, want the local copy", list, err)
+	}
+}
+
+// searchPage проверяет запрос страницы поиска по тегу work
+func searchPage(token string) gomock.Matcher {
+	return gomock.Cond(func(req *proto.SearchDataRequest) bool {
+		return slices.Equal(req.Tags, []string{"work"}) && req.PageToken == token
+	})
+}
+
+// Поиск идёт на сервере, а найденные записи, которые уже есть в локальной копии, возвращаются целиком
+func TestSearch_ReturnsCachedRecordsAndSummaries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c, _, dataMock := onlineClient(t, ctrl)
+	r, err := replica.Open(c, openCache(t, filepath.Join(t.TempDir(), "cache.db")), "user", "pass")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	encrypted, _ := c.Vault().Encrypt([]byte("secret"))
+	dataMock.EXPECT().SyncData(gomock.Any(), gomock.Any()).
+		Return(&proto.SyncDataResponse{
+			Success:    true,
+			Data:       []*proto.Data{{Id: "d1", Name: "Банк", EncryptedData: encrypted, Version: 2}},
+			FullResync: true,
+			NextCursor: "c1",
+		}, nil)
+	if _, err := r.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	gomock.InOrder(
+		dataMock.EXPECT().SearchData(gomock.Any(), searchPage("")).
+			Return(&proto.SearchDataResponse{
+				Success:       true,
+				Summaries:     []*proto.DataSummary{{Id: "d1", Name: "Банк", Version: 2}},
+				NextPageToken: "p2",
+			}, nil),
+		dataMock.EXPECT().SearchData(gomock.Any(), searchPage("p2")).
+			Return(&proto.SearchDataResponse{
+				Success:   true,
+				Summaries: []*proto.DataSummary{{Id: "d2", Name: "Новая", Version: 1}},
+			}, nil),
+	)
+	list, err := r.Search(&proto.SearchDataRequest{Tags: []string{"work"}})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(list) != 2 || list[0].Id != "d1" || replica.Partial(list[0]) || list[1].Id != "d2" || !replica.Partial(list[1]) {
+		t.Errorf("Search = %v, want the cached d1 and the summary of d2", list)
+	}
+}
+
+func TestOfflineKeyCheck_NoCopy(t *testing.T) {
+	store := openCache(t, filepath.Join(t.TempDir(), "cache.db"))
+	defer store.Close()
+
+	if _, err := replica.OfflineKeyCheck(store); !errors.Is(err, replica.ErrNoOfflineCopy) {
+		t.Errorf("err = %v, want ErrNoOfflineCopy", err)
+	}
+}
+
+func TestSave_QueuesWhileUnavailableAndPushesOnSync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c, _, dataMock := onlineClient(t, ctrl)
+	r, err := replica.Open(c, openCache(t, filepath.Join(t.TempDir(), "cache.db")), "user", "pass")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	dataMock.EXPECT().SaveData(gomock.Any(), gomock.Any()).Return(nil, errUnavailable)
+
+	data := &proto.Data{Name: "Банк", Type: proto.DataType_TEXT, EncryptedData: []byte("ct")}
+	queued, err := r.Save(data)
+	if err != nil || !queued {
+		t.Fatalf("Save = %v, %v, want queued", queued, err)
+	}
+	if data.Id == "" {
+		t.Fatal("новой записи должен назначаться ID на клиенте")
+	}
+	if n, _ := r.PendingCount(); n != 1 {
+		t.Errorf("PendingCount = %d, want 1", n)
+	}
+	if !r.Offline() {
+		t.Error("после недоступности сервера реплика должна быть офлайн")
+	}
+
+	// Вторая правка той же записи не уходит на сервер в обход очереди
+	data.Name = "Банк (основной)"
+	if queued, err := r.Save(data); err != nil || !queued {
+		t.Fatalf("second Save = %v, %v, want queued", queued, err)
+	}
+
+	var pushed *proto.SaveDataRequest
+	dataMock.EXPECT().SaveData(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *proto.SaveDataRequest, _ ...grpc.CallOption) (*proto.SaveDataResponse, error) {
+			pushed = req
+			return &proto.SaveDataResponse{Success: true, DataId: req.Data.Id, Version: 1}, nil
+		})
+	dataMock.EXPECT().SyncData(gomock.Any(), gomock.Any()).
+		Return(&proto.SyncDataResponse{Success: true, NextCursor: "c1"}, nil)
+
+	report, err := r.Sync()
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if report.Pushed != 1 || report.Pending != 0 {
+		t.Errorf("report = %+v, want 1 pushed, 0 pending", report)
+	}
+	if pushed.Data.Id != data.Id || pushed.BaseVersion != 0 || pushed.Data.Name != "Банк (основной)" {
+		t.Errorf("pushed = %v, want last edit of %s from base 0", pushed, data.Id)
+	}
+
+	list, _ := r.List()
+	if len(list) != 1 || list[0].Version != 1 {
+		t.Errorf("List = %v, want record at server version 1", list)
+	}
+	if r.Offline() {
+		t.Error("после успешной синхронизации реплика должна быть онлайн")
+	}
+}
+
+// Close при выходе из сеанса ждёт синхронизацию, которая ещё идёт, и не закрывает кэш под ней
+func TestClose_WaitsForRunningSync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c, _, dataMock := onlineClient(t, ctrl)
+	r, err := replica.Open(c, openCache(t, filepath.Join(t.TempDir(), "cache.db")), "user", "pass")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	started, release := make(chan struct{}), make(chan struct{})
+	dataMock.EXPECT().SyncData(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, *proto.SyncDataRequest, ...grpc.CallOption) (*proto.SyncDataResponse, error) {
+			close(started)
+			<-release
+			return &proto.SyncDataResponse{Success: true, FullResync: true, NextCursor: "c1"}, nil
+		})
+	syncErr := make(chan error, 1)
+	go func() {
+		_, err := r.Sync()
+		syncErr <- err
+	}()
+	<-started
+
+	closed := make(chan error, 1)
+	go func() { closed <- r.Close() }()
+	select {
+	case <-closed:
+		t.Fatal("Close returned while Sync was still running")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	if err := <-syncErr; err != nil {
+		t.Errorf("Sync: %v", err)
+	}
+	if err := <-closed; err != nil {
+		t.Errorf("Close: %v", err)
+	}
+}
+
+func TestSync_KeepsConflictingEditQueued(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c, _, dataMock := onlineClient(t, ctrl)
+	r, err := replica.Open(c, openCache(t, filepath.Join(t.TempDir(), "cache.db")), "user", "pass")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	dataMock.EXPECT().SaveData(gomock.Any(), gomock.Any()).Return(nil, errUnavailable)
+	local := &proto.Data{Id: "d1", Name: "Локально", Version: 3}
+	if _, err := r.Save(local); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	st, _ := status.New(codes.Aborted, "conflict").WithDetails(&proto.Data{Id: "d1", Name: "На сервере", Version: 4})
+	dataMock.EXPECT().SaveData(gomock.Any(), gomock.Any()).Return(nil, st.Err())
+	dataMock.EXPECT().SyncData(gomock.Any(), gomock.Any()).
+		Return(&proto.SyncDataResponse{
+			Success:    true,
+			Data:       []*proto.Data{{Id: "d1", Name: "На сервере", Version: 4}},
+			NextCursor: "c1",
+		}, nil)
+
+	report, err := r.Sync()
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if len(report.Conflicts) != 1 || report.Conflicts[0] != "d1" || report.Pending != 1 {
+		t.Errorf("report = %+v, want conflict on d1 kept in queue", report)
+	}
+
+	// Локальная правка не перезаписывается копией с сервера, пока конфликт не разрешён
+	list, _ := r.List()
+	if len(list) != 1 || list[0].Name != "Локально" {
+		t.Errorf("List = %v, want local edit", list)
+	}
+}
+
+func TestDelete_QueuedWhileUnavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c, _, dataMock := onlineClient(t, ctrl)
+	store := openCache(t, filepath.Join(t.TempDir(), "cache.db"))
+	r, err := replica.Open(c, store, "user", "pass")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	dataMock.EXPECT().DeleteData(gomock.Any(), gomock.Any()).Return(nil, errUnavailable)
+	queued, err := r.Delete(&proto.Data{Id: "d1", Version: 2})
+	if err != nil || !queued {
+		t.Fatalf("Delete = %v, %v, want queued", queued, err)
+	}
+
+	dataMock.EXPECT().DeleteData(gomock.Any(), &proto.DeleteDataRequest{DataId: "d1"}).
+		Return(&proto.DeleteDataResponse{Success: true}, nil)
+	dataMock.EXPECT().SyncData(gomock.Any(), gomock.Any()).
+		Return(&proto.SyncDataResponse{Success: true, NextCursor: "c1"}, nil)
+
+	report, err := r.Sync()
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if report.Pushed != 1 || report.Pending != 0 {
+		t.Errorf("report = %+v, want delete pushed", report)
+	}
+}
+
+func TestSync_IncrementalFromSavedCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c, _, dataMock := onlineClient(t, ctrl)
+	r, err := replica.Open(c, openCache(t, filepath.Join(t.TempDir(), "cache.db")), "user", "pass")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	gomock.InOrder(
+		dataMock.EXPECT().SyncData(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *proto.SyncDataRequest, _ ...grpc.CallOption) (*proto.SyncDataResponse, error) {
+				if req.Cursor != "" {
+					t.Errorf("first sync cursor = %q, want empty", req.Cursor)
+				}
+				return &proto.SyncDataResponse{
+					Success:    true,
+					Data:       []*proto.Data{{Id: "d1", Name: "a", Version: 1}, {Id: "d2", Name: "b", Version: 1}},
+					FullResync: true,
+					NextCursor: "c1",
+				}, nil
+			}),
+		dataMock.EXPECT().SyncData(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *proto.SyncDataRequest, _ ...grpc.CallOption) (*proto.SyncDataResponse, error) {
+				if req.Cursor != "c1" {
+					t.Errorf("second sync cursor = %q, want c1", req.Cursor)
+				}
+				return &proto.SyncDataResponse{
+					Success:    true,
+					Data:       []*proto.Data{{Id: "d1", Name: "a2", Version: 2}},
+					Deleted:    []*proto.Tombstone{{Id: "d2", Version: 2}},
+					NextCursor: "c2",
+				}, nil
+			}),
+	)
+
+	first, err := r.Sync()
+	if err != nil {
+		t.Fatalf("first Sync: %v", err)
+	}
+	shown := replica.Merge(nil, first.Changed, first.Removed)
+	if len(shown) != 2 {
+		t.Fatalf("after first sync = %v, want 2 records", shown)
+	}
+	if last, err := r.LastSync(); err != nil || last.IsZero() {
+		t.Errorf("LastSync = %v, %v, want set", last, err)
+	}
+
+	second, err := r.Sync()
+	if err != nil {
+		t.Fatalf("second Sync: %v", err)
+	}
+	if len(second.Changed) != 1 || second.Changed[0].Name != "a2" || len(second.Removed) != 1 || second.Removed[0] != "d2" {
+		t.Fatalf("report = %+v, want d1 changed and d2 removed", second)
+	}
+	shown = replica.Merge(shown, second.Changed, second.Removed)
+	if len(shown) != 1 || shown[0].Id != "d1" || shown[0].Version != 2 {
+		t.Errorf("merged = %v, want updated d1 only", shown)
+	}
+}
+
+func TestMerge_KeepsOrderAndAppendsNew(t *testing.T) {
+	list := []*proto.Data{{Id: "a", Version: 1}, {Id: "b", Version: 1}, {Id: "c", Version: 1}}
+	merged := replica.Merge(list, []*proto.Data{{Id: "c", Version: 2}, {Id: "d", Version: 1}}, []string{"a"})
+
+	var got []string
+	for _, d := range merged {
+		got = append(got, d.Id)
+	}
+	if len(got) != 3 || got[0] != "b" || got[1] != "c" || got[2] != "d" {
+		t.Fatalf("merged ids = %v, want [b c d]", got)
+	}
+	if merged[1].Version != 2 {
+		t.Errorf("c version = %d, want 2", merged[1].Version)
+	}
+	if len(list) != 3 || list[0].Id != "a" {
+		t.Error("исходный список не должен изменяться")
+	}
+}
+
+var errTokenExpired = status.Error(codes.Unauthenticated, "invalid token")
+
+// bearer возвращает токен доступа из метаданных исходящего запроса
+func bearer(ctx context.Context) string {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if v := md.Get("authorization"); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func TestSave_RefreshesExpiredToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c, authMock, dataMock := onlineClient(t, ctrl)
+	r, err := replica.Open(c, openCache(t, filepath.Join(t.TempDir(), "cache.db")), "user", "pass")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	gomock.InOrder(
+		dataMock.EXPECT().SaveData(gomock.Any(), gomock.Any()).Return(nil, errTokenExpired),
+		authMock.EXPECT().RefreshToken(gomock.Any(), &proto.RefreshTokenRequest{RefreshToken: "rt"}).
+			Return(&proto.RefreshTokenResponse{Success: true, AccessToken: "at2", RefreshToken: "rt2"}, nil),
+		dataMock.EXPECT().SaveData(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, req *proto.SaveDataRequest, _ ...grpc.CallOption) (*proto.SaveDataResponse, error) {
+				if got := bearer(ctx); got != "Bearer at2" {
+					t.Errorf("retry authorization = %q, want the refreshed token", got)
+				}
+				return &proto.SaveDataResponse{Success: true, DataId: req.Data.Id, Version: 1}, nil
+			}),
+	)
+
+	queued, err := r.Save(&proto.Data{Name: "Банк", Type: proto.DataType_TEXT, EncryptedData: []byte("ct")})
+	if err != nil || queued {
+		t.Fatalf("Save = %v, %v, want saved on the server", queued, err)
+	}
+	if r.Offline() {
+		t.Error("истёкший токен не означает отсутствие связи")
+	}
+}
+
+func TestSync_LogsInAgainWhenRefreshTokenRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c, authMock, dataMock := onlineClient(t, ctrl)
+	r, err := replica.Open(c, openCache(t, filepath.Join(t.TempDir(), "cache.db")), "user", "pass")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	gomock.InOrder(
+		dataMock.EXPECT().SyncData(gomock.Any(), gomock.Any()).Return(nil, errTokenExpired),
+		authMock.EXPECT().RefreshToken(gomock.Any(), gomock.Any()).
+			Return(&proto.RefreshTokenResponse{Success: false}, status.Error(codes.Unauthenticated, "invalid refresh token")),
+		authMock.EXPECT().Login(gomock.Any(), &proto.LoginRequest{Login: "user", Password: "pass"}).
+			Return(&proto.LoginResponse{Success: true, AccessToken: "at3", RefreshToken: "rt3", KeyCheck: c.KeyCheck()}, nil),
+		dataMock.EXPECT().SyncData(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ *proto.SyncDataRequest, _ ...grpc.CallOption) (*proto.SyncDataResponse, error) {
+				if got := bearer(ctx); got != "Bearer at3" {
+					t.Errorf("retry authorization = %q, want the token from the new login", got)
+				}
+				return &proto.SyncDataResponse{Success: true, NextCursor: "c1"}, nil
+			}),
+	)
+
+	if _, err := r.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if c.Vault() == nil {
+		t.Error("повторный вход не должен закрывать хранилище")
+	}
+}
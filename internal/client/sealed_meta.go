@@ -0,0 +1,110 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/gophkeeper/gophkeeper/internal/client/vault"
+	"github.com/gophkeeper/gophkeeper/proto"
+	pb "google.golang.org/protobuf/proto"
+)
+
+// SetEncryptMetadata включает шифрование названия и метаданных записей при сохранении.
+// На сервере тогда остаются только зашифрованный блок и токен названия для поиска; записи,
+// полученные с сервера, расшифровываются независимо от настройки.
+func (c *Client) SetEncryptMetadata(enabled bool) {
+	c.encryptMeta = enabled
+}
+
+// sealMeta возвращает копию записи для отправки на сервер: при включённом шифровании название
+// и метаданные переносятся в EncryptedMeta, а в SearchToken записывается токен названия
+func (c *Client) sealMeta(data *proto.Data) (*proto.Data, error) {
+	if !c.encryptMeta {
+		if len(data.EncryptedMeta) == 0 && data.SearchToken == "" {
+			return data, nil
+		}
+		// Запись, зашифрованная ранее, сохраняется открыто: устаревший блок не должен остаться на сервере
+		out := pb.Clone(data).(*proto.Data)
+		out.EncryptedMeta = nil
+		out.SearchToken = ""
+		return out, nil
+	}
+	if c.vault == nil {
+		return nil, vault.ErrLocked
+	}
+
+	raw, err := pb.Marshal(&proto.SealedMeta{Name: data.Name, Metadata: data.Metadata})
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := c.vault.Encrypt(raw)
+	if err != nil {
+		return nil, err
+	}
+	token, err := c.vault.SearchToken(data.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	out := pb.Clone(data).(*proto.Data)
+	out.Name = ""
+	out.Metadata = nil
+	out.EncryptedMeta = encrypted
+	out.SearchToken = token
+	return out, nil
+}
+
+// openMeta расшифровывает название и метаданные записи, полученной с сервера, и возвращает их на место
+func (c *Client) openMeta(data *proto.Data) error {
+	if data == nil || len(data.EncryptedMeta) == 0 {
+		return nil
+	}
+	meta, err := c.decryptMeta(data.Id, data.EncryptedMeta)
+	if err != nil {
+		return err
+	}
+	data.Name = meta.Name
+	data.Metadata = meta.Metadata
+	data.EncryptedMeta = nil
+	return nil
+}
+
+// openSummaryMeta расшифровывает название в кратком описании записи
+func (c *Client) openSummaryMeta(summary *proto.DataSummary) error {
+	if summary == nil || len(summary.EncryptedMeta) == 0 {
+		return nil
+	}
+	meta, err := c.decryptMeta(summary.Id, summary.EncryptedMeta)
+	if err != nil {
+		return err
+	}
+	summary.Name = meta.Name
+	summary.EncryptedMeta = nil
+	return nil
+}
+
+// decryptMeta расшифровывает и декодирует SealedMeta записи id
+func (c *Client) decryptMeta(id string, sealed []byte) (*proto.SealedMeta, error) {
+	if c.vault == nil {
+		return nil, vault.ErrLocked
+	}
+
+	raw, err := c.vault.Decrypt(sealed)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt metadata of %s: %w", id, err)
+	}
+	meta := &proto.SealedMeta{}
+	if err := pb.Unmarshal(raw, meta); err != nil {
+		return nil, fmt.Errorf("decode metadata of %s: %w", id, err)
+	}
+	return meta, nil
+}
+
+// openMetaAll расшифровывает название и метаданные у всех записей
+func (c *Client) openMetaAll(list []*proto.Data) error {
+	for _, d := range list {
+		if err := c.openMeta(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
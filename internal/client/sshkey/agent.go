@@ -0,0 +1,86 @@
+package sshkey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+var (
+	// ErrReadOnly — агент отдаёт только ключи из хранилища: добавить или удалить ключ через ssh-add нельзя
+	ErrReadOnly = errors.New("GophKeeper agent is read-only")
+	// ErrNotSocket — по пути сокета агента уже есть файл другого типа
+	ErrNotSocket = errors.New("file exists and is not a socket")
+)
+
+// readOnlyAgent — агент с ключами из записей; ключи не меняются через протокол, а блокировка
+// (ssh-add -x) работает как у ssh-agent
+type readOnlyAgent struct {
+	agent.Agent
+}
+
+func (readOnlyAgent) Add(agent.AddedKey) error { return ErrReadOnly }
+
+func (readOnlyAgent) Remove(ssh.PublicKey) error { return ErrReadOnly }
+
+func (readOnlyAgent) RemoveAll() error { return ErrReadOnly }
+
+// NewAgent создаёт агент с ключами keys; комментарий ключа виден в ssh-add -l
+func NewAgent(keys []*Key) (agent.Agent, error) {
+	keyring := agent.NewKeyring()
+	for _, k := range keys {
+		if err := keyring.Add(agent.AddedKey{PrivateKey: k.Private, Comment: k.Comment}); err != nil {
+			return nil, fmt.Errorf("key %s: %w", k.Fingerprint(), err)
+		}
+	}
+	return readOnlyAgent{keyring}, nil
+}
+
+// Listen открывает Unix-сокет агента по пути path, доступный только владельцу.
+// Оставшийся от прежнего запуска сокет удаляется; другой файл по этому пути — ошибка.
+func Listen(path string) (net.Listener, error) {
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode().Type() != fs.ModeSocket {
+			return nil, fmt.Errorf("%w: %s", ErrNotSocket, path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Serve обслуживает подключения к агенту a, пока не отменён ctx; затем закрывает l
+func Serve(ctx context.Context, l net.Listener, a agent.Agent) error {
+	go func() {
+		<-ctx.Done()
+		_ = l.Close()
+	}()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			_ = agent.ServeAgent(a, conn)
+		}()
+	}
+}
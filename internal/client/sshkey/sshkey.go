@@ -0,0 +1,131 @@
+// Package sshkey разбирает ключи SSH из записей SSH_KEY и отдаёт их клиенту ssh через протокол ssh-agent,
+// не записывая закрытые ключи на диск.
+package sshkey
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	// ErrPassphraseRequired — закрытый ключ зашифрован, а парольная фраза не указана
+	ErrPassphraseRequired = errors.New("private key is encrypted, passphrase required")
+	// ErrWrongPassphrase — парольная фраза не подходит к закрытому ключу
+	ErrWrongPassphrase = errors.New("wrong passphrase")
+	// ErrKeyMismatch — публичный ключ не соответствует закрытому
+	ErrKeyMismatch = errors.New("public key does not match private key")
+	// ErrInvalidPrivateKey — закрытый ключ не разбирается
+	ErrInvalidPrivateKey = errors.New("invalid private key")
+	// ErrInvalidPublicKey — публичный ключ не в формате authorized_keys
+	ErrInvalidPublicKey = errors.New("invalid public key")
+	// ErrUnsupportedKey — тип закрытого ключа не поддерживается SSH
+	ErrUnsupportedKey = errors.New("unsupported key")
+)
+
+// Key — разобранный ключ записи
+type Key struct {
+	// Private — закрытый ключ (*rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey и т.п.)
+	Private any
+	Public  ssh.PublicKey
+	Comment string
+}
+
+// Parse разбирает закрытый ключ (OpenSSH или PEM) с парольной фразой passphrase (пустая — ключ не зашифрован).
+// Публичный ключ public в формате authorized_keys необязателен; если он указан, он должен совпадать
+// с закрытым. Комментарий берётся из comment, а если он пуст — из публичного ключа.
+func Parse(private, passphrase, public, comment string) (*Key, error) {
+	raw, err := parsePrivate([]byte(strings.TrimSpace(private)+"\n"), passphrase)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ssh.NewSignerFromKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedKey, err)
+	}
+
+	key := &Key{Private: raw, Public: signer.PublicKey(), Comment: strings.TrimSpace(comment)}
+	if strings.TrimSpace(public) != "" {
+		pub, pubComment, err := ParsePublic(public)
+		if err != nil {
+			return nil, err
+		}
+		if ssh.FingerprintSHA256(pub) != ssh.FingerprintSHA256(key.Public) {
+			return nil, ErrKeyMismatch
+		}
+		if key.Comment == "" {
+			key.Comment = pubComment
+		}
+	}
+	return key, nil
+}
+
+// parsePrivate разбирает закрытый ключ; парольная фраза нужна только зашифрованному ключу
+func parsePrivate(pemBytes []byte, passphrase string) (any, error) {
+	raw, err := ssh.ParseRawPrivateKey(pemBytes)
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) {
+		if passphrase == "" {
+			return nil, ErrPassphraseRequired
+		}
+		raw, err = ssh.ParseRawPrivateKeyWithPassphrase(pemBytes, []byte(passphrase))
+		if errors.Is(err, x509.IncorrectPasswordError) {
+			return nil, ErrWrongPassphrase
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPrivateKey, err)
+	}
+	return raw, nil
+}
+
+// ParsePublic разбирает публичный ключ в формате authorized_keys и возвращает его с комментарием
+func ParsePublic(public string) (ssh.PublicKey, string, error) {
+	pub, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(strings.TrimSpace(public)))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+	}
+	return pub, comment, nil
+}
+
+// AuthorizedKey возвращает публичный ключ одной строкой authorized_keys с комментарием
+func (k *Key) AuthorizedKey() string {
+	line := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(k.Public)))
+	if k.Comment != "" {
+		line += " " + k.Comment
+	}
+	return line
+}
+
+// Fingerprint возвращает отпечаток SHA256 публичного ключа, как его показывает ssh-keygen -l
+func (k *Key) Fingerprint() string {
+	return ssh.FingerprintSHA256(k.Public)
+}
+
+// Describe возвращает тип и длину ключа, например «ED25519» или «RSA 3072»
+func (k *Key) Describe() string {
+	return Describe(k.Public)
+}
+
+// Describe возвращает тип и длину публичного ключа pub
+func Describe(pub ssh.PublicKey) string {
+	crypto, ok := pub.(ssh.CryptoPublicKey)
+	if !ok {
+		return pub.Type()
+	}
+	switch key := crypto.CryptoPublicKey().(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d", key.N.BitLen())
+	case *ecdsa.PublicKey:
+		return fmt.Sprintf("ECDSA %d", key.Curve.Params().BitSize)
+	case ed25519.PublicKey:
+		return "ED25519"
+	}
+	return pub.Type()
+}
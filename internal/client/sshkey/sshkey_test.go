@@ -0,0 +1,188 @@
+package sshkey_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gophkeeper/gophkeeper/internal/client/sshkey"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// newKey возвращает закрытый ключ Ed25519 в формате OpenSSH (зашифрованный, если указана passphrase)
+// и публичный ключ в формате authorized_keys
+func newKey(t *testing.T, passphrase string) (private, public string) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var block *pem.Block
+	if passphrase == "" {
+		block, err = ssh.MarshalPrivateKey(priv, "deploy@ci")
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(priv, "deploy@ci", []byte(passphrase))
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(block)), strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub))) + " deploy@ci"
+}
+
+func TestParse(t *testing.T) {
+	private, public := newKey(t, "")
+	key, err := sshkey.Parse(private, "", public, "")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if key.Comment != "deploy@ci" {
+		t.Errorf("comment %q, want comment from public key", key.Comment)
+	}
+	if key.Describe() != "ED25519" {
+		t.Errorf("Describe() = %q", key.Describe())
+	}
+	if !strings.HasPrefix(key.Fingerprint(), "SHA256:") {
+		t.Errorf("Fingerprint() = %q", key.Fingerprint())
+	}
+	if key.AuthorizedKey() != public {
+		t.Errorf("AuthorizedKey() = %q, want %q", key.AuthorizedKey(), public)
+	}
+
+	// Публичный ключ необязателен, а комментарий записи важнее комментария ключа
+	key, err = sshkey.Parse(private, "", "", "prod")
+	if err != nil {
+		t.Fatalf("Parse without public key: %v", err)
+	}
+	if key.Comment != "prod" {
+		t.Errorf("comment %q, want prod", key.Comment)
+	}
+}
+
+func TestParse_RSA(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := sshkey.Parse(string(pem.EncodeToMemory(block)), "", "", "")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if key.Describe() != "RSA 2048" {
+		t.Errorf("Describe() = %q", key.Describe())
+	}
+}
+
+func TestParse_Passphrase(t *testing.T) {
+	private, public := newKey(t, "correct horse")
+	if _, err := sshkey.Parse(private, "", public, ""); !errors.Is(err, sshkey.ErrPassphraseRequired) {
+		t.Errorf("without passphrase: %v, want ErrPassphraseRequired", err)
+	}
+	if _, err := sshkey.Parse(private, "wrong", public, ""); !errors.Is(err, sshkey.ErrWrongPassphrase) {
+		t.Errorf("wrong passphrase: %v, want ErrWrongPassphrase", err)
+	}
+	if _, err := sshkey.Parse(private, "correct horse", public, ""); err != nil {
+		t.Errorf("correct passphrase: %v", err)
+	}
+
+	// Парольная фраза у незашифрованного ключа не мешает
+	plain, _ := newKey(t, "")
+	if _, err := sshkey.Parse(plain, "unused", "", ""); err != nil {
+		t.Errorf("unencrypted key with passphrase: %v", err)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	private, _ := newKey(t, "")
+	_, otherPublic := newKey(t, "")
+	if _, err := sshkey.Parse(private, "", otherPublic, ""); !errors.Is(err, sshkey.ErrKeyMismatch) {
+		t.Errorf("other public key: %v, want ErrKeyMismatch", err)
+	}
+	if _, err := sshkey.Parse("not a key", "", "", ""); !errors.Is(err, sshkey.ErrInvalidPrivateKey) {
+		t.Errorf("garbage private key: %v, want ErrInvalidPrivateKey", err)
+	}
+	if _, err := sshkey.Parse(private, "", "ssh-ed25519 garbage", ""); !errors.Is(err, sshkey.ErrInvalidPublicKey) {
+		t.Errorf("garbage public key: %v, want ErrInvalidPublicKey", err)
+	}
+}
+
+func TestServe(t *testing.T) {
+	private, _ := newKey(t, "")
+	key, err := sshkey.Parse(private, "", "", "deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := sshkey.NewAgent([]*sshkey.Key{key})
+	if err != nil {
+		t.Fatalf("NewAgent: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "agent.sock")
+	l, err := sshkey.Listen(path)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- sshkey.Serve(ctx, l, a) }()
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := agent.NewClient(conn)
+
+	keys, err := client.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(keys) != 1 || keys[0].Comment != "deploy" || ssh.FingerprintSHA256(keys[0]) != key.Fingerprint() {
+		t.Fatalf("List = %v", keys)
+	}
+	data := []byte("challenge")
+	sig, err := client.Sign(keys[0], data)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if err := key.Public.Verify(data, sig); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	if err := client.RemoveAll(); err == nil {
+		t.Error("RemoveAll: no error from read-only agent")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Serve: %v", err)
+	}
+}
+
+func TestListen_RefusesRegularFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.sock")
+	if err := os.WriteFile(path, []byte("keep"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sshkey.Listen(path); !errors.Is(err, sshkey.ErrNotSocket) {
+		t.Errorf("Listen over a file: %v, want ErrNotSocket", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "keep" {
+		t.Errorf("file was changed: %q, %v", data, err)
+	}
+}
@@ -1,21 +1,28 @@
 package tui
 
 import (
+	"errors"
 	"iter"
+	"maps"
 	"slices"
+	"strings"
 
 	"fmt"
 
+	"github.com/charmbracelet/bubbles/filepicker"
 	"github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gophkeeper/gophkeeper/internal/client/format"
+	"github.com/gophkeeper/gophkeeper/internal/client/generator"
+	"github.com/gophkeeper/gophkeeper/internal/client/replica"
 	"github.com/gophkeeper/gophkeeper/proto"
 )
 
 const (
 	addDataStepNameType = 0
 	addDataStepFields   = 1
+	addDataStepMetadata = 2
 )
 
 // AddDataModel представляет модель добавления данных
@@ -28,34 +35,28 @@ type AddDataModel struct {
 	focused    int
 	err        error
 
-	// Поля шага 2 (по типу)
-	loginInput   textinput.Model
-	passwordInput textinput.Model
-	textInput    textinput.Model
-	cardNumber   textinput.Model
-	cardExpiry   textinput.Model
-	cardCVV      textinput.Model
-	cardHolder   textinput.Model
+	// Поля шага 2 строятся по описанию типа записи (format.RecordType); inputsType — тип, для которого они построены
+	inputs     []fieldInput
+	inputsType proto.DataType
+	fieldFocus int
+
+	// Запись с файлом: путь к новому файлу вводится в binaryInput или выбирается в picker.
+	// binaryFields — текущее содержимое (прикреплённый файл или данные в старом формате), blobID — ссылка на файл
 	binaryInput  textinput.Model
-	fieldFocus   int
-}
+	binaryFields map[string]string
+	blobID       string
+	picking      bool
+	picker       filepicker.Model
 
-func newLoginInput() textinput.Model {
-	t := textinput.New()
-	t.Placeholder = "Логин"
-	t.CharLimit = 200
-	t.Width = 38
-	return t
-}
+	// Генератор пароля, открытый из поля пароля (Ctrl+G)
+	generating bool
+	generator  passwordGenerator
 
-func newPasswordInput() textinput.Model {
-	t := textinput.New()
-	t.Placeholder = "Пароль"
-	t.CharLimit = 200
-	t.Width = 38
-	t.EchoMode = textinput.EchoPassword
-	t.EchoCharacter = '•'
-	return t
+	// Шаг 3: метаданные
+	metadata metadataEditor
+
+	// editing — изменяемая запись; nil при добавлении новой
+	editing *proto.Data
 }
 
 func newTextInput(placeholder string) textinput.Model {
@@ -82,14 +83,7 @@ func NewAddDataModel(m *Model) *AddDataModel {
 		typeSelect:  0,
 		types:       types,
 		focused:     0,
-		loginInput:  newLoginInput(),
-		passwordInput: newPasswordInput(),
-		textInput:   newTextInput("Содержимое"),
-		cardNumber:  newTextInput("Номер карты"),
-		cardExpiry:  newTextInput("Срок (MM/YY)"),
-		cardCVV:     newTextInput("CVV"),
-		cardHolder:  newTextInput("Держатель карты"),
-		binaryInput: newTextInput("Данные (base64 или текст)"),
+		binaryInput: newTextInput("Путь к файлу"),
 		fieldFocus:  0,
 	}
 }
@@ -98,30 +92,46 @@ func (m *AddDataModel) dataType() proto.DataType {
 	return format.DataTypeFromIndex(m.typeSelect)
 }
 
+func (m *AddDataModel) recordType() *format.RecordType {
+	return format.LookupRecordType(m.dataType())
+}
+
+// hasFile — содержимое записи выбранного типа — прикреплённый файл
+func (m *AddDataModel) hasFile() bool {
+	rt := m.recordType()
+	return rt != nil && rt.File
+}
+
 func (m *AddDataModel) fieldCount() int {
 	return format.FieldCount(m.dataType())
 }
 
-func (m *AddDataModel) focusFirstField() {
+// ensureInputs строит поля ввода для выбранного типа; при возврате к тому же типу введённое сохраняется
+func (m *AddDataModel) ensureInputs() {
+	dt := m.dataType()
+	if m.inputs != nil && m.inputsType == dt {
+		return
+	}
+	m.inputs, m.inputsType = nil, dt
+	if rt := m.recordType(); rt != nil && !rt.File {
+		m.inputs = make([]fieldInput, 0, len(rt.Fields))
+		for _, f := range rt.Fields {
+			m.inputs = append(m.inputs, newFieldInput(f))
+		}
+	}
+}
+
+func (m *AddDataModel) focusFirstField() tea.Cmd {
 	m.fieldFocus = 0
-	m.loginInput.Blur()
-	m.passwordInput.Blur()
-	m.textInput.Blur()
-	m.cardNumber.Blur()
-	m.cardExpiry.Blur()
-	m.cardCVV.Blur()
-	m.cardHolder.Blur()
-	m.binaryInput.Blur()
-	switch m.dataType() {
-	case proto.DataType_LOGIN_PASSWORD:
-		m.loginInput.Focus()
-	case proto.DataType_TEXT:
-		m.textInput.Focus()
-	case proto.DataType_BINARY:
-		m.binaryInput.Focus()
-	case proto.DataType_BANK_CARD:
-		m.cardNumber.Focus()
+	return m.moveFocusFields(0)
+}
+
+// blurFields снимает фокус со всех полей шага 2
+func (m *AddDataModel) blurFields() {
+	for i := range m.inputs {
+		m.inputs[i].Blur()
 	}
+	m.binaryInput.Blur()
 }
 
 func (m *AddDataModel) moveFocusFields(delta int) tea.Cmd {
@@ -129,104 +139,74 @@ func (m *AddDataModel) moveFocusFields(delta int) tea.Cmd {
 	if n == 0 {
 		return nil
 	}
-	m.fieldFocus += delta
-	if m.fieldFocus < 0 {
-		m.fieldFocus = 0
+	m.fieldFocus = min(max(m.fieldFocus+delta, 0), n-1)
+	m.blurFields()
+	if m.hasFile() {
+		return m.binaryInput.Focus()
 	}
-	if m.fieldFocus >= n {
-		m.fieldFocus = n - 1
-	}
-	m.loginInput.Blur()
-	m.passwordInput.Blur()
-	m.textInput.Blur()
-	m.cardNumber.Blur()
-	m.cardExpiry.Blur()
-	m.cardCVV.Blur()
-	m.cardHolder.Blur()
-	m.binaryInput.Blur()
-	var focused textinput.Model
-	switch m.dataType() {
-	case proto.DataType_LOGIN_PASSWORD:
-		if m.fieldFocus == 0 {
-			focused = m.loginInput
-			m.loginInput.Focus()
-		} else {
-			focused = m.passwordInput
-			m.passwordInput.Focus()
-		}
-	case proto.DataType_TEXT:
-		focused = m.textInput
-		m.textInput.Focus()
-	case proto.DataType_BINARY:
-		focused = m.binaryInput
-		m.binaryInput.Focus()
-	case proto.DataType_BANK_CARD:
-		switch m.fieldFocus {
-		case 0:
-			focused = m.cardNumber
-			m.cardNumber.Focus()
-		case 1:
-			focused = m.cardExpiry
-			m.cardExpiry.Focus()
-		case 2:
-			focused = m.cardCVV
-			m.cardCVV.Focus()
-		case 3:
-			focused = m.cardHolder
-			m.cardHolder.Focus()
-		}
+	return m.inputs[m.fieldFocus].Focus()
+}
+
+// focusedInput возвращает поле ввода в фокусе (nil для записи с файлом)
+func (m *AddDataModel) focusedInput() *fieldInput {
+	if m.fieldFocus < len(m.inputs) {
+		return &m.inputs[m.fieldFocus]
 	}
-	_ = focused
 	return nil
 }
 
-func (m *AddDataModel) getFocusedInput() *textinput.Model {
-	switch m.dataType() {
-	case proto.DataType_LOGIN_PASSWORD:
-		if m.fieldFocus == 0 {
-			return &m.loginInput
+// onMultiline — в фокусе многострочное поле: Enter и стрелки работают внутри него
+func (m *AddDataModel) onMultiline() bool {
+	in := m.focusedInput()
+	return m.step == addDataStepFields && in != nil && in.field.Multiline
+}
+
+// validateFields проверяет поля по их описанию и переводит фокус на первое неверное
+func (m *AddDataModel) validateFields() error {
+	for i := range m.inputs {
+		f := m.inputs[i].field
+		if f.Validate == nil {
+			continue
+		}
+		if err := f.Validate(m.inputs[i].Value()); err != nil {
+			m.fieldFocus = i
+			m.moveFocusFields(0)
+			return fmt.Errorf("%s: %w", f.Label, err)
 		}
-		return &m.passwordInput
-	case proto.DataType_TEXT:
-		return &m.textInput
-	case proto.DataType_BINARY:
-		return &m.binaryInput
-	case proto.DataType_BANK_CARD:
-		switch m.fieldFocus {
-		case 0:
-			return &m.cardNumber
-		case 1:
-			return &m.cardExpiry
-		case 2:
-			return &m.cardCVV
-		case 3:
-			return &m.cardHolder
+	}
+	rt := m.recordType()
+	if rt == nil || rt.Validate == nil {
+		return nil
+	}
+	err := rt.Validate(m.collectFieldValues())
+	var fieldErr *format.FieldError
+	if errors.As(err, &fieldErr) {
+		if i := slices.IndexFunc(m.inputs, func(in fieldInput) bool { return in.field.Key == fieldErr.Key }); i >= 0 {
+			m.fieldFocus = i
+			m.moveFocusFields(0)
+			return fmt.Errorf("%s: %w", m.inputs[i].field.Label, fieldErr.Err)
 		}
 	}
-	return &m.textInput
+	return err
 }
 
 func (m *AddDataModel) buildEncryptedData() ([]byte, error) {
 	fields := m.collectFieldValues()
-	return format.BuildPayload(m.dataType(), fields)
+	payload, err := format.BuildPayload(m.dataType(), fields)
+	if err != nil {
+		return nil, err
+	}
+	return m.model.client.Vault().Encrypt(payload)
 }
 
 func (m *AddDataModel) collectFieldValues() map[string]string {
 	fields := make(map[string]string)
-	dt := m.dataType()
-	switch dt {
-	case proto.DataType_LOGIN_PASSWORD:
-		fields[format.FieldLogin] = m.loginInput.Value()
-		fields[format.FieldPassword] = m.passwordInput.Value()
-	case proto.DataType_TEXT:
-		fields[format.FieldText] = m.textInput.Value()
-	case proto.DataType_BINARY:
-		fields[format.FieldBinary] = m.binaryInput.Value()
-	case proto.DataType_BANK_CARD:
-		fields[format.FieldNumber] = m.cardNumber.Value()
-		fields[format.FieldExpiry] = m.cardExpiry.Value()
-		fields[format.FieldCVV] = m.cardCVV.Value()
-		fields[format.FieldHolder] = m.cardHolder.Value()
+	if m.hasFile() {
+		maps.Copy(fields, m.binaryFields)
+		return fields
+	}
+	for i := range m.inputs {
+		fields[m.inputs[i].field.Key] = m.inputs[i].Value()
 	}
 	return fields
 }
@@ -238,16 +218,42 @@ func (m *AddDataModel) Init() tea.Cmd {
 func (m *AddDataModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	if m.picking {
+		return m.updatePicker(msg)
+	}
+	if m.generating {
+		return m.updateGenerator(msg)
+	}
+	if m.step == addDataStepMetadata {
+		return m.updateMetadata(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "esc", "q":
+		key := msg.String()
+		// В многострочном поле стрелки двигают курсор, а Enter переводит строку
+		if m.onMultiline() && (key == "up" || key == "down" || key == "enter") {
+			break
+		}
+		switch key {
+		case "q":
+			// q закрывает форму только из выбора типа, в полях ввода это обычная буква
+			if m.step != addDataStepNameType || m.focused == 0 {
+				break
+			}
+			fallthrough
+		case "esc":
 			if m.step == addDataStepFields {
 				m.step = addDataStepNameType
-				m.focusFirstField()
+				m.blurFields()
 				m.err = nil
 				return m, nil
 			}
+			if m.editing != nil {
+				m.model.state = StateViewData
+				viewModel := NewViewDataModel(m.model)
+				return viewModel, viewModel.Init()
+			}
 			m.model.state = StateMainMenu
 			return NewMainMenuModel(m.model), nil
 		case "tab", "down":
@@ -255,28 +261,40 @@ func (m *AddDataModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.focused == 0 {
 					m.focused = 1
 					m.nameInput.Blur()
-				} else {
+				} else if m.editing == nil {
 					m.typeSelect = (m.typeSelect + 1) % len(m.types)
 				}
-			} else {
-				m.moveFocusFields(1)
+				return m, nil
 			}
-			return m, nil
-		case "up":
+			return m, m.moveFocusFields(1)
+		case "shift+tab", "up":
 			if m.step == addDataStepNameType {
 				if m.focused == 1 {
-					if m.typeSelect > 0 {
+					if m.typeSelect > 0 && m.editing == nil {
 						m.typeSelect--
 					} else {
 						m.focused = 0
 						m.nameInput.Focus()
 					}
 				}
-			} else {
-				m.moveFocusFields(-1)
+				return m, nil
 			}
-			return m, nil
-		case "enter":
+			return m, m.moveFocusFields(-1)
+		case "ctrl+o":
+			if m.step == addDataStepFields && m.hasFile() {
+				m.picker = newFilePicker()
+				m.picking = true
+				m.err = nil
+				return m, m.picker.Init()
+			}
+		case "ctrl+g":
+			if m.onPasswordField() {
+				m.generator = newPasswordGenerator()
+				m.generating = true
+				m.err = nil
+				return m, nil
+			}
+		case "enter", "ctrl+s":
 			if m.step == addDataStepNameType {
 				name := m.nameInput.Value()
 				if name == "" {
@@ -285,11 +303,20 @@ func (m *AddDataModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				m.err = nil
 				m.step = addDataStepFields
-				m.focusFirstField()
-			} else {
-				return m.handleSubmit()
+				m.ensureInputs()
+				return m, m.focusFirstField()
 			}
-			return m, nil
+			if err := m.validateFields(); err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.err = nil
+			m.step = addDataStepMetadata
+			m.blurFields()
+			if len(m.metadata.rows) == 0 {
+				return m, m.metadata.addRow()
+			}
+			return m, m.metadata.setFocus(0)
 		}
 	case error:
 		m.err = msg
@@ -301,13 +328,110 @@ func (m *AddDataModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 	if m.step == addDataStepFields {
-		inp := m.getFocusedInput()
-		*inp, cmd = inp.Update(msg)
-		return m, cmd
+		if m.hasFile() {
+			m.binaryInput, cmd = m.binaryInput.Update(msg)
+			return m, cmd
+		}
+		if in := m.focusedInput(); in != nil {
+			return m, in.Update(msg)
+		}
+	}
+	return m, nil
+}
+
+// updatePicker обрабатывает ввод при выборе файла: выбранный путь подставляется в binaryInput
+func (m *AddDataModel) updatePicker(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" {
+		m.picking = false
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.picker, cmd = m.picker.Update(msg)
+	if ok, path := m.picker.DidSelectFile(msg); ok {
+		m.binaryInput.SetValue(path)
+		m.binaryInput.CursorEnd()
+		m.picking = false
+	}
+	return m, cmd
+}
+
+// onPasswordField — в фокусе поле, для которого доступен генератор паролей
+func (m *AddDataModel) onPasswordField() bool {
+	in := m.focusedInput()
+	return m.step == addDataStepFields && in != nil && in.field.Generate
+}
+
+// updateGenerator обрабатывает ввод в генераторе пароля: Enter подставляет пароль в поле, Esc — отмена
+func (m *AddDataModel) updateGenerator(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			m.generating = false
+		case "enter":
+			if in := m.focusedInput(); in != nil && m.generator.err == nil {
+				in.SetValue(m.generator.value)
+				m.generating = false
+			}
+		default:
+			m.generator.handleKey(msg.String())
+		}
 	}
 	return m, nil
 }
 
+// attachSelectedFile загружает файл, указанный в binaryInput. Без пути у изменяемой записи
+// остаётся прежнее содержимое; новой записи BINARY файл нужен обязательно.
+func (m *AddDataModel) attachSelectedFile() error {
+	path := strings.TrimSpace(m.binaryInput.Value())
+	if path == "" {
+		if len(m.binaryFields) == 0 {
+			return errors.New("укажите путь к файлу (Ctrl+O — выбрать)")
+		}
+		return nil
+	}
+
+	var fields map[string]string
+	var blobID string
+	err := m.model.replica.Do(func() (err error) {
+		fields, blobID, err = m.model.client.AttachFile(path)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	// Файл уже загружен: при повторной попытке сохранения он не загружается заново
+	m.binaryFields = fields
+	m.blobID = blobID
+	m.binaryInput.SetValue("")
+	return nil
+}
+
+// updateMetadata обрабатывает ввод на шаге метаданных
+func (m *AddDataModel) updateMetadata(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			m.step = addDataStepFields
+			m.err = nil
+			m.metadata.blur()
+			m.moveFocusFields(0)
+			return m, nil
+		case "tab", "down":
+			return m, m.metadata.move(1)
+		case "shift+tab", "up":
+			return m, m.metadata.move(-1)
+		case "ctrl+n":
+			return m, m.metadata.addRow()
+		case "ctrl+x":
+			return m, m.metadata.removeRow()
+		case "enter":
+			return m.handleSubmit()
+		}
+	}
+	return m, m.metadata.update(msg)
+}
+
 func (m *AddDataModel) handleSubmit() (tea.Model, tea.Cmd) {
 	name := m.nameInput.Value()
 	if name == "" {
@@ -315,29 +439,79 @@ func (m *AddDataModel) handleSubmit() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	metadata, err := m.metadata.metadata()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	if m.hasFile() {
+		if err := m.attachSelectedFile(); err != nil {
+			m.err = err
+			return m, nil
+		}
+	}
+
 	payload, err := m.buildEncryptedData()
 	if err != nil {
 		m.err = err
 		return m, nil
 	}
 
-	data := format.BuildDataForSave(name, m.dataType(), payload)
-	_, _, err = m.model.client.SaveData(data)
+	var data *proto.Data
+	if m.editing != nil {
+		data = m.editedData(name, payload)
+	} else {
+		data = format.BuildDataForSave(name, m.dataType(), payload)
+	}
+	data.Metadata = metadata
+	data.BlobId = m.blobID
+	queued, err := m.model.replica.Save(data)
+	var conflict *replica.ConflictError
+	if errors.As(err, &conflict) {
+		m.model.state = StateConflict
+		return NewConflictModel(m.model, []*replica.Conflict{conflict.Conflict}), nil
+	}
 	if err != nil {
 		m.err = err
 		return m, nil
 	}
 
+	m.model.applyLocalChange(data, "")
 	m.model.message = "Данные успешно сохранены"
+	if queued {
+		m.model.message = "Данные сохранены локально и будут отправлены при синхронизации"
+	}
+	if m.editing != nil {
+		m.model.currentData = data
+		m.model.state = StateViewData
+		viewModel := NewViewDataModel(m.model)
+		return viewModel, viewModel.Init()
+	}
 	m.model.state = StateMainMenu
 	return NewMainMenuModel(m.model), nil
 }
 
 func (m *AddDataModel) View() string {
 	var view []string
-	view = append(view, titleStyle.Render("Добавление данных"))
+	title := "Добавление данных"
+	if m.editing != nil {
+		title = "Редактирование данных"
+	}
+	view = append(view, titleStyle.Render(title))
 	view = append(view, "")
 
+	if m.picking {
+		view = append(view, "Выбор файла: "+m.picker.CurrentDirectory)
+		view = append(view, "")
+		view = append(view, m.picker.View())
+		view = append(view, "↑↓ — выбор, →/Enter — открыть каталог или выбрать файл, ← — вверх, Esc — отмена")
+		return menuStyle.Render(lipgloss.JoinVertical(lipgloss.Left, view...))
+	}
+	if m.generating {
+		return menuStyle.Render(lipgloss.JoinVertical(lipgloss.Left, m.generator.view()...))
+	}
+
 	if m.step == addDataStepNameType {
 		if m.focused == 0 {
 			view = append(view, focusedStyle.Render(m.nameInput.View()))
@@ -346,72 +520,43 @@ func (m *AddDataModel) View() string {
 		}
 		view = append(view, "")
 		view = append(view, "Тип данных:")
-		view = append(view, slices.Collect(addDataTypesToLinesSeq(m.types, m.typeSelect, m.focused == 1))...)
+		if m.editing != nil {
+			view = append(view, menuItemStyle.Render("  "+m.types[m.typeSelect]+" (тип записи не меняется)"))
+		} else {
+			view = append(view, slices.Collect(addDataTypesToLinesSeq(m.types, m.typeSelect, m.focused == 1))...)
+		}
+		view = append(view, "")
+		view = append(view, "Tab/↓ — следующий, Enter — далее, Esc — назад/отмена")
+	} else if m.step == addDataStepMetadata {
+		view = append(view, inputStyle.Render("Название: "+m.nameInput.Value()))
+		view = append(view, "")
+		view = append(view, "Метаданные (необязательно):")
+		view = append(view, m.metadata.view()...)
 		view = append(view, "")
-		view = append(view, "Tab/↓ — следующий, Enter — далее или сохранить, Esc — назад/отмена")
+		view = append(view, "Tab/↓ — следующее поле, Ctrl+N — добавить строку, Ctrl+X — удалить строку")
+		view = append(view, "Enter — сохранить, Esc — назад")
 	} else {
 		view = append(view, inputStyle.Render("Название: "+m.nameInput.Value()))
 		view = append(view, "")
 		view = append(view, "Тип: "+m.types[m.typeSelect])
 		view = append(view, "")
 
-		switch m.dataType() {
-		case proto.DataType_LOGIN_PASSWORD:
-			if m.fieldFocus == 0 {
-				view = append(view, focusedStyle.Render(m.loginInput.View()))
-			} else {
-				view = append(view, inputStyle.Render(m.loginInput.View()))
-			}
-			view = append(view, "")
-			if m.fieldFocus == 1 {
-				view = append(view, focusedStyle.Render(m.passwordInput.View()))
-			} else {
-				view = append(view, inputStyle.Render(m.passwordInput.View()))
-			}
-		case proto.DataType_TEXT:
-			if m.fieldFocus == 0 {
-				view = append(view, focusedStyle.Render(m.textInput.View()))
-			} else {
-				view = append(view, inputStyle.Render(m.textInput.View()))
-			}
-		case proto.DataType_BINARY:
-			if m.fieldFocus == 0 {
-				view = append(view, focusedStyle.Render(m.binaryInput.View()))
-			} else {
-				view = append(view, inputStyle.Render(m.binaryInput.View()))
-			}
-		case proto.DataType_BANK_CARD:
-			style := inputStyle
-			if m.fieldFocus == 0 {
-				style = focusedStyle
-			}
-			view = append(view, style.Render(m.cardNumber.View()))
-			view = append(view, "")
-			style = inputStyle
-			if m.fieldFocus == 1 {
-				style = focusedStyle
-			}
-			view = append(view, style.Render(m.cardExpiry.View()))
-			view = append(view, "")
-			style = inputStyle
-			if m.fieldFocus == 2 {
-				style = focusedStyle
-			}
-			view = append(view, style.Render(m.cardCVV.View()))
-			view = append(view, "")
-			style = inputStyle
-			if m.fieldFocus == 3 {
-				style = focusedStyle
-			}
-			view = append(view, style.Render(m.cardHolder.View()))
+		if m.hasFile() {
+			view = append(view, m.fileView()...)
+		} else {
+			view = append(view, m.fieldsView()...)
 		}
 		view = append(view, "")
-		view = append(view, "Tab/↓ — следующее поле, Enter — сохранить, Esc — назад")
+		if m.onMultiline() {
+			view = append(view, "Tab — следующее поле, Ctrl+S — далее к метаданным, Esc — назад")
+		} else {
+			view = append(view, "Tab/↓ — следующее поле, Enter — далее к метаданным, Esc — назад")
+		}
 	}
 
 	if m.err != nil {
 		view = append(view, "")
-		view = append(view, errorStyle.Render(fmt.Sprintf("Ошибка: %v", m.err)))
+		view = append(view, errorStyle.Render("Ошибка: "+errorText(m.err)))
 	}
 
 	view = append(view, "")
@@ -419,6 +564,49 @@ func (m *AddDataModel) View() string {
 	return menuStyle.Render(lipgloss.JoinVertical(lipgloss.Left, view...))
 }
 
+// fieldsView — поля ввода типа записи с подсказками под ними
+func (m *AddDataModel) fieldsView() []string {
+	var view []string
+	for i := range m.inputs {
+		in := &m.inputs[i]
+		if i > 0 {
+			view = append(view, "")
+		}
+		if in.field.Multiline {
+			view = append(view, in.field.Label+":")
+		}
+		view = append(view, in.View(i == m.fieldFocus))
+		if in.field.Generate {
+			if password := in.Value(); password != "" {
+				view = append(view, strengthLine(generator.Estimate(password)))
+			}
+			view = append(view, "Ctrl+G — сгенерировать пароль")
+		}
+		if in.field.Preview != nil && i == m.fieldFocus {
+			if preview := in.field.Preview(in.Value()); preview != "" {
+				view = append(view, preview)
+			}
+		}
+	}
+	return view
+}
+
+// fileView — текущий файл записи и поле пути к новому файлу
+func (m *AddDataModel) fileView() []string {
+	var view []string
+	if name := m.binaryFields[format.FieldFileName]; name != "" {
+		view = append(view, fmt.Sprintf("Текущий файл: %s (%s байт)", name, m.binaryFields[format.FieldFileSize]))
+	} else if len(m.binaryFields) > 0 {
+		view = append(view, "Текущие данные сохранены без файла")
+	}
+	view = append(view, focusedStyle.Render(m.binaryInput.View()))
+	view = append(view, "Ctrl+O — выбрать файл на диске")
+	if m.editing != nil {
+		view = append(view, "Пустой путь — оставить текущее содержимое")
+	}
+	return view
+}
+
 // addDataTypesToLinesSeq возвращает итератор строк списка типов (выбранный/обычный стиль)
 func addDataTypesToLinesSeq(types []string, typeSelect int, typeFocused bool) iter.Seq[string] {
 	return func(yield func(string) bool) {
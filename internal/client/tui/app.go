@@ -1,7 +1,13 @@
 package tui
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gophkeeper/gophkeeper/internal/client"
 )
 
 // AppModel представляет основную модель приложения
@@ -11,8 +17,8 @@ type AppModel struct {
 }
 
 // NewAppModel создаёт новую модель приложения
-func NewAppModel(serverAddress string) (*AppModel, error) {
-	model, err := NewModel(serverAddress)
+func NewAppModel(serverAddress, cacheDir string, syncInterval time.Duration, encryptMetadata bool, clipboardTimeout time.Duration) (*AppModel, error) {
+	model, err := NewModel(serverAddress, cacheDir, syncInterval, encryptMetadata, clipboardTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -37,25 +43,75 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 	case tea.WindowSizeMsg:
-		// Обработка изменения размера окна
+		// Размер нужен экранам для разбиения на страницы; сообщение передаётся и текущему экрану
+		m.width, m.height = msg.Width, msg.Height
+	case clipboardClearMsg:
+		m.handleClipboardClear(msg)
+		return m, nil
+	case syncTickMsg:
+		return m, m.handleSyncTick(msg)
+	case syncNowMsg:
+		return m, m.startSync()
+	case localDataMsg:
+		m.handleLocalData(msg)
+		return m, nil
+	case syncDoneMsg:
+		if msg.gen != m.sync.gen {
+			// Синхронизация предыдущего сеанса: её итог относится к другой учётной записи
+			return m, nil
+		}
+		if errors.Is(msg.err, client.ErrSessionChanged) {
+			return m, m.logout(fmt.Errorf("мастер-пароль изменён на другом устройстве, войдите заново"))
+		}
+		m.finishSync(msg)
+		// Экраны (список, синхронизация) тоже получают итог, чтобы обновить отображение
 	}
 
 	var cmd tea.Cmd
 	m.current, cmd = m.current.Update(msg)
 
 	// Проверяем, нужно ли переключить состояние
-	if m.state == StateLogin && m.client.IsAuthenticated() {
+	if m.state == StateLogin && m.replica != nil {
 		m.state = StateMainMenu
 		m.current = NewMainMenuModel(m.Model)
 		cmd = m.current.Init()
 	}
 
+	// После входа читаем локальную копию и запускаем фоновую синхронизацию
+	if m.replica != nil && !m.sync.started {
+		cmd = tea.Batch(cmd, m.startBackgroundSync())
+	}
+
 	return m, cmd
 }
 
+// logout закрывает локальную копию и возвращает на экран входа с сообщением err.
+// Close ждёт синхронизацию, которая ещё идёт: иначе она продолжила бы работать с клиентом
+// после входа под другой учётной записью.
+func (m *AppModel) logout(err error) tea.Cmd {
+	if m.replica != nil {
+		_ = m.replica.Close()
+		m.replica = nil
+	}
+	m.dataList = nil
+	m.currentData = nil
+	m.list = listState{}
+	m.badges = nil
+	m.clearClipboard()
+	m.sync.stop()
+	m.state = StateLogin
+	login := NewLoginModel(m.Model)
+	login.err = err
+	m.current = login
+	return login.Init()
+}
+
 func (m *AppModel) View() string {
 	if m.quit {
 		return ""
 	}
-	return m.current.View()
+	if m.replica == nil {
+		return m.current.View()
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, m.current.View(), m.statusBar())
 }
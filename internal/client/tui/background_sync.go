@@ -0,0 +1,219 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gophkeeper/gophkeeper/internal/client"
+	"github.com/gophkeeper/gophkeeper/internal/client/replica"
+	"github.com/gophkeeper/gophkeeper/proto"
+)
+
+var statusBarStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("241")).
+	PaddingLeft(2)
+
+// syncState — состояние фоновой синхронизации
+type syncState struct {
+	interval  time.Duration
+	started   bool
+	gen       int // поколение цикла: тики от завершённого сеанса игнорируются
+	running   bool
+	lastSync  time.Time
+	pending   int64
+	conflicts int64
+	offline   bool
+	err       error // последняя ошибка синхронизации, не связанная с отсутствием связи
+}
+
+// stop завершает цикл фоновой синхронизации текущего сеанса
+func (s *syncState) stop() {
+	s.started = false
+	s.running = false
+	s.gen++
+}
+
+// syncTickMsg — пора выполнить плановую синхронизацию
+type syncTickMsg struct {
+	gen int
+}
+
+// syncNowMsg — экран запросил синхронизацию немедленно
+type syncNowMsg struct{}
+
+// syncDoneMsg — синхронизация завершена
+type syncDoneMsg struct {
+	gen    int
+	report *replica.SyncReport
+	err    error
+}
+
+// localDataMsg — записи прочитаны из локальной копии (или краткие описания с сервера, см. Replica.InitialList)
+type localDataMsg struct {
+	gen  int
+	data []*proto.Data
+	err  error
+}
+
+// requestSync запрашивает синхронизацию у AppModel (если она уже идёт, новая не запускается)
+func requestSync() tea.Msg {
+	return syncNowMsg{}
+}
+
+// startBackgroundSync читает локальную копию, сразу синхронизирует её и планирует следующие синхронизации.
+// Синхронизация начинается после чтения: на новом устройстве список — это краткие описания с сервера,
+// и они не должны ждать первой синхронизации, загружающей все записи целиком.
+func (m *AppModel) startBackgroundSync() tea.Cmd {
+	m.sync.started = true
+	m.sync.gen++
+	m.refreshSyncStatus()
+	return tea.Batch(tea.Sequence(m.loadLocalData(), m.startSync()), m.scheduleSync())
+}
+
+// loadLocalData читает записи из локальной копии
+func (m *AppModel) loadLocalData() tea.Cmd {
+	r, gen := m.replica, m.sync.gen
+	return func() tea.Msg {
+		data, err := r.InitialList()
+		return localDataMsg{gen: gen, data: data, err: err}
+	}
+}
+
+func (m *AppModel) handleLocalData(msg localDataMsg) {
+	if msg.gen != m.sync.gen {
+		// Записи прочитаны до выхода из сеанса
+		return
+	}
+	if msg.err != nil {
+		m.err = msg.err
+		return
+	}
+	if msg.data == nil {
+		msg.data = []*proto.Data{}
+	}
+	// Если синхронизация успела что-то добавить, её изменения накладываются поверх прочитанного
+	m.dataList = replica.Merge(msg.data, m.dataList, nil)
+}
+
+// scheduleSync планирует следующую плановую синхронизацию
+func (m *AppModel) scheduleSync() tea.Cmd {
+	if m.sync.interval <= 0 {
+		return nil
+	}
+	gen := m.sync.gen
+	return tea.Tick(m.sync.interval, func(time.Time) tea.Msg {
+		return syncTickMsg{gen: gen}
+	})
+}
+
+func (m *AppModel) handleSyncTick(msg syncTickMsg) tea.Cmd {
+	if msg.gen != m.sync.gen || m.replica == nil {
+		return nil
+	}
+	return tea.Batch(m.startSync(), m.scheduleSync())
+}
+
+// startSync запускает синхронизацию в фоне, если она ещё не идёт
+func (m *AppModel) startSync() tea.Cmd {
+	if m.sync.running || m.replica == nil {
+		return nil
+	}
+	m.sync.running = true
+	r, gen := m.replica, m.sync.gen
+	return func() tea.Msg {
+		report, err := r.Sync()
+		return syncDoneMsg{gen: gen, report: report, err: err}
+	}
+}
+
+// finishSync применяет итог синхронизации к списку записей и строке состояния
+func (m *AppModel) finishSync(msg syncDoneMsg) {
+	m.sync.running = false
+	m.sync.err = nil
+	if msg.err != nil && !client.IsUnavailable(msg.err) {
+		m.sync.err = msg.err
+	}
+	if msg.err == nil {
+		m.dataList = replica.Merge(m.dataList, msg.report.Changed, msg.report.Removed)
+		// После синхронизации все записи есть в локальной копии; краткие описания, которые не заменила
+		// ни одна запись, остались от удалённых на сервере
+		m.dataList = slices.DeleteFunc(m.dataList, replica.Partial)
+		if m.currentData != nil {
+			for _, d := range msg.report.Changed {
+				if d.Id == m.currentData.Id {
+					m.currentData = d
+				}
+			}
+		}
+	}
+	m.refreshSyncStatus()
+}
+
+// refreshSyncStatus перечитывает время синхронизации и размер очереди из локальной копии
+func (m *Model) refreshSyncStatus() {
+	if m.replica == nil {
+		return
+	}
+	if last, err := m.replica.LastSync(); err == nil {
+		m.sync.lastSync = last
+	}
+	if pending, err := m.replica.PendingCount(); err == nil {
+		m.sync.pending = pending
+	}
+	if conflicts, err := m.replica.ConflictCount(); err == nil {
+		m.sync.conflicts = conflicts
+	}
+	m.sync.offline = m.replica.Offline()
+}
+
+// applyLocalChange отражает в списке запись, сохранённую или удалённую на этом устройстве
+func (m *Model) applyLocalChange(changed *proto.Data, removedID string) {
+	var changedList []*proto.Data
+	var removed []string
+	if changed != nil {
+		changedList = []*proto.Data{changed}
+	}
+	if removedID != "" {
+		removed = []string{removedID}
+	}
+	m.dataList = replica.Merge(m.dataList, changedList, removed)
+	m.refreshSyncStatus()
+}
+
+// statusBar возвращает строку состояния: время последней синхронизации, связь и очередь изменений
+func (m *Model) statusBar() string {
+	var parts []string
+	switch {
+	case m.sync.running:
+		parts = append(parts, "⟳ Синхронизация…")
+	case m.sync.lastSync.IsZero():
+		parts = append(parts, "Ещё не синхронизировано")
+	default:
+		parts = append(parts, "✓ Синхронизировано: "+formatSyncTime(m.sync.lastSync, time.Now()))
+	}
+	if m.sync.offline {
+		parts = append(parts, "нет связи с сервером")
+	}
+	if m.sync.pending > 0 {
+		parts = append(parts, fmt.Sprintf("не отправлено: %d", m.sync.pending))
+	}
+	if m.sync.conflicts > 0 {
+		parts = append(parts, fmt.Sprintf("⚠ конфликтов: %d (разрешить — на экране синхронизации)", m.sync.conflicts))
+	}
+	if m.sync.err != nil {
+		parts = append(parts, fmt.Sprintf("ошибка: %v", m.sync.err))
+	}
+	return statusBarStyle.Render(strings.Join(parts, " · "))
+}
+
+// formatSyncTime показывает только время для сегодняшней синхронизации и дату со временем для более ранней
+func formatSyncTime(t, now time.Time) string {
+	if t.Year() == now.Year() && t.YearDay() == now.YearDay() {
+		return t.Format("15:04:05")
+	}
+	return t.Format("02.01.2006 15:04")
+}
@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Поля формы смены пароля
+const (
+	cpFieldOldPassword = iota
+	cpFieldNewPassword
+	cpFieldNewMaster
+	cpFieldRepeatMaster
+	cpFieldCount
+)
+
+// passwordChangedMsg — пароль успешно изменён
+type passwordChangedMsg struct{}
+
+// ChangePasswordModel представляет модель смены пароля входа и мастер-пароля
+type ChangePasswordModel struct {
+	model   *Model
+	inputs  []textinput.Model
+	focused int
+	loading bool
+	err     error
+	message string
+}
+
+func NewChangePasswordModel(m *Model) *ChangePasswordModel {
+	placeholders := []string{
+		"Текущий пароль входа",
+		"Новый пароль входа (пусто — не менять)",
+		"Новый мастер-пароль (пусто — не менять)",
+		"Повторите мастер-пароль",
+	}
+
+	inputs := make([]textinput.Model, cpFieldCount)
+	for i := range inputs {
+		inputs[i] = textinput.New()
+		inputs[i].Placeholder = placeholders[i]
+		inputs[i].EchoMode = textinput.EchoPassword
+		inputs[i].EchoCharacter = '•'
+		inputs[i].CharLimit = 100
+		inputs[i].Width = 38
+	}
+	inputs[cpFieldOldPassword].Focus()
+
+	return &ChangePasswordModel{
+		model:  m,
+		inputs: inputs,
+	}
+}
+
+func (m *ChangePasswordModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m *ChangePasswordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case passwordChangedMsg:
+		m.loading = false
+		m.message = "Пароль изменён. Прежние сессии на других устройствах завершатся."
+		for i := range m.inputs {
+			m.inputs[i].SetValue("")
+		}
+		return m, nil
+	case error:
+		m.loading = false
+		m.err = msg
+		return m, nil
+	case tea.KeyMsg:
+		if m.loading {
+			return m, nil
+		}
+		switch msg.String() {
+		case "esc":
+			m.model.state = StateMainMenu
+			return NewMainMenuModel(m.model), nil
+		case "enter":
+			return m.handleSubmit()
+		case "tab", "down":
+			m.setFocus((m.focused + 1) % cpFieldCount)
+			return m, nil
+		case "shift+tab", "up":
+			m.setFocus((m.focused + cpFieldCount - 1) % cpFieldCount)
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.inputs[m.focused], cmd = m.inputs[m.focused].Update(msg)
+	return m, cmd
+}
+
+func (m *ChangePasswordModel) setFocus(i int) {
+	m.inputs[m.focused].Blur()
+	m.focused = i
+	m.inputs[m.focused].Focus()
+}
+
+func (m *ChangePasswordModel) handleSubmit() (tea.Model, tea.Cmd) {
+	oldPassword := m.inputs[cpFieldOldPassword].Value()
+	newPassword := m.inputs[cpFieldNewPassword].Value()
+	newMaster := m.inputs[cpFieldNewMaster].Value()
+
+	m.err = nil
+	m.message = ""
+	if oldPassword == "" {
+		m.err = fmt.Errorf("текущий пароль обязателен")
+		return m, nil
+	}
+	if newPassword == "" && newMaster == "" {
+		m.err = fmt.Errorf("укажите новый пароль входа или новый мастер-пароль")
+		return m, nil
+	}
+	if newMaster != m.inputs[cpFieldRepeatMaster].Value() {
+		m.err = fmt.Errorf("мастер-пароли не совпадают")
+		return m, nil
+	}
+
+	m.loading = true
+	c, r := m.model.client, m.model.replica
+	return m, func() tea.Msg {
+		if err := r.Do(func() error { return c.ChangePassword(oldPassword, newPassword, newMaster) }); err != nil {
+			return err
+		}
+		if newPassword != "" {
+			r.SetPassword(newPassword)
+		}
+		// Вход без связи с сервером должен принимать уже новый мастер-пароль
+		if err := r.RememberKeyCheck(); err != nil {
+			return err
+		}
+		return passwordChangedMsg{}
+	}
+}
+
+func (m *ChangePasswordModel) View() string {
+	view := []string{titleStyle.Render("Смена пароля"), ""}
+	for i := range m.inputs {
+		style := inputStyle
+		if i == m.focused {
+			style = focusedStyle
+		}
+		view = append(view, style.Render(m.inputs[i].View()))
+	}
+
+	switch {
+	case m.loading:
+		view = append(view, "", "Перешифрование и сохранение...")
+	case m.err != nil:
+		view = append(view, errorStyle.Render(fmt.Sprintf("Ошибка: %v", m.err)))
+	case m.message != "":
+		view = append(view, successStyle.Render(m.message))
+	}
+
+	view = append(view, "", "Tab — поля, Enter — сохранить, Esc — назад")
+	return menuStyle.Render(lipgloss.JoinVertical(lipgloss.Left, view...))
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/gophkeeper/gophkeeper/internal/client/clipboard"
+)
+
+// clipState — секрет, скопированный в буфер обмена и ожидающий очистки
+type clipState struct {
+	board   *clipboard.Clipboard
+	timeout time.Duration
+	gen     int // поколение копирования: очистка от предыдущего копирования игнорируется
+	secret  string
+}
+
+// clipboardClearMsg — истёк срок хранения секрета в буфере обмена
+type clipboardClearMsg struct {
+	gen int
+}
+
+// copySecret копирует значение поля label в буфер обмена и планирует его очистку
+func (m *Model) copySecret(label, value string) tea.Cmd {
+	if value == "" {
+		m.err = fmt.Errorf("поле «%s» пустое", label)
+		return nil
+	}
+	method, err := m.clip.board.Copy(value)
+	if err != nil {
+		m.err = fmt.Errorf("не удалось скопировать: %w", err)
+		return nil
+	}
+	m.clip.gen++
+	m.clip.secret = value
+
+	m.message = fmt.Sprintf("%s скопирован", label)
+	if method == clipboard.MethodOSC52 {
+		m.message += " в буфер терминала"
+	}
+	if m.clip.timeout <= 0 {
+		return nil
+	}
+	m.message += fmt.Sprintf(", буфер очистится через %s", m.clip.timeout)
+	gen := m.clip.gen
+	return tea.Tick(m.clip.timeout, func(time.Time) tea.Msg {
+		return clipboardClearMsg{gen: gen}
+	})
+}
+
+// handleClipboardClear очищает буфер, если после запланированной очистки ничего не копировалось
+func (m *Model) handleClipboardClear(msg clipboardClearMsg) {
+	if msg.gen != m.clip.gen || m.clip.secret == "" {
+		return
+	}
+	m.clearClipboard()
+	if m.state == StateViewData && m.err == nil {
+		m.message = "Буфер обмена очищен"
+	}
+}
+
+// clearClipboard убирает из буфера обмена скопированный секрет, если он там ещё есть
+func (m *Model) clearClipboard() {
+	if m.clip.secret == "" {
+		return
+	}
+	if err := m.clip.board.Clear(m.clip.secret); err != nil {
+		m.err = fmt.Errorf("не удалось очистить буфер обмена: %w", err)
+	}
+	m.clip.secret = ""
+	m.clip.gen++
+}
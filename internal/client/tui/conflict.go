@@ -0,0 +1,440 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gophkeeper/gophkeeper/internal/client/format"
+	"github.com/gophkeeper/gophkeeper/internal/client/replica"
+	"github.com/gophkeeper/gophkeeper/proto"
+	pb "google.golang.org/protobuf/proto"
+)
+
+const conflictColumnWidth = 32
+
+var (
+	conflictChosenStyle = lipgloss.NewStyle().
+				Width(conflictColumnWidth).
+				Foreground(lipgloss.Color("205")).
+				Bold(true)
+
+	conflictOtherStyle = lipgloss.NewStyle().
+				Width(conflictColumnWidth).
+				Foreground(lipgloss.Color("241"))
+
+	conflictLabelStyle = lipgloss.NewStyle().
+				Width(15)
+
+	conflictCursorStyle = conflictLabelStyle.Copy().
+				Foreground(lipgloss.Color("205")).
+				Bold(true)
+)
+
+// conflictSide — чья версия поля берётся в итоговую запись
+type conflictSide int
+
+const (
+	sideMine conflictSide = iota
+	sideTheirs
+)
+
+// conflictField — поле записи в локальной версии и версии сервера
+type conflictField struct {
+	key    string // ключ поля содержимого (format.Field*) или conflictFieldName/conflictFieldMetadata
+	label  string
+	mine   string
+	theirs string
+	// both — поле изменено на обоих устройствах относительно исходной версии
+	both   bool
+	choice conflictSide
+}
+
+// Особые строки сравнения, не входящие в содержимое записи
+const (
+	conflictFieldName     = "#name"
+	conflictFieldMetadata = "#metadata"
+)
+
+// ConflictModel — экран разрешения конфликта версий: локальная версия и версия сервера
+// сравниваются по полям; можно оставить свою, принять с сервера или собрать запись из полей обеих
+type ConflictModel struct {
+	model     *Model
+	conflicts []*replica.Conflict
+	fields    []conflictField
+	mergeable bool
+	cursor    int
+	resolved  int
+	err       error
+}
+
+// NewConflictModel открывает экран для конфликтов по очереди, начиная с первого
+func NewConflictModel(m *Model, conflicts []*replica.Conflict) *ConflictModel {
+	c := &ConflictModel{model: m, conflicts: conflicts}
+	c.prepare()
+	return c
+}
+
+// openConflicts читает неразрешённые конфликты и переходит к их разрешению.
+// Если конфликтов нет, возвращает nil.
+func openConflicts(m *Model) (tea.Model, error) {
+	conflicts, err := m.replica.Conflicts()
+	if err != nil || len(conflicts) == 0 {
+		return nil, err
+	}
+	m.state = StateConflict
+	return NewConflictModel(m, conflicts), nil
+}
+
+func (m *ConflictModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *ConflictModel) current() *replica.Conflict {
+	return m.conflicts[0]
+}
+
+// prepare раскладывает текущий конфликт по полям и выбирает версию каждого поля по исходной:
+// берётся та сторона, где поле менялось; если оно изменено на обеих, по умолчанию остаётся своё
+func (m *ConflictModel) prepare() {
+	m.cursor = 0
+	m.fields = nil
+	c := m.current()
+
+	mine, errMine := m.contentFields(c.Mine)
+	theirs, errTheirs := m.contentFields(c.Theirs)
+	// Прикреплённый файл нельзя собрать по частям из двух версий — выбирается запись целиком
+	m.mergeable = c.Mine != nil && c.Theirs != nil && c.Mine.Type == c.Theirs.Type && errMine == nil && errTheirs == nil &&
+		c.Mine.BlobId == "" && c.Theirs.BlobId == ""
+	if !m.mergeable {
+		return
+	}
+	var base map[string]string
+	if c.Base != nil && c.Base.Type == c.Mine.Type {
+		base, _ = m.contentFields(c.Base)
+	}
+
+	add := func(key, label, mineValue, theirsValue string, baseValue *string) {
+		f := conflictField{key: key, label: label, mine: mineValue, theirs: theirsValue}
+		switch {
+		case mineValue == theirsValue:
+		case baseValue != nil && mineValue == *baseValue:
+			f.choice = sideTheirs
+		case baseValue != nil && theirsValue == *baseValue:
+		default:
+			f.both = true
+		}
+		m.fields = append(m.fields, f)
+	}
+
+	var baseName, baseMetadata *string
+	if c.Base != nil {
+		name, metadata := c.Base.Name, metadataString(c.Base.Metadata)
+		baseName, baseMetadata = &name, &metadata
+	}
+	add(conflictFieldName, "Название", c.Mine.Name, c.Theirs.Name, baseName)
+	for _, key := range format.PayloadFields(c.Mine.Type) {
+		var baseValue *string
+		if v, ok := base[key]; ok {
+			baseValue = &v
+		}
+		add(key, format.FieldLabel(key), mine[key], theirs[key], baseValue)
+	}
+	add(conflictFieldMetadata, "Метаданные", metadataString(c.Mine.Metadata), metadataString(c.Theirs.Metadata), baseMetadata)
+}
+
+// contentFields расшифровывает содержимое записи и раскладывает его на поля
+func (m *ConflictModel) contentFields(data *proto.Data) (map[string]string, error) {
+	if data == nil {
+		return nil, nil
+	}
+	payload, err := m.model.client.Vault().Decrypt(data.EncryptedData)
+	if err != nil {
+		return nil, err
+	}
+	return format.ParsePayload(data.Type, payload)
+}
+
+func (m *ConflictModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.err = nil
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.fields)-1 {
+				m.cursor++
+			}
+		case "left", "h":
+			if m.mergeable {
+				m.fields[m.cursor].choice = sideMine
+			}
+		case "right", "l":
+			if m.mergeable {
+				m.fields[m.cursor].choice = sideTheirs
+			}
+		case "m":
+			if m.current().Mine == nil {
+				return m.keepDeleted()
+			}
+			return m.resolve(pb.Clone(m.current().Mine).(*proto.Data))
+		case "t":
+			return m.keepTheirs()
+		case "enter":
+			if !m.mergeable {
+				break
+			}
+			merged, err := m.merge()
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			return m.resolve(merged)
+		case "s":
+			// Конфликт остаётся в очереди до следующего раза
+			return m.next()
+		case "esc", "q":
+			return m.finish()
+		}
+	}
+	return m, nil
+}
+
+// merge собирает итоговую запись из выбранных версий полей
+func (m *ConflictModel) merge() (*proto.Data, error) {
+	c := m.current()
+	merged := pb.Clone(c.Mine).(*proto.Data)
+	values := make(map[string]string)
+	for _, f := range m.fields {
+		source := c.Mine
+		value := f.mine
+		if f.choice == sideTheirs {
+			source, value = c.Theirs, f.theirs
+		}
+		switch f.key {
+		case conflictFieldName:
+			merged.Name = value
+		case conflictFieldMetadata:
+			merged.Metadata = source.Metadata
+		default:
+			values[f.key] = value
+		}
+	}
+
+	payload, err := format.BuildPayload(merged.Type, values)
+	if err != nil {
+		return nil, err
+	}
+	if merged.EncryptedData, err = m.model.client.Vault().Encrypt(payload); err != nil {
+		return nil, err
+	}
+	merged.UpdatedAt = time.Now().Unix()
+	return merged, nil
+}
+
+// resolve сохраняет итоговую запись поверх версии сервера
+func (m *ConflictModel) resolve(resolved *proto.Data) (tea.Model, tea.Cmd) {
+	id := m.current().ID
+	queued, err := m.model.replica.Resolve(id, resolved)
+	var conflict *replica.ConflictError
+	if errors.As(err, &conflict) {
+		// Пока конфликт разрешался, запись изменили ещё раз: сравниваем с новой версией
+		m.conflicts[0] = conflict.Conflict
+		m.prepare()
+		m.err = errors.New("запись снова изменена на другом устройстве, сравните с новой версией")
+		return m, nil
+	}
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	removedID := ""
+	if resolved.Id != id {
+		removedID = id
+	}
+	m.model.applyLocalChange(resolved, removedID)
+	if queued {
+		m.model.message = "Решение сохранено локально и будет отправлено при синхронизации"
+	}
+	m.resolved++
+	return m.next()
+}
+
+// keepTheirs отбрасывает локальную правку и принимает версию сервера
+func (m *ConflictModel) keepTheirs() (tea.Model, tea.Cmd) {
+	id := m.current().ID
+	theirs, err := m.model.replica.KeepTheirs(id)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	if theirs == nil {
+		m.model.applyLocalChange(nil, id)
+	} else {
+		m.model.applyLocalChange(theirs, "")
+	}
+	m.resolved++
+	return m.next()
+}
+
+// keepDeleted оставляет запись удалённой, как на этом устройстве: удаление отправляется на сервер
+func (m *ConflictModel) keepDeleted() (tea.Model, tea.Cmd) {
+	c := m.current()
+	queued, err := m.model.replica.Delete(&proto.Data{Id: c.ID, Version: c.Theirs.GetVersion()})
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.model.applyLocalChange(nil, c.ID)
+	if queued {
+		m.model.message = "Удаление будет отправлено при синхронизации"
+	}
+	m.resolved++
+	return m.next()
+}
+
+// next переходит к следующему конфликту или возвращает к списку, если конфликты закончились
+func (m *ConflictModel) next() (tea.Model, tea.Cmd) {
+	m.conflicts = m.conflicts[1:]
+	if len(m.conflicts) == 0 {
+		return m.finish()
+	}
+	m.prepare()
+	return m, nil
+}
+
+func (m *ConflictModel) finish() (tea.Model, tea.Cmd) {
+	if m.resolved > 0 && m.model.message == "" {
+		m.model.message = fmt.Sprintf("Разрешено конфликтов: %d", m.resolved)
+	}
+	m.model.refreshSyncStatus()
+	m.model.state = StateListData
+	listModel := NewListDataModel(m.model)
+	return listModel, listModel.Init()
+}
+
+func (m *ConflictModel) View() string {
+	c := m.current()
+	var view []string
+	view = append(view, titleStyle.Render(fmt.Sprintf("Конфликт версий: %s", conflictName(c))))
+	if len(m.conflicts) > 1 {
+		view = append(view, fmt.Sprintf("Ещё конфликтов: %d", len(m.conflicts)-1))
+	}
+	if c.Mine == nil {
+		view = append(view, "Запись удалена на этом устройстве и изменена на другом.", "")
+	} else {
+		view = append(view, "Запись изменена и на этом устройстве, и на другом.", "")
+	}
+
+	mineTitle, theirsTitle := "Моя версия", "На сервере"
+	if c.Mine == nil {
+		mineTitle = "Моя версия (удалена локально)"
+	}
+	if c.Theirs == nil {
+		theirsTitle = "На сервере (удалена)"
+	}
+	view = append(view, lipgloss.JoinHorizontal(lipgloss.Top,
+		conflictLabelStyle.Render(""),
+		conflictOtherStyle.Render(mineTitle),
+		conflictOtherStyle.Render(theirsTitle)))
+
+	if m.mergeable {
+		for i, f := range m.fields {
+			view = append(view, m.fieldRow(i, f))
+		}
+	} else {
+		// Поля сравнить нельзя (разные типы или запись удалена): показываем версии целиком
+		view = append(view, lipgloss.JoinHorizontal(lipgloss.Top,
+			conflictLabelStyle.Render(""),
+			conflictOtherStyle.Render(m.wholeRecord(c.Mine)),
+			conflictOtherStyle.Render(m.wholeRecord(c.Theirs))))
+	}
+
+	if m.err != nil {
+		view = append(view, errorStyle.Render(fmt.Sprintf("Ошибка: %v", m.err)))
+	}
+
+	view = append(view, "")
+	if m.mergeable {
+		view = append(view, "↑↓ — поле, ←→ — выбрать версию поля, Enter — сохранить выбранное")
+		view = append(view, "⚠ — поле изменено на обоих устройствах")
+	}
+	switch {
+	case c.Mine == nil:
+		view = append(view, "m — удалить запись, t — восстановить версию с сервера")
+	case c.Theirs == nil:
+		view = append(view, "m — сохранить мою версию как новую запись, t — согласиться с удалением")
+	default:
+		view = append(view, "m — оставить мою версию, t — принять версию с сервера")
+	}
+	view = append(view, "s — отложить, Esc — вернуться к списку")
+
+	return menuStyle.Render(lipgloss.JoinVertical(lipgloss.Left, view...))
+}
+
+func (m *ConflictModel) fieldRow(i int, f conflictField) string {
+	cursor, marker := " ", "  "
+	if f.both {
+		marker = "⚠ "
+	}
+	style := conflictLabelStyle
+	if i == m.cursor {
+		cursor, style = "▶", conflictCursorStyle
+	}
+	label := style.Render(cursor + marker + f.label)
+
+	mineStyle, theirsStyle := conflictChosenStyle, conflictOtherStyle
+	if f.choice == sideTheirs {
+		mineStyle, theirsStyle = conflictOtherStyle, conflictChosenStyle
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		label,
+		mineStyle.Render(orDash(f.mine)),
+		theirsStyle.Render(orDash(f.theirs)))
+}
+
+// conflictName — название записи для заголовка: локальная версия может быть удалена
+func conflictName(c *replica.Conflict) string {
+	for _, data := range []*proto.Data{c.Mine, c.Theirs, c.Base} {
+		if data != nil {
+			return data.Name
+		}
+	}
+	return c.ID
+}
+
+// wholeRecord показывает запись целиком: название, тип и расшифрованное содержимое
+func (m *ConflictModel) wholeRecord(data *proto.Data) string {
+	if data == nil {
+		return "—"
+	}
+	lines := []string{data.Name, format.DataTypeDisplayName(data.Type)}
+	if content := format.DataContentToDisplayString(data, m.model.client.Vault(), true); content != "" {
+		lines = append(lines, content)
+	}
+	lines = append(lines, format.MetadataToDisplayLines(data.Metadata)...)
+	return strings.Join(lines, "\n")
+}
+
+// metadataString показывает метаданные одной строкой для сравнения версий
+func metadataString(metadata []*proto.Metadata) string {
+	lines := format.MetadataToDisplayLines(metadata)
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.Join(lines, ", ")
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "—"
+	}
+	return s
+}
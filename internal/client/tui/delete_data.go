@@ -26,9 +26,16 @@ func (m *DeleteDataModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "y", "Y":
 			if m.model.currentData != nil {
-				if err := m.model.client.DeleteData(m.model.currentData.Id); err != nil {
+				queued, err := m.model.replica.Delete(m.model.currentData)
+				if err == nil {
+					m.model.applyLocalChange(nil, m.model.currentData.Id)
+				}
+				switch {
+				case err != nil:
 					m.model.err = err
-				} else {
+				case queued:
+					m.model.message = "Данные удалены локально, удаление будет отправлено при синхронизации"
+				default:
 					m.model.message = "Данные успешно удалены"
 				}
 			}
@@ -37,7 +44,8 @@ func (m *DeleteDataModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return listModel, listModel.Init()
 		case "n", "N", "esc", "q":
 			m.model.state = StateViewData
-			return NewViewDataModel(m.model), nil
+			viewModel := NewViewDataModel(m.model)
+			return viewModel, viewModel.Init()
 		}
 	}
 	return m, nil
@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gophkeeper/gophkeeper/internal/client/format"
+	"github.com/gophkeeper/gophkeeper/proto"
+	pb "google.golang.org/protobuf/proto"
+)
+
+// NewEditDataModel открывает форму добавления, заполненную полями записи m.currentData.
+// Запись сохраняется с тем же ID и прочитанной версией как базовой: сервер увеличит версию,
+// а если запись успели изменить на другом устройстве, откроется экран конфликта.
+func NewEditDataModel(m *Model) (*AddDataModel, error) {
+	data := m.currentData
+	typeIndex := format.DataTypeIndex(data.Type)
+	if typeIndex < 0 {
+		return nil, fmt.Errorf("тип записи %s не поддерживает редактирование", format.DataTypeDisplayName(data.Type))
+	}
+
+	payload, err := m.client.Vault().Decrypt(data.EncryptedData)
+	if err != nil {
+		return nil, fmt.Errorf("расшифровка записи: %w", err)
+	}
+	fields, err := format.ParsePayload(data.Type, payload)
+	if err != nil {
+		return nil, fmt.Errorf("декодирование записи: %w", err)
+	}
+
+	edit := NewAddDataModel(m)
+	edit.editing = data
+	edit.typeSelect = typeIndex
+	edit.blobID = data.BlobId
+	edit.nameInput.SetValue(data.Name)
+	edit.setFieldValues(fields)
+	edit.metadata = newMetadataEditor(data.Metadata)
+	return edit, nil
+}
+
+// setFieldValues заполняет поля ввода значениями полей содержимого
+func (m *AddDataModel) setFieldValues(fields map[string]string) {
+	if m.hasFile() {
+		m.binaryFields = fields
+		return
+	}
+	m.ensureInputs()
+	for i := range m.inputs {
+		m.inputs[i].SetValue(fields[m.inputs[i].field.Key])
+	}
+}
+
+// editedData возвращает изменяемую запись с новыми названием и содержимым; ID и версия сохраняются
+func (m *AddDataModel) editedData(name string, encryptedPayload []byte) *proto.Data {
+	data := pb.Clone(m.editing).(*proto.Data)
+	data.Name = name
+	data.EncryptedData = encryptedPayload
+	data.UpdatedAt = time.Now().Unix()
+	return data
+}
@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gophkeeper/gophkeeper/internal/client/card"
+	"github.com/gophkeeper/gophkeeper/internal/client/format"
+	"github.com/gophkeeper/gophkeeper/internal/client/generator"
+	"github.com/gophkeeper/gophkeeper/internal/client/otp"
+	"github.com/gophkeeper/gophkeeper/internal/client/sshkey"
+)
+
+// errorText возвращает текст ошибки для показа. Пакеты проверки данных возвращают ошибки на английском
+// (их же печатают команды без TUI); известная ошибка в цепочке заменяется переводом, а пояснения
+// вокруг неё (название поля, недопустимый символ) остаются как есть.
+func errorText(err error) string {
+	msg := err.Error()
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if text, ok := translateError(e); ok {
+			return strings.Replace(msg, e.Error(), text, 1)
+		}
+	}
+	return msg
+}
+
+// translateError переводит ошибку, если это одна из известных ошибок (не проверяя её цепочку)
+func translateError(err error) (string, bool) {
+	switch err {
+	case card.ErrNoNumber:
+		return "не указан номер карты", true
+	case card.ErrNumberChar:
+		return "номер содержит недопустимый символ", true
+	case card.ErrLuhn:
+		return "неверная контрольная цифра номера", true
+	case card.ErrCVVDigits:
+		return "CVV состоит из цифр", true
+	case card.ErrExpiryFormat:
+		return "срок действия в формате MM/YY", true
+	case generator.ErrNoCharClasses:
+		return "выберите хотя бы один класс символов", true
+	case generator.ErrLength:
+		return fmt.Sprintf("длина пароля должна быть от %d до %d", generator.MinLength, generator.MaxLength), true
+	case generator.ErrWords:
+		return fmt.Sprintf("число слов должно быть от %d до %d", generator.MinWords, generator.MaxWords), true
+	case otp.ErrEmptySecret:
+		return "не указан секрет", true
+	case otp.ErrSecretEncoding:
+		return "секрет не в формате Base32", true
+	case otp.ErrInvalidURI:
+		return "неверный URI", true
+	case otp.ErrUnsupportedType:
+		return "тип ключа не поддерживается, нужен totp", true
+	case otp.ErrUnsupportedAlgorithm:
+		return "алгоритм не поддерживается", true
+	case otp.ErrDigits:
+		return "число цифр должно быть от 6 до 8", true
+	case otp.ErrPeriod:
+		return "неверный период", true
+	case sshkey.ErrPassphraseRequired:
+		return "ключ зашифрован, укажите парольную фразу", true
+	case sshkey.ErrWrongPassphrase:
+		return "неверная парольная фраза", true
+	case sshkey.ErrKeyMismatch:
+		return "публичный ключ не соответствует закрытому", true
+	case sshkey.ErrInvalidPrivateKey:
+		return "неверный закрытый ключ", true
+	case sshkey.ErrInvalidPublicKey:
+		return "неверный публичный ключ", true
+	case sshkey.ErrUnsupportedKey:
+		return "неподдерживаемый ключ", true
+	}
+	switch e := err.(type) {
+	case *card.NumberLengthError:
+		if e.Brand == card.BrandUnknown {
+			return fmt.Sprintf("номер карты из %d–%d цифр, а не %d", card.MinNumberLength, card.MaxNumberLength, e.Got), true
+		}
+		lengths := make([]string, len(e.Lengths))
+		for i, n := range e.Lengths {
+			lengths[i] = fmt.Sprint(n)
+		}
+		return fmt.Sprintf("у карты %s номер из %s цифр, а не %d", format.CardBrandName(e.Brand), strings.Join(lengths, ", "), e.Got), true
+	case *card.CVVLengthError:
+		if e.Brand == card.BrandUnknown {
+			return "CVV из 3 или 4 цифр", true
+		}
+		return fmt.Sprintf("у карты %s CVV из %d цифр", format.CardBrandName(e.Brand), e.Length), true
+	}
+	return "", false
+}
@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/gophkeeper/gophkeeper/internal/client/format"
+)
+
+const (
+	defaultFieldCharLimit = 2000
+	textareaHeight        = 6
+)
+
+// fieldInput — поле ввода формы записи по описанию поля типа: однострочное (textinput)
+// или многострочное (textarea)
+type fieldInput struct {
+	field format.Field
+	text  textinput.Model
+	area  textarea.Model
+}
+
+func newFieldInput(f format.Field) fieldInput {
+	placeholder := f.Placeholder
+	if placeholder == "" {
+		placeholder = f.Label
+	}
+	limit := f.CharLimit
+	if limit == 0 {
+		limit = defaultFieldCharLimit
+	}
+
+	in := fieldInput{field: f}
+	if f.Multiline {
+		in.area = textarea.New()
+		in.area.Placeholder = placeholder
+		in.area.CharLimit = limit
+		in.area.ShowLineNumbers = false
+		in.area.SetWidth(38)
+		in.area.SetHeight(textareaHeight)
+		return in
+	}
+	in.text = newTextInput(placeholder)
+	in.text.CharLimit = limit
+	if f.Secret {
+		in.text.EchoMode = textinput.EchoPassword
+		in.text.EchoCharacter = '•'
+	}
+	return in
+}
+
+func (in *fieldInput) Value() string {
+	if in.field.Multiline {
+		return in.area.Value()
+	}
+	return in.text.Value()
+}
+
+func (in *fieldInput) SetValue(s string) {
+	if in.field.Multiline {
+		in.area.SetValue(s)
+		return
+	}
+	in.text.SetValue(s)
+	in.text.CursorEnd()
+}
+
+func (in *fieldInput) Focus() tea.Cmd {
+	if in.field.Multiline {
+		return in.area.Focus()
+	}
+	return in.text.Focus()
+}
+
+func (in *fieldInput) Blur() {
+	in.area.Blur()
+	in.text.Blur()
+}
+
+func (in *fieldInput) Update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	if in.field.Multiline {
+		in.area, cmd = in.area.Update(msg)
+	} else {
+		in.text, cmd = in.text.Update(msg)
+	}
+	return cmd
+}
+
+func (in *fieldInput) View(focused bool) string {
+	style := inputStyle
+	if focused {
+		style = focusedStyle
+	}
+	if in.field.Multiline {
+		return style.Render(in.area.View())
+	}
+	return style.Render(in.text.View())
+}
@@ -0,0 +1,23 @@
+package tui
+
+import (
+	"os"
+
+	"github.com/charmbracelet/bubbles/filepicker"
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// newFilePicker создаёт выбор файла для записи BINARY, начиная с текущего каталога
+func newFilePicker() filepicker.Model {
+	fp := filepicker.New()
+	if dir, err := os.Getwd(); err == nil {
+		fp.CurrentDirectory = dir
+	}
+	// Ключи и сертификаты часто лежат в скрытых каталогах (~/.ssh, ~/.gnupg)
+	fp.ShowHidden = true
+	fp.AutoHeight = false
+	fp.Height = 15
+	// Esc закрывает выбор файла, а не поднимается на каталог выше
+	fp.KeyMap.Back = key.NewBinding(key.WithKeys("h", "backspace", "left"))
+	return fp
+}
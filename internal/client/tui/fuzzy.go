@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/gophkeeper/gophkeeper/proto"
+)
+
+// Веса нечёткого поиска
+const (
+	fuzzyMatchScore    = 1
+	fuzzyConsecutive   = 5
+	fuzzyWordStart     = 8
+	fuzzyFirstRune     = 10
+	fuzzySubstring     = 20
+	fuzzyGapPenalty    = 1
+	fuzzyMaxGapPenalty = 10
+	fuzzyNameBonus     = 100 // совпадение в названии важнее совпадения в метаданных
+)
+
+// fuzzyMatch ищет руны pattern в s по порядку без учёта регистра. Возвращает оценку (больше — лучше)
+// и номера совпавших рун s; ok == false, если какой-то руны pattern в s нет.
+// Подстрока целиком ценится выше разрозненных рун, начало слова — выше середины.
+func fuzzyMatch(pattern, s string) (score int, positions []int, ok bool) {
+	p := []rune(strings.ToLower(pattern))
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+	r := []rune(strings.ToLower(s))
+
+	if i := runeIndex(r, p); i >= 0 {
+		positions = make([]int, len(p))
+		for k := range p {
+			positions[k] = i + k
+		}
+		score = fuzzySubstring + len(p)*(fuzzyMatchScore+fuzzyConsecutive)
+		if isWordStart(r, i) {
+			score += fuzzyWordStart
+		}
+		if i == 0 {
+			score += fuzzyFirstRune
+		}
+		return score, positions, true
+	}
+
+	positions = make([]int, 0, len(p))
+	prev := -1
+	for i, k := 0, 0; i < len(r) && k < len(p); i++ {
+		if r[i] != p[k] {
+			continue
+		}
+		score += fuzzyMatchScore
+		switch {
+		case prev >= 0 && i == prev+1:
+			score += fuzzyConsecutive
+		case prev >= 0:
+			score -= min((i-prev-1)*fuzzyGapPenalty, fuzzyMaxGapPenalty)
+		}
+		if isWordStart(r, i) {
+			score += fuzzyWordStart
+		}
+		if i == 0 {
+			score += fuzzyFirstRune
+		}
+		positions = append(positions, i)
+		prev = i
+		k++
+	}
+	if len(positions) < len(p) {
+		return 0, nil, false
+	}
+	return score, positions, true
+}
+
+// runeIndex возвращает позицию первого вхождения sub в r или -1
+func runeIndex(r, sub []rune) int {
+	for i := 0; i+len(sub) <= len(r); i++ {
+		match := true
+		for k := range sub {
+			if r[i+k] != sub[k] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+// isWordStart — руна i начинает слово (первая в строке или после пробела либо знака)
+func isWordStart(r []rune, i int) bool {
+	return i == 0 || !unicode.IsLetter(r[i-1]) && !unicode.IsDigit(r[i-1])
+}
+
+// matchRecord проверяет запись по запросу: каждое слово запроса должно найтись в названии
+// или в ключах и значениях метаданных. Возвращает суммарную оценку и совпавшие руны названия.
+func matchRecord(query string, data *proto.Data) (score int, namePositions []int, ok bool) {
+	for _, term := range strings.Fields(query) {
+		if s, pos, found := fuzzyMatch(term, data.Name); found {
+			score += fuzzyNameBonus + s
+			namePositions = append(namePositions, pos...)
+			continue
+		}
+		best, found := 0, false
+		for _, md := range data.Metadata {
+			if md == nil {
+				continue
+			}
+			if s, _, ok := fuzzyMatch(term, md.Key+" "+md.Value); ok && (!found || s > best) {
+				best, found = s, true
+			}
+		}
+		if !found {
+			return 0, nil, false
+		}
+		score += best
+	}
+	return score, namePositions, true
+}
@@ -1,80 +1,304 @@
 package tui
 
 import (
+	"bytes"
+	"cmp"
 	"fmt"
-	"iter"
 	"slices"
+	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gophkeeper/gophkeeper/internal/client/format"
+	"github.com/gophkeeper/gophkeeper/internal/client/replica"
 	"github.com/gophkeeper/gophkeeper/proto"
 )
 
-// ListDataModel представляет модель списка данных
+var (
+	listRowStyle = lipgloss.NewStyle()
+
+	selectedListRowStyle = listRowStyle.Copy().
+				Foreground(lipgloss.Color("205")).
+				Bold(true)
+
+	listMatchStyle = lipgloss.NewStyle().Underline(true)
+
+	listDimStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	listBadgeStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("230")).
+			Background(lipgloss.Color("160")).
+			Padding(0, 1)
+
+	chipStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")).
+			Padding(0, 1)
+
+	activeChipStyle = chipStyle.Copy().
+			Foreground(lipgloss.Color("230")).
+			Background(lipgloss.Color("62")).
+			Bold(true)
+)
+
+const (
+	// defaultListPageSize — строк на странице, пока размер терминала не известен
+	defaultListPageSize = 15
+	// minListPageSize — строк на странице в слишком низком терминале
+	minListPageSize = 3
+	// listChromeHeight — строки экрана списка вне самих записей: поля и рамка menuStyle, заголовок,
+	// фильтры, строка позиции, подсказки и строка состояния
+	listChromeHeight = 17
+)
+
+// listTypeFilters — фильтры по типу в порядке переключения Tab: все типы (UNKNOWN) и зарегистрированные типы записей
+func listTypeFilters() []proto.DataType {
+	filters := []proto.DataType{proto.DataType_UNKNOWN}
+	for _, rt := range format.RecordTypes() {
+		filters = append(filters, rt.Type)
+	}
+	return filters
+}
+
+// listSort — поле сортировки списка
+type listSort int
+
+const (
+	sortByName listSort = iota
+	sortByUpdated
+	sortByType
+)
+
+func (s listSort) String() string {
+	switch s {
+	case sortByUpdated:
+		return "изменение"
+	case sortByType:
+		return "тип"
+	default:
+		return "название"
+	}
+}
+
+// listState — поиск, фильтр и сортировка списка; хранятся в Model, чтобы не сбрасываться
+// при переходе к записи и обратно
+type listState struct {
+	query      string
+	typeFilter proto.DataType
+	sort       listSort
+	// reverse — обратный порядок (для изменения по умолчанию новые сверху)
+	reverse bool
+	// selectedID — выбранная запись; по ней восстанавливается курсор после синхронизации и фильтрации
+	selectedID string
+}
+
+// compare сравнивает записи в выбранном порядке; при равенстве — по названию и id
+func (s listState) compare(a, b *proto.Data) int {
+	var c int
+	switch s.sort {
+	case sortByUpdated:
+		// Новые сверху
+		c = cmp.Compare(b.UpdatedAt, a.UpdatedAt)
+	case sortByType:
+		c = cmp.Compare(a.Type, b.Type)
+	}
+	if c == 0 {
+		c = cmp.Or(cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)), cmp.Compare(a.Id, b.Id))
+	}
+	if s.reverse {
+		return -c
+	}
+	return c
+}
+
+// listEntry — запись, прошедшая фильтр
+type listEntry struct {
+	data  *proto.Data
+	score int
+	// positions — руны названия, совпавшие с запросом
+	positions []int
+}
+
+// ListDataModel представляет модель списка данных.
+// Список хранится в Model.dataList: его заполняет чтение локальной копии при входе,
+// а фоновая синхронизация дополняет изменениями. Поиск идёт по расшифрованным названиям
+// и метаданным локальной копии, без запросов к серверу.
 type ListDataModel struct {
-	model      *Model
-	selected   int
-	dataList   []*proto.Data
-	err        error
-	loading    bool
+	model *Model
+	// selected — позиция курсора, если выбранная запись пропала из списка
+	selected int
+	// filtering — фокус в строке поиска
+	filtering   bool
+	filterInput textinput.Model
 }
 
 func NewListDataModel(m *Model) *ListDataModel {
-	model := &ListDataModel{
-		model:    m,
-		selected: 0,
-		loading:  true,
+	input := textinput.New()
+	input.Prompt = "/ "
+	input.Placeholder = "поиск по названию и метаданным"
+	input.SetValue(m.list.query)
+	return &ListDataModel{
+		model:       m,
+		filterInput: input,
 	}
-	return model
 }
 
 func (m *ListDataModel) Init() tea.Cmd {
-	return m.loadData()
+	return nil
 }
 
-func (m *ListDataModel) loadData() tea.Cmd {
-	return func() tea.Msg {
-		data, err := m.model.client.ListData(proto.DataType_UNKNOWN)
-		if err != nil {
-			return err
+// entries возвращает записи, прошедшие фильтр по типу и запросу. С запросом сначала идут
+// лучшие совпадения, без него — выбранный порядок.
+func (m *ListDataModel) entries() []listEntry {
+	st := m.model.list
+	var out []listEntry
+	for _, d := range m.model.dataList {
+		if st.typeFilter != proto.DataType_UNKNOWN && d.Type != st.typeFilter {
+			continue
+		}
+		score, positions, ok := matchRecord(st.query, d)
+		if !ok {
+			continue
 		}
-		return data
+		out = append(out, listEntry{data: d, score: score, positions: positions})
+	}
+	slices.SortStableFunc(out, func(a, b listEntry) int {
+		if st.query != "" && a.score != b.score {
+			return cmp.Compare(b.score, a.score)
+		}
+		return st.compare(a.data, b.data)
+	})
+	return out
+}
+
+// cursor возвращает позицию выбранной записи в entries
+func (m *ListDataModel) cursor(entries []listEntry) int {
+	if id := m.model.list.selectedID; id != "" {
+		if i := slices.IndexFunc(entries, func(e listEntry) bool { return e.data.Id == id }); i >= 0 {
+			return i
+		}
+	}
+	return min(m.selected, max(len(entries)-1, 0))
+}
+
+// moveTo выбирает запись с позицией i (с ограничением по краям списка)
+func (m *ListDataModel) moveTo(entries []listEntry, i int) {
+	if len(entries) == 0 {
+		m.selected = 0
+		m.model.list.selectedID = ""
+		return
+	}
+	i = max(0, min(i, len(entries)-1))
+	m.selected = i
+	m.model.list.selectedID = entries[i].data.Id
+}
+
+// pageSize — сколько записей помещается на экране при текущей высоте терминала
+func (m *Model) pageSize() int {
+	if m.height == 0 {
+		return defaultListPageSize
 	}
+	return max(m.height-listChromeHeight, minListPageSize)
+}
+
+// setQuery меняет запрос и ставит курсор на лучшее совпадение
+func (m *ListDataModel) setQuery(query string) {
+	if query == m.model.list.query {
+		return
+	}
+	m.model.list.query = query
+	m.model.list.selectedID = ""
+	m.moveTo(m.entries(), 0)
+}
+
+// cycleType переключает фильтр по типу на следующий (step 1) или предыдущий (step -1)
+func (m *ListDataModel) cycleType(step int) {
+	filters := listTypeFilters()
+	i := slices.Index(filters, m.model.list.typeFilter)
+	n := len(filters)
+	m.model.list.typeFilter = filters[((i+step)%n+n)%n]
+	entries := m.entries()
+	m.moveTo(entries, m.cursor(entries))
 }
 
 func (m *ListDataModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.filtering {
+		return m.updateFilter(msg)
+	}
+
+	entries := m.entries()
+	cur := m.cursor(entries)
+	page := m.model.pageSize()
+
 	switch msg := msg.(type) {
-	case []*proto.Data:
-		if msg == nil {
-			msg = []*proto.Data{}
+	case syncDoneMsg:
+		// Синхронизация могла удалить выбранную запись — курсор остаётся на той же позиции
+		m.moveTo(entries, cur)
+	case recordLoadedMsg:
+		if msg.gen != m.model.sync.gen {
+			return m, nil
 		}
-		m.dataList = msg
-		m.loading = false
-		m.model.dataList = msg
-	case error:
-		m.err = msg
-		m.loading = false
+		if msg.err != nil {
+			m.model.err = msg.err
+			return m, nil
+		}
+		m.model.dataList = replica.Merge(m.model.dataList, []*proto.Data{msg.data}, nil)
+		m.model.currentData = msg.data
+		m.model.state = StateViewData
+		viewModel := NewViewDataModel(m.model)
+		return viewModel, viewModel.Init()
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "up", "k":
-			if m.selected > 0 {
-				m.selected--
-			}
+			m.moveTo(entries, cur-1)
 		case "down", "j":
-			if m.selected < len(m.dataList)-1 {
-				m.selected++
-			}
+			m.moveTo(entries, cur+1)
+		case "pgup", "ctrl+b":
+			m.moveTo(entries, cur-page)
+		case "pgdown", "ctrl+f":
+			m.moveTo(entries, cur+page)
+		case "home", "g":
+			m.moveTo(entries, 0)
+		case "end", "G":
+			m.moveTo(entries, len(entries)-1)
 		case "enter":
-			if len(m.dataList) > 0 && m.selected < len(m.dataList) {
-				m.model.currentData = m.dataList[m.selected]
+			if cur < len(entries) && replica.Partial(entries[cur].data) {
+				m.moveTo(entries, cur)
+				return m, m.loadRecord(entries[cur].data.Id)
+			}
+			if cur < len(entries) {
+				m.moveTo(entries, cur)
+				m.model.currentData = entries[cur].data
 				m.model.state = StateViewData
-				return NewViewDataModel(m.model), nil
+				viewModel := NewViewDataModel(m.model)
+				return viewModel, viewModel.Init()
 			}
+		case "/":
+			m.filtering = true
+			m.filterInput.SetValue(m.model.list.query)
+			m.filterInput.CursorEnd()
+			return m, m.filterInput.Focus()
+		case "tab":
+			m.cycleType(1)
+		case "shift+tab":
+			m.cycleType(-1)
+		case "s":
+			m.model.list.sort = (m.model.list.sort + 1) % (sortByType + 1)
+			m.model.list.reverse = false
+		case "S":
+			m.model.list.reverse = !m.model.list.reverse
 		case "r":
-			m.loading = true
-			return m, m.loadData()
-		case "esc", "q":
+			return m, requestSync
+		case "esc":
+			if m.model.list.query != "" {
+				m.setQuery("")
+				m.filterInput.SetValue("")
+				return m, nil
+			}
+			m.model.state = StateMainMenu
+			return NewMainMenuModel(m.model), nil
+		case "q":
 			m.model.state = StateMainMenu
 			return NewMainMenuModel(m.model), nil
 		}
@@ -82,42 +306,220 @@ func (m *ListDataModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// recordLoadedMsg — запись, у которой в списке было только краткое описание, загружена целиком
+type recordLoadedMsg struct {
+	gen  int
+	data *proto.Data
+	err  error
+}
+
+// loadRecord загружает запись целиком, чтобы открыть её (см. Replica.InitialList)
+func (m *ListDataModel) loadRecord(id string) tea.Cmd {
+	r, gen := m.model.replica, m.model.sync.gen
+	return func() tea.Msg {
+		data, err := r.Get(id)
+		return recordLoadedMsg{gen: gen, data: data, err: err}
+	}
+}
+
+// updateFilter обрабатывает ввод в строке поиска: список фильтруется при каждом изменении,
+// Enter оставляет запрос и возвращает к списку, Esc сбрасывает запрос
+func (m *ListDataModel) updateFilter(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		entries := m.entries()
+		cur := m.cursor(entries)
+		switch msg.String() {
+		case "enter":
+			m.filtering = false
+			m.filterInput.Blur()
+			return m, nil
+		case "esc":
+			m.filtering = false
+			m.filterInput.Blur()
+			m.filterInput.SetValue("")
+			m.setQuery("")
+			return m, nil
+		case "up", "ctrl+p":
+			m.moveTo(entries, cur-1)
+			return m, nil
+		case "down", "ctrl+n":
+			m.moveTo(entries, cur+1)
+			return m, nil
+		case "tab":
+			m.cycleType(1)
+			return m, nil
+		case "shift+tab":
+			m.cycleType(-1)
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	m.setQuery(m.filterInput.Value())
+	return m, cmd
+}
+
 func (m *ListDataModel) View() string {
-	if m.loading {
-		return "Загрузка данных..."
+	if m.model.err != nil {
+		return errorStyle.Render(fmt.Sprintf("Ошибка: %v\n\nНажмите Esc для возврата", m.model.err))
 	}
 
-	if m.err != nil {
-		return errorStyle.Render(fmt.Sprintf("Ошибка: %v\n\nНажмите r для обновления, Esc для возврата", m.err))
+	dataList := m.model.dataList
+	if dataList == nil {
+		return "Загрузка данных..."
 	}
 
-	if len(m.dataList) == 0 {
-		return "Нет данных\n\nНажмите Esc для возврата"
+	if len(dataList) == 0 {
+		if m.model.sync.running {
+			return "Синхронизация данных..."
+		}
+		return "Нет данных\n\nНажмите r для синхронизации, Esc для возврата"
 	}
 
+	entries := m.entries()
+	cur := m.cursor(entries)
+	page := m.model.pageSize()
+	start := cur / page * page
+	end := min(start+page, len(entries))
+
 	items := []string{
 		titleStyle.Render("Список данных"),
+		m.chipsLine(),
+		m.filterLine(),
 		"",
 	}
-	items = append(items, slices.Collect(listDataToLinesSeq(m.dataList, m.selected))...)
-	items = append(items, "", "↑↓ для навигации, Enter для просмотра, r для обновления, Esc для возврата")
+	if len(entries) == 0 {
+		items = append(items, listDimStyle.Render("Ничего не найдено"))
+	}
+	for i := start; i < end; i++ {
+		items = append(items, listRow(entries[i], m.model.recordBadge(entries[i].data), i == cur))
+	}
+	items = append(items, "")
+	if len(entries) > 0 {
+		items = append(items, listDimStyle.Render(fmt.Sprintf("%d–%d из %d · стр. %d/%d",
+			start+1, end, len(entries), start/page+1, (len(entries)+page-1)/page)))
+	} else {
+		items = append(items, "")
+	}
+	items = append(items,
+		"↑↓ выбор, PgUp/PgDn страницы, Enter просмотр, / поиск, Tab тип, s/S сортировка",
+		"r синхронизация, Esc сброс поиска или возврат",
+	)
 	return menuStyle.Render(lipgloss.JoinVertical(lipgloss.Left, items...))
 }
 
-// listDataToLinesSeq возвращает итератор строк списка данных (имя [тип], выбранный/обычный стиль)
-func listDataToLinesSeq(dataList []*proto.Data, selected int) iter.Seq[string] {
-	return func(yield func(string) bool) {
-		for i, d := range dataList {
-			item := fmt.Sprintf("%s [%s]", d.Name, format.DataTypeDisplayName(d.Type))
-			var line string
-			if i == selected {
-				line = selectedMenuItemStyle.Render("▶ " + item)
-			} else {
-				line = menuItemStyle.Render("  " + item)
+// chipsLine — фильтры по типу и текущая сортировка
+func (m *ListDataModel) chipsLine() string {
+	filters := listTypeFilters()
+	chips := make([]string, 0, len(filters)+1)
+	for _, t := range filters {
+		label := "Все"
+		if t != proto.DataType_UNKNOWN {
+			label = format.DataTypeDisplayName(t)
+		}
+		style := chipStyle
+		if t == m.model.list.typeFilter {
+			style = activeChipStyle
+		}
+		chips = append(chips, style.Render(label))
+	}
+
+	st := m.model.list
+	sortLabel := "сортировка: " + st.sort.String()
+	if st.reverse {
+		sortLabel += " (обратная)"
+	}
+	if st.query != "" {
+		sortLabel = "сортировка: совпадение"
+	}
+	chips = append(chips, listDimStyle.Render("  "+sortLabel))
+	return lipgloss.JoinHorizontal(lipgloss.Top, chips...)
+}
+
+// filterLine — строка поиска: поле ввода, пока оно в фокусе, иначе текущий запрос или подсказка
+func (m *ListDataModel) filterLine() string {
+	if m.filtering {
+		return m.filterInput.View()
+	}
+	if m.model.list.query != "" {
+		return "/ " + m.model.list.query
+	}
+	return listDimStyle.Render("/ — поиск")
+}
+
+// listRow — строка записи: название с подчёркнутыми совпадениями, тип, пометка badge и дата изменения
+func listRow(e listEntry, badge string, selected bool) string {
+	style, prefix := listRowStyle, "  "
+	if selected {
+		style, prefix = selectedListRowStyle, "▶ "
+	}
+
+	var b strings.Builder
+	b.WriteString(style.Render(prefix))
+	if e.data.Name == "" {
+		b.WriteString(style.Render("(без названия)"))
+	} else {
+		// Совпавшие с запросом руны подчёркиваются; соседние руны выводятся одним отрезком
+		matched := style.Copy().Inherit(listMatchStyle)
+		runes := []rune(e.data.Name)
+		for i := 0; i < len(runes); {
+			isMatch := slices.Contains(e.positions, i)
+			j := i + 1
+			for j < len(runes) && slices.Contains(e.positions, j) == isMatch {
+				j++
 			}
-			if !yield(line) {
-				return
+			if isMatch {
+				b.WriteString(matched.Render(string(runes[i:j])))
+			} else {
+				b.WriteString(style.Render(string(runes[i:j])))
 			}
+			i = j
 		}
 	}
+	b.WriteString(style.Render(fmt.Sprintf(" [%s]", format.DataTypeDisplayName(e.data.Type))))
+	if badge != "" {
+		b.WriteString(" " + listBadgeStyle.Render(badge))
+	}
+	if e.data.UpdatedAt > 0 {
+		b.WriteString(listDimStyle.Render("  " + time.Unix(e.data.UpdatedAt, 0).Format("02.01.2006")))
+	}
+	return b.String()
+}
+
+// listBadge — пометка записи в списке, вычисленная по содержимому encrypted в день day
+type listBadge struct {
+	encrypted []byte
+	day       string
+	text      string
+}
+
+// recordBadge возвращает пометку записи по её расшифрованному содержимому (format.RecordType.Badge).
+// Пометки хранятся, пока не изменится содержимое записи или дата, поэтому запись расшифровывается
+// один раз, а не при каждой перерисовке списка.
+func (m *Model) recordBadge(d *proto.Data) string {
+	rt := format.LookupRecordType(d.Type)
+	if rt == nil || rt.Badge == nil {
+		return ""
+	}
+	now := time.Now()
+	day := now.Format(time.DateOnly)
+	if b, ok := m.badges[d.Id]; ok && b.day == day && bytes.Equal(b.encrypted, d.EncryptedData) {
+		return b.text
+	}
+	vault := m.client.Vault()
+	if vault == nil {
+		return ""
+	}
+	var text string
+	if payload, err := vault.Decrypt(d.EncryptedData); err == nil {
+		if fields, err := format.ParsePayload(d.Type, payload); err == nil {
+			text = rt.Badge(fields, now)
+		}
+	}
+	if m.badges == nil {
+		m.badges = make(map[string]listBadge)
+	}
+	m.badges[d.Id] = listBadge{encrypted: d.EncryptedData, day: day, text: text}
+	return text
 }
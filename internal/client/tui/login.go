@@ -1,11 +1,15 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/gophkeeper/gophkeeper/internal/client"
+	"github.com/gophkeeper/gophkeeper/internal/client/replica"
+	"github.com/gophkeeper/gophkeeper/internal/client/vault"
 )
 
 var (
@@ -33,11 +37,15 @@ const (
 	registerMode
 )
 
+// loginFieldCount — количество полей формы входа (логин, пароль, мастер-пароль)
+const loginFieldCount = 3
+
 // LoginModel представляет модель входа и регистрации
 type LoginModel struct {
 	model         *Model
 	loginInput    textinput.Model
 	passwordInput textinput.Model
+	masterInput   textinput.Model
 	focused       int
 	mode          int // loginMode или registerMode
 	err           error
@@ -57,10 +65,18 @@ func NewLoginModel(m *Model) *LoginModel {
 	passwordInput.CharLimit = 50
 	passwordInput.Width = 38
 
+	masterInput := textinput.New()
+	masterInput.Placeholder = "Мастер-пароль (шифрование данных)"
+	masterInput.EchoMode = textinput.EchoPassword
+	masterInput.EchoCharacter = '•'
+	masterInput.CharLimit = 100
+	masterInput.Width = 38
+
 	return &LoginModel{
 		model:         m,
 		loginInput:    loginInput,
 		passwordInput: passwordInput,
+		masterInput:   masterInput,
 		focused:       0,
 		mode:          loginMode,
 	}
@@ -81,16 +97,20 @@ func (m *LoginModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m.handleSubmit()
 			}
 			if msg.String() == "tab" || msg.String() == "down" {
-				m.focused = (m.focused + 1) % 2
+				m.focused = (m.focused + 1) % loginFieldCount
 			} else if msg.String() == "up" {
-				m.focused = (m.focused + 2 - 1) % 2
+				m.focused = (m.focused + loginFieldCount - 1) % loginFieldCount
 			}
-			if m.focused == 0 {
+			m.loginInput.Blur()
+			m.passwordInput.Blur()
+			m.masterInput.Blur()
+			switch m.focused {
+			case 0:
 				m.loginInput.Focus()
-				m.passwordInput.Blur()
-			} else {
-				m.loginInput.Blur()
+			case 1:
 				m.passwordInput.Focus()
+			default:
+				m.masterInput.Focus()
 			}
 		case "left", "right", "1", "2":
 			// Переключение сценария: Вход (1) / Регистрация (2) или стрелки
@@ -109,10 +129,13 @@ func (m *LoginModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	if m.focused == 0 {
+	switch m.focused {
+	case 0:
 		m.loginInput, cmd = m.loginInput.Update(msg)
-	} else {
+	case 1:
 		m.passwordInput, cmd = m.passwordInput.Update(msg)
+	default:
+		m.masterInput, cmd = m.masterInput.Update(msg)
 	}
 
 	return m, cmd
@@ -121,11 +144,16 @@ func (m *LoginModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *LoginModel) handleSubmit() (tea.Model, tea.Cmd) {
 	login := m.loginInput.Value()
 	password := m.passwordInput.Value()
+	masterPassword := m.masterInput.Value()
 
 	if login == "" || password == "" {
 		m.err = fmt.Errorf("логин и пароль обязательны")
 		return m, nil
 	}
+	if masterPassword == "" {
+		m.err = fmt.Errorf("мастер-пароль обязателен")
+		return m, nil
+	}
 
 	if m.mode == registerMode {
 		// Сценарий регистрации
@@ -141,16 +169,76 @@ func (m *LoginModel) handleSubmit() (tea.Model, tea.Cmd) {
 	} else {
 		// Сценарий входа
 		if err := m.model.client.Login(login, password); err != nil {
+			if client.IsUnavailable(err) {
+				// Сервер недоступен — открываем локальную копию
+				return m.handleOfflineLogin(login, password, masterPassword)
+			}
 			m.err = err
 			return m, nil
 		}
 	}
 
+	// Открываем хранилище ключа: без мастер-пароля данные нельзя ни прочитать, ни сохранить
+	if err := m.model.client.UnlockVault(masterPassword); err != nil {
+		m.err = masterPasswordError(err)
+		return m, nil
+	}
+
+	store, err := m.model.openCache(login)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	if err := m.model.openReplica(store, login, password); err != nil {
+		m.err = err
+		return m, nil
+	}
+
 	// Успешный вход — переходим в главное меню
 	m.model.state = StateMainMenu
 	return NewMainMenuModel(m.model), nil
 }
 
+// handleOfflineLogin открывает хранилище по блоку проверки из локальной копии.
+// Пароль входа проверит сервер при первой синхронизации.
+func (m *LoginModel) handleOfflineLogin(login, password, masterPassword string) (tea.Model, tea.Cmd) {
+	store, err := m.model.openCache(login)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	keyCheck, err := replica.OfflineKeyCheck(store)
+	if err != nil {
+		_ = store.Close()
+		if errors.Is(err, replica.ErrNoOfflineCopy) {
+			err = fmt.Errorf("нет связи с сервером, а локальной копии для этого пользователя нет")
+		}
+		m.err = err
+		return m, nil
+	}
+	if err := m.model.client.UnlockVaultOffline(masterPassword, keyCheck); err != nil {
+		_ = store.Close()
+		m.err = masterPasswordError(err)
+		return m, nil
+	}
+	if err := m.model.openReplica(store, login, password); err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.model.message = "Нет связи с сервером: открыта локальная копия, изменения будут отправлены при синхронизации"
+	m.model.state = StateMainMenu
+	return NewMainMenuModel(m.model), nil
+}
+
+// masterPasswordError переводит ошибку открытия хранилища в сообщение для пользователя
+func masterPasswordError(err error) error {
+	if errors.Is(err, vault.ErrWrongMasterPassword) {
+		return fmt.Errorf("неверный мастер-пароль")
+	}
+	return err
+}
+
 func (m *LoginModel) View() string {
 	var style lipgloss.Style
 	if m.focused == 0 {
@@ -169,15 +257,24 @@ func (m *LoginModel) View() string {
 
 	passwordView := style.Render(m.passwordInput.View())
 
+	if m.focused == 2 {
+		style = focusedStyle
+	} else {
+		style = inputStyle
+	}
+
+	masterView := style.Render(m.masterInput.View())
+
 	title := "Вход в GophKeeper"
 	if m.mode == registerMode {
 		title = "Регистрация в GophKeeper"
 	}
 	view := fmt.Sprintf(
-		"%s\n\n%s\n\n%s\n",
+		"%s\n\n%s\n\n%s\n\n%s\n",
 		title,
 		loginView,
 		passwordView,
+		masterView,
 	)
 
 	if m.err != nil {
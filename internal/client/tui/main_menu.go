@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"fmt"
 	"iter"
 	"slices"
 
@@ -44,6 +45,7 @@ func NewMainMenuModel(m *Model) *MainMenuModel {
 			"📋 Список данных",
 			"➕ Добавить данные",
 			"🔄 Синхронизация",
+			"🔑 Смена пароля",
 			"🚪 Выход",
 		},
 	}
@@ -56,6 +58,9 @@ func (m *MainMenuModel) Init() tea.Cmd {
 func (m *MainMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// Сообщение о результате предыдущего действия показывается до первого нажатия
+		m.model.message = ""
+		m.model.err = nil
 		switch msg.String() {
 		case "up", "k":
 			if m.selected > 0 {
@@ -88,7 +93,11 @@ func (m *MainMenuModel) handleSelection() (tea.Model, tea.Cmd) {
 		m.model.state = StateSync
 		syncModel := NewSyncModel(m.model)
 		return syncModel, syncModel.Init()
-	case 3: // Выход
+	case 3: // Смена пароля
+		m.model.state = StateChangePassword
+		changeModel := NewChangePasswordModel(m.model)
+		return changeModel, changeModel.Init()
+	case 4: // Выход
 		m.model.quit = true
 		return m, tea.Quit
 	}
@@ -101,6 +110,11 @@ func (m *MainMenuModel) View() string {
 		"",
 	}
 	items = append(items, slices.Collect(menuItemsToLinesSeq(m.menuItems, m.selected))...)
+	if m.model.err != nil {
+		items = append(items, errorStyle.Render(fmt.Sprintf("Ошибка: %v", m.model.err)))
+	} else if m.model.message != "" {
+		items = append(items, successStyle.Render(m.model.message))
+	}
 	items = append(items, "", "Используйте ↑↓ для навигации, Enter для выбора, q для выхода")
 	return menuStyle.Render(lipgloss.JoinVertical(lipgloss.Left, items...))
 }
@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gophkeeper/gophkeeper/internal/client/format"
+	"github.com/gophkeeper/gophkeeper/proto"
+)
+
+var metadataInputStyle = inputStyle.Copy().
+	Width(24).
+	Padding(0, 1)
+
+var metadataFocusedStyle = metadataInputStyle.Copy().
+	BorderForeground(lipgloss.Color("205"))
+
+// metadataRow — строка редактора метаданных
+type metadataRow struct {
+	key   textinput.Model
+	value textinput.Model
+}
+
+// metadataEditor — редактор метаданных записи: строки ключ/значение
+type metadataEditor struct {
+	rows []metadataRow
+	// focus — поле ввода в фокусе: строка*2 для ключа, строка*2+1 для значения
+	focus int
+}
+
+func newMetadataEditor(metadata []*proto.Metadata) metadataEditor {
+	e := metadataEditor{}
+	for _, md := range metadata {
+		e.addRow()
+		row := &e.rows[len(e.rows)-1]
+		row.key.SetValue(md.Key)
+		row.value.SetValue(md.Value)
+	}
+	e.blur()
+	return e
+}
+
+func newMetadataKeyInput() textinput.Model {
+	t := textinput.New()
+	t.Placeholder = "Ключ"
+	t.CharLimit = 100
+	t.Width = 20
+	// Частые ключи подсказываются по мере ввода, → принимает подсказку
+	t.ShowSuggestions = true
+	t.SetSuggestions(format.CommonMetadataKeys)
+	t.KeyMap.AcceptSuggestion = key.NewBinding(key.WithKeys("right"))
+	return t
+}
+
+func newMetadataValueInput() textinput.Model {
+	t := textinput.New()
+	t.Placeholder = "Значение"
+	t.CharLimit = 1000
+	t.Width = 20
+	return t
+}
+
+// addRow добавляет пустую строку в конец и переводит фокус на её ключ
+func (e *metadataEditor) addRow() tea.Cmd {
+	e.rows = append(e.rows, metadataRow{key: newMetadataKeyInput(), value: newMetadataValueInput()})
+	return e.setFocus((len(e.rows) - 1) * 2)
+}
+
+// removeRow удаляет строку в фокусе
+func (e *metadataEditor) removeRow() tea.Cmd {
+	if len(e.rows) == 0 {
+		return nil
+	}
+	row := e.focus / 2
+	e.rows = append(e.rows[:row], e.rows[row+1:]...)
+	return e.setFocus(min(e.focus-e.focus%2, max(len(e.rows)*2-2, 0)))
+}
+
+// move переводит фокус на соседнее поле ввода
+func (e *metadataEditor) move(delta int) tea.Cmd {
+	if len(e.rows) == 0 {
+		return nil
+	}
+	return e.setFocus(min(max(e.focus+delta, 0), len(e.rows)*2-1))
+}
+
+func (e *metadataEditor) setFocus(focus int) tea.Cmd {
+	e.focus = focus
+	e.blur()
+	if input := e.focused(); input != nil {
+		return input.Focus()
+	}
+	return nil
+}
+
+// blur снимает фокус со всех полей (редактор неактивен)
+func (e *metadataEditor) blur() {
+	for i := range e.rows {
+		e.rows[i].key.Blur()
+		e.rows[i].value.Blur()
+	}
+}
+
+func (e *metadataEditor) focused() *textinput.Model {
+	if len(e.rows) == 0 {
+		return nil
+	}
+	row := &e.rows[e.focus/2]
+	if e.focus%2 == 0 {
+		return &row.key
+	}
+	return &row.value
+}
+
+// update передаёт ввод полю в фокусе
+func (e *metadataEditor) update(msg tea.Msg) tea.Cmd {
+	input := e.focused()
+	if input == nil {
+		return nil
+	}
+	var cmd tea.Cmd
+	*input, cmd = input.Update(msg)
+	return cmd
+}
+
+// metadata возвращает проверенные метаданные (см. format.NormalizeMetadata)
+func (e *metadataEditor) metadata() ([]*proto.Metadata, error) {
+	metadata := make([]*proto.Metadata, 0, len(e.rows))
+	for _, row := range e.rows {
+		metadata = append(metadata, &proto.Metadata{Key: row.key.Value(), Value: row.value.Value()})
+	}
+	return format.NormalizeMetadata(metadata)
+}
+
+// view возвращает строки редактора
+func (e *metadataEditor) view() []string {
+	var view []string
+	if len(e.rows) == 0 {
+		view = append(view, "  (метаданных нет)")
+	}
+	for i, row := range e.rows {
+		keyStyle, valueStyle := metadataInputStyle, metadataInputStyle
+		if e.focus == i*2 {
+			keyStyle = metadataFocusedStyle
+		}
+		if e.focus == i*2+1 {
+			valueStyle = metadataFocusedStyle
+		}
+		view = append(view, lipgloss.JoinHorizontal(lipgloss.Center,
+			keyStyle.Render(row.key.View()), " ", valueStyle.Render(row.value.View())))
+	}
+	view = append(view, "", "Частые ключи: "+strings.Join(format.CommonMetadataKeys, ", ")+" (→ — подставить подсказку)")
+	return view
+}
@@ -1,7 +1,13 @@
 package tui
 
 import (
+	"os"
+	"time"
+
 	"github.com/gophkeeper/gophkeeper/internal/client"
+	"github.com/gophkeeper/gophkeeper/internal/client/cache"
+	"github.com/gophkeeper/gophkeeper/internal/client/clipboard"
+	"github.com/gophkeeper/gophkeeper/internal/client/replica"
 	"github.com/gophkeeper/gophkeeper/proto"
 )
 
@@ -18,39 +24,81 @@ const (
 	StateEditData
 	StateDeleteData
 	StateSync
+	StateChangePassword
+	StateConflict
 	StateQuit
 )
 
 // Model представляет основную модель TUI
 type Model struct {
-	client      *client.Client
-	state       AppState
-	err         error
-	message     string
-	login       string
-	password    string
-	selectedIdx int
-	dataList    []*proto.Data
-	currentData *proto.Data
-	quit        bool
+	client        *client.Client
+	replica       *replica.Replica
+	serverAddress string
+	cacheDir      string
+	state         AppState
+	err           error
+	message       string
+	login         string
+	password      string
+	selectedIdx   int
+	dataList      []*proto.Data
+	currentData   *proto.Data
+	quit          bool
+	sync          syncState
+	list          listState
+	clip          clipState
+	// badges — пометки записей в списке по id (см. recordBadge)
+	badges map[string]listBadge
+	// width и height — размер терминала из tea.WindowSizeMsg (0 — ещё не известен)
+	width  int
+	height int
 }
 
-// NewModel создаёт новую модель
-func NewModel(serverAddress string) (*Model, error) {
+// NewModel создаёт новую модель; cacheDir — каталог локальной копии записей,
+// syncInterval — период фоновой синхронизации (0 — только по запросу),
+// encryptMetadata — шифровать названия и метаданные записей при сохранении,
+// clipboardTimeout — через сколько очищать буфер обмена после копирования секрета (0 — не очищать)
+func NewModel(serverAddress, cacheDir string, syncInterval time.Duration, encryptMetadata bool, clipboardTimeout time.Duration) (*Model, error) {
 	c, err := client.NewClient(serverAddress)
 	if err != nil {
 		return nil, err
 	}
+	c.SetEncryptMetadata(encryptMetadata)
 
 	return &Model{
-		client:      c,
-		state:       StateLogin,
-		selectedIdx: 0,
+		client:        c,
+		serverAddress: serverAddress,
+		cacheDir:      cacheDir,
+		sync:          syncState{interval: syncInterval},
+		clip:          clipState{board: clipboard.New(os.Stderr), timeout: clipboardTimeout},
+		state:         StateLogin,
+		selectedIdx:   0,
 	}, nil
 }
 
-// Close закрывает клиент
+// openCache открывает файл локальной копии для пользователя login
+func (m *Model) openCache(login string) (*cache.Cache, error) {
+	return cache.Open(cache.PathFor(m.cacheDir, m.serverAddress, login))
+}
+
+// openReplica подключает локальную копию после того, как хранилище ключа открыто
+func (m *Model) openReplica(store *cache.Cache, login, password string) error {
+	r, err := replica.Open(m.client, store, login, password)
+	if err != nil {
+		_ = store.Close()
+		return err
+	}
+	m.replica = r
+	m.login = login
+	return nil
+}
+
+// Close очищает буфер обмена от скопированного секрета, закрывает локальную копию и клиент
 func (m *Model) Close() error {
+	m.clearClipboard()
+	if m.replica != nil {
+		_ = m.replica.Close()
+	}
 	if m.client != nil {
 		return m.client.Close()
 	}
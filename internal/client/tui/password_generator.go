@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gophkeeper/gophkeeper/internal/client/generator"
+)
+
+var (
+	generatedPasswordStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("86")).
+				Bold(true)
+
+	generatorModeStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("205")).
+				Bold(true)
+
+	weakPasswordStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	strongPasswordStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
+)
+
+// passwordGenerator — панель генератора пароля в форме записи: случайные символы или слова (diceware)
+type passwordGenerator struct {
+	words  bool
+	opts   generator.Options
+	phrase generator.PassphraseOptions
+	value  string
+	err    error
+}
+
+func newPasswordGenerator() passwordGenerator {
+	g := passwordGenerator{
+		opts:   generator.DefaultOptions(),
+		phrase: generator.DefaultPassphraseOptions(),
+	}
+	g.generate()
+	return g
+}
+
+// generate создаёт новый пароль с текущими параметрами
+func (g *passwordGenerator) generate() {
+	if g.words {
+		g.value, g.err = generator.Passphrase(g.phrase)
+	} else {
+		g.value, g.err = generator.Password(g.opts)
+	}
+}
+
+func (g *passwordGenerator) entropy() float64 {
+	if g.words {
+		return g.phrase.Entropy()
+	}
+	return g.opts.Entropy()
+}
+
+// handleKey меняет параметры по клавише и создаёт новый пароль
+func (g *passwordGenerator) handleKey(key string) {
+	switch key {
+	case "tab", "shift+tab":
+		g.words = !g.words
+	case "left", "-":
+		g.resize(-1)
+	case "right", "+", "=":
+		g.resize(1)
+	case "1":
+		if g.words {
+			g.phrase.Capitalize = !g.phrase.Capitalize
+		} else {
+			g.opts.Lower = !g.opts.Lower
+		}
+	case "2":
+		g.opts.Upper = !g.opts.Upper
+	case "3":
+		g.opts.Digits = !g.opts.Digits
+	case "4":
+		g.opts.Symbols = !g.opts.Symbols
+	case "5":
+		g.opts.ExcludeAmbiguous = !g.opts.ExcludeAmbiguous
+	case "r", " ":
+	default:
+		return
+	}
+	g.generate()
+}
+
+// resize меняет длину пароля или число слов в допустимых пределах
+func (g *passwordGenerator) resize(delta int) {
+	if g.words {
+		g.phrase.Words = min(max(g.phrase.Words+delta, generator.MinWords), generator.MaxWords)
+		return
+	}
+	g.opts.Length = min(max(g.opts.Length+delta, generator.MinLength), generator.MaxLength)
+}
+
+func (g *passwordGenerator) view() []string {
+	mode := generatorModeStyle.Render("[Символы]") + "  Слова"
+	if g.words {
+		mode = "Символы  " + generatorModeStyle.Render("[Слова]")
+	}
+	lines := []string{
+		titleStyle.Render("Генератор пароля"),
+		"",
+		"Режим: " + mode,
+	}
+	if g.words {
+		lines = append(lines,
+			fmt.Sprintf("Слов: %d", g.phrase.Words),
+			checkbox(g.phrase.Capitalize, "1", "С заглавной буквы"))
+	} else {
+		lines = append(lines,
+			fmt.Sprintf("Длина: %d", g.opts.Length),
+			strings.Join([]string{
+				checkbox(g.opts.Lower, "1", "a-z"),
+				checkbox(g.opts.Upper, "2", "A-Z"),
+				checkbox(g.opts.Digits, "3", "0-9"),
+				checkbox(g.opts.Symbols, "4", "!@#"),
+				checkbox(g.opts.ExcludeAmbiguous, "5", "без похожих (Il1O0o)"),
+			}, "  "))
+	}
+	lines = append(lines, "")
+	if g.err != nil {
+		lines = append(lines, errorStyle.Render(errorText(g.err)))
+	} else {
+		lines = append(lines, generatedPasswordStyle.Render(g.value))
+		lines = append(lines, strengthLine(g.entropy()))
+	}
+	lines = append(lines, "",
+		"Tab — символы/слова, ←/→ — длина, 1–5 — параметры, r — другой пароль",
+		"Enter — подставить, Esc — отмена")
+	return lines
+}
+
+func checkbox(on bool, key, label string) string {
+	mark := "[ ]"
+	if on {
+		mark = "[x]"
+	}
+	return fmt.Sprintf("%s %s (%s)", mark, label, key)
+}
+
+// strengthLine показывает энтропию пароля и её словесную оценку
+func strengthLine(bits float64) string {
+	var label string
+	switch {
+	case bits < 40:
+		label = weakPasswordStyle.Render("слабый")
+	case bits < 60:
+		label = "средний"
+	case bits < 80:
+		label = strongPasswordStyle.Render("сильный")
+	default:
+		label = strongPasswordStyle.Render("очень сильный")
+	}
+	return fmt.Sprintf("Стойкость: ≈%.0f бит, %s", bits, label)
+}
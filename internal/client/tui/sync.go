@@ -5,7 +5,8 @@ import (
 
 	"github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/gophkeeper/gophkeeper/proto"
+	"github.com/gophkeeper/gophkeeper/internal/client"
+	"github.com/gophkeeper/gophkeeper/internal/client/replica"
 )
 
 // SyncModel представляет модель синхронизации
@@ -24,49 +25,38 @@ func NewSyncModel(m *Model) *SyncModel {
 }
 
 func (m *SyncModel) Init() tea.Cmd {
-	return m.sync()
-}
-
-func (m *SyncModel) sync() tea.Cmd {
-	return func() tea.Msg {
-		// Синхронизируем данные (lastSyncTime = 0 означает получить все данные)
-		data, syncTime, err := m.model.client.SyncData(0)
-		if err != nil {
-			return err
-		}
-		return syncResult{data: data, syncTime: syncTime}
-	}
-}
-
-type syncResult struct {
-	data     []*proto.Data
-	syncTime int64
+	// Синхронизацию выполняет AppModel: отправляет очередь и получает изменения после сохранённого курсора
+	return requestSync
 }
 
 func (m *SyncModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
-	case syncResult:
-		data := msg.data
-		if data == nil {
-			data = []*proto.Data{}
-		}
-		m.model.dataList = data
+	case syncDoneMsg:
 		m.loading = false
-		if len(data) == 0 {
-			m.message = "Данных нет. Синхронизация завершена."
-		} else {
-			m.message = fmt.Sprintf("Синхронизировано %d записей", len(data))
+		if msg.err == nil {
+			m.message = syncReportMessage(msg.report)
+			break
+		}
+		m.err = msg.err
+		if client.IsUnavailable(msg.err) {
+			m.err = fmt.Errorf("нет связи с сервером, изменений в очереди: %d", m.model.sync.pending)
 		}
-	case error:
-		m.err = msg
-		m.loading = false
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "r":
 			m.loading = true
 			m.err = nil
 			m.message = ""
-			return m, m.sync()
+			return m, requestSync
+		case "c":
+			conflicts, err := openConflicts(m.model)
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			if conflicts != nil {
+				return conflicts, nil
+			}
 		case "esc", "q":
 			m.model.state = StateMainMenu
 			return NewMainMenuModel(m.model), nil
@@ -93,7 +83,25 @@ func (m *SyncModel) View() string {
 	}
 
 	view = append(view, "")
+	if m.model.sync.conflicts > 0 {
+		view = append(view, fmt.Sprintf("c — разрешить конфликты (%d)", m.model.sync.conflicts))
+	}
 	view = append(view, "r для повторной синхронизации, Esc для возврата")
 
 	return menuStyle.Render(lipgloss.JoinVertical(lipgloss.Left, view...))
 }
+
+// syncReportMessage описывает итог синхронизации
+func syncReportMessage(r *replica.SyncReport) string {
+	if r.Pushed == 0 && r.Received == 0 && r.Deleted == 0 && len(r.Conflicts) == 0 && r.Failed == 0 {
+		return "Изменений нет. Синхронизация завершена."
+	}
+	msg := fmt.Sprintf("Отправлено изменений: %d, получено записей: %d, удалено: %d", r.Pushed, r.Received, r.Deleted)
+	if len(r.Conflicts) > 0 {
+		msg += fmt.Sprintf("\nКонфликтов версий: %d — локальные правки сохранены и ждут разрешения (клавиша c)", len(r.Conflicts))
+	}
+	if r.Failed > 0 {
+		msg += fmt.Sprintf("\nСервер отклонил изменений: %d, они будут повторены при следующей синхронизации", r.Failed)
+	}
+	return msg
+}
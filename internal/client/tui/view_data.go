@@ -2,15 +2,32 @@ package tui
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gophkeeper/gophkeeper/internal/client/format"
+	"github.com/gophkeeper/gophkeeper/proto"
 )
 
 // ViewDataModel представляет модель просмотра данных
 type ViewDataModel struct {
 	model *Model
+	// exporting — вводится каталог для сохранения прикреплённого файла
+	exporting bool
+	dirInput  textinput.Model
+	// revealed — секретные поля показаны открыто, а не маской
+	revealed bool
+}
+
+// liveTickMsg — пора перерисовать меняющееся со временем содержимое (код TOTP) на экране просмотра view
+type liveTickMsg struct {
+	view *ViewDataModel
 }
 
 func NewViewDataModel(m *Model) *ViewDataModel {
@@ -18,28 +35,170 @@ func NewViewDataModel(m *Model) *ViewDataModel {
 }
 
 func (m *ViewDataModel) Init() tea.Cmd {
+	return m.liveTick()
+}
+
+// recordType возвращает описание типа открытой записи (nil, если тип не зарегистрирован)
+func (m *ViewDataModel) recordType() *format.RecordType {
+	if m.model.currentData == nil {
+		return nil
+	}
+	return format.LookupRecordType(m.model.currentData.Type)
+}
+
+// liveTick планирует перерисовку в начале следующей секунды, пока открыта запись с меняющимся содержимым
+func (m *ViewDataModel) liveTick() tea.Cmd {
+	if rt := m.recordType(); rt == nil || !rt.Live {
+		return nil
+	}
+	return tea.Every(time.Second, func(time.Time) tea.Msg {
+		return liveTickMsg{view: m}
+	})
+}
+
+func (m *ViewDataModel) hasFile() bool {
+	rt := m.recordType()
+	return rt != nil && rt.File && m.model.currentData.BlobId != ""
+}
+
+// copyFields возвращает поля открытой записи, которые копируются в буфер обмена
+func (m *ViewDataModel) copyFields() []*format.Field {
+	if rt := m.recordType(); rt != nil {
+		return rt.CopyFields()
+	}
 	return nil
 }
 
+// copy копирует в буфер обмена поле записи, назначенное клавише key.
+// Возвращает false, если клавиша не назначена ни одному полю.
+func (m *ViewDataModel) copy(key string) (tea.Cmd, bool) {
+	data := m.model.currentData
+	for _, f := range m.copyFields() {
+		if f.CopyKey != key {
+			continue
+		}
+		return m.copyField(data, f), true
+	}
+	return nil, false
+}
+
+// copyField расшифровывает запись и копирует значение поля f
+func (m *ViewDataModel) copyField(data *proto.Data, f *format.Field) tea.Cmd {
+	vault := m.model.client.Vault()
+	if vault == nil {
+		m.model.err = fmt.Errorf("хранилище заблокировано")
+		return nil
+	}
+	payload, err := vault.Decrypt(data.EncryptedData)
+	if err != nil {
+		m.model.err = fmt.Errorf("ошибка расшифровки: %w", err)
+		return nil
+	}
+	fields, err := format.ParsePayload(data.Type, payload)
+	if err != nil {
+		m.model.err = fmt.Errorf("ошибка декодирования: %w", err)
+		return nil
+	}
+	value := fields[f.Key]
+	if f.CopyValue != nil {
+		if value, err = f.CopyValue(value); err != nil {
+			m.model.err = err
+			return nil
+		}
+	}
+	return m.model.copySecret(f.CopyName(), value)
+}
+
 func (m *ViewDataModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if tick, ok := msg.(liveTickMsg); ok {
+		// Тики от прежних экранов просмотра не продолжаются, иначе обновлений стало бы несколько
+		if tick.view != m {
+			return m, nil
+		}
+		return m, m.liveTick()
+	}
+	if m.exporting {
+		return m.updateExport(msg)
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// Сообщение о результате редактирования показывается до первого нажатия
+		m.model.message = ""
+		m.model.err = nil
 		switch msg.String() {
 		case "esc", "q":
 			m.model.state = StateListData
 			listModel := NewListDataModel(m.model)
 			return listModel, listModel.Init()
+		case "e":
+			if m.model.currentData != nil {
+				edit, err := NewEditDataModel(m.model)
+				if err != nil {
+					m.model.err = err
+					return m, nil
+				}
+				m.model.err = nil
+				m.model.state = StateEditData
+				return edit, edit.Init()
+			}
+		case "x":
+			if m.hasFile() {
+				m.dirInput = newTextInput("Каталог")
+				if dir, err := os.Getwd(); err == nil {
+					m.dirInput.SetValue(dir)
+				}
+				m.dirInput.Focus()
+				m.exporting = true
+				return m, textinput.Blink
+			}
+		case "v":
+			m.revealed = !m.revealed
 		case "d":
 			// Удаление данных
 			if m.model.currentData != nil {
 				m.model.state = StateDeleteData
 				return NewDeleteDataModel(m.model), nil
 			}
+		default:
+			if cmd, ok := m.copy(msg.String()); ok {
+				return m, cmd
+			}
 		}
 	}
 	return m, nil
 }
 
+// updateExport обрабатывает ввод каталога и сохраняет в него прикреплённый файл
+func (m *ViewDataModel) updateExport(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			m.exporting = false
+			m.model.err = nil
+			return m, nil
+		case "enter":
+			var path string
+			err := m.model.replica.Do(func() (err error) {
+				path, err = m.model.client.ExportFile(m.model.currentData, m.dirInput.Value())
+				return err
+			})
+			if err != nil {
+				m.model.err = err
+				return m, nil
+			}
+			m.exporting = false
+			m.model.err = nil
+			m.model.message = "Файл сохранён: " + path
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.dirInput, cmd = m.dirInput.Update(msg)
+	return m, cmd
+}
+
 func (m *ViewDataModel) View() string {
 	if m.model.currentData == nil {
 		return "Нет данных для отображения"
@@ -62,7 +221,7 @@ func (m *ViewDataModel) View() string {
 		view = append(view, "")
 	}
 
-	if content := format.DataContentToDisplayString(data); content != "" {
+	if content := format.DataContentToDisplayString(data, m.model.client.Vault(), m.revealed); content != "" {
 		view = append(view, "Данные:")
 		view = append(view, content)
 		view = append(view, "")
@@ -71,7 +230,49 @@ func (m *ViewDataModel) View() string {
 		view = append(view, "")
 	}
 
-	view = append(view, "Esc для возврата, d для удаления")
+	if m.model.err != nil {
+		view = append(view, errorStyle.Render("Ошибка: "+errorText(m.model.err)), "")
+	} else if m.model.message != "" {
+		view = append(view, successStyle.Render(m.model.message), "")
+	}
+
+	if m.exporting {
+		view = append(view, "Сохранить файл в каталог:")
+		view = append(view, focusedStyle.Render(m.dirInput.View()))
+		view = append(view, "Enter — сохранить, Esc — отмена")
+	} else if m.hasFile() {
+		view = append(view, "Esc для возврата, e для редактирования, x для сохранения файла, d для удаления")
+	} else if len(m.copyFields()) > 0 {
+		view = append(view, m.copyHelp())
+		view = append(view, "Esc для возврата, e для редактирования, d для удаления")
+	} else {
+		view = append(view, "Esc для возврата, e для редактирования, d для удаления")
+	}
 
 	return menuStyle.Render(lipgloss.JoinVertical(lipgloss.Left, view...))
 }
+
+// copyHelp — подсказка по клавишам копирования и показа секретов
+func (m *ViewDataModel) copyHelp() string {
+	fields := m.copyFields()
+	parts := make([]string, 0, len(fields)+1)
+	for _, f := range fields {
+		parts = append(parts, f.CopyKey+" — копировать "+copyHint(f.Label))
+	}
+	if m.revealed {
+		parts = append(parts, "v — скрыть")
+	} else {
+		parts = append(parts, "v — показать")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// copyHint — название поля в подсказке: со строчной буквы, если это не аббревиатура (CVV)
+func copyHint(label string) string {
+	r, size := utf8.DecodeRuneInString(label)
+	next, _ := utf8.DecodeRuneInString(label[size:])
+	if unicode.IsUpper(next) {
+		return label
+	}
+	return string(unicode.ToLower(r)) + label[size:]
+}
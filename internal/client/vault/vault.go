@@ -0,0 +1,170 @@
+package vault
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/gophkeeper/gophkeeper/internal/crypto"
+)
+
+var (
+	ErrMasterPasswordRequired = errors.New("master password is required")
+	ErrWrongMasterPassword    = errors.New("wrong master password")
+	ErrLocked                 = errors.New("vault is locked")
+)
+
+// legacyKeyCheckPlaintext — текст блока проверки в первой версии хранилища (до появления мастер-ключа).
+const legacyKeyCheckPlaintext = "gophkeeper-key-check"
+
+// Vault шифрует и расшифровывает содержимое записей на клиенте.
+//
+// Иерархия ключей: из мастер-пароля (не совпадает с паролем входа и не уходит на сервер) один раз
+// за сессию выводится ключ, которым обёрнут случайный мастер-ключ. Обёрнутый мастер-ключ хранится
+// на сервере как блок проверки: если пароль неверный, он не расшифруется. Каждая запись шифруется
+// собственным случайным ключом, обёрнутым мастер-ключом, поэтому смена мастер-пароля требует
+// перешифровать только блок проверки.
+type Vault struct {
+	masterPassword string
+	masterKey      []byte
+}
+
+// Create создаёт хранилище со случайным мастер-ключом и возвращает блок проверки для сохранения на сервере
+func Create(masterPassword string) (*Vault, []byte, error) {
+	if masterPassword == "" {
+		return nil, nil, ErrMasterPasswordRequired
+	}
+
+	masterKey, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v := &Vault{masterPassword: masterPassword, masterKey: masterKey}
+	keyCheck, err := v.wrapMasterKey(masterPassword)
+	if err != nil {
+		return nil, nil, err
+	}
+	return v, keyCheck, nil
+}
+
+// Open открывает хранилище по блоку проверки, полученному с сервера.
+// Если блок создан в устаревшем формате, хранилище переводится на мастер-ключ; если мастер-ключ обёрнут
+// с устаревшими параметрами KDF, он переоборачивается с текущими. В обоих случаях возвращается
+// новый блок проверки — его нужно сохранить на сервере вместо старого. Иначе второй результат nil.
+func Open(masterPassword string, keyCheck []byte) (*Vault, []byte, error) {
+	if masterPassword == "" {
+		return nil, nil, ErrMasterPasswordRequired
+	}
+
+	h, err := crypto.ParseHeader(keyCheck)
+	if err != nil {
+		return nil, nil, ErrWrongMasterPassword
+	}
+
+	plaintext, err := crypto.DecryptData(keyCheck, masterPassword)
+	if err != nil {
+		return nil, nil, ErrWrongMasterPassword
+	}
+
+	if h.Legacy {
+		if !bytes.Equal(plaintext, []byte(legacyKeyCheckPlaintext)) {
+			return nil, nil, ErrWrongMasterPassword
+		}
+		return Create(masterPassword)
+	}
+
+	if len(plaintext) != crypto.KeySize {
+		return nil, nil, ErrWrongMasterPassword
+	}
+
+	v := &Vault{masterPassword: masterPassword, masterKey: plaintext}
+	if !h.NeedsUpgrade() {
+		return v, nil, nil
+	}
+	upgraded, err := v.wrapMasterKey(masterPassword)
+	if err != nil {
+		return nil, nil, err
+	}
+	return v, upgraded, nil
+}
+
+// wrapMasterKey шифрует мастер-ключ паролем (результат служит блоком проверки)
+func (v *Vault) wrapMasterKey(password string) ([]byte, error) {
+	return crypto.EncryptData(v.masterKey, password)
+}
+
+// Encrypt шифрует содержимое записи перед отправкой на сервер (на nil-хранилище возвращает ErrLocked)
+func (v *Vault) Encrypt(plaintext []byte) ([]byte, error) {
+	if v == nil {
+		return nil, ErrLocked
+	}
+	return crypto.SealEnvelope(plaintext, v.masterKey)
+}
+
+// Decrypt расшифровывает содержимое записи, полученное с сервера.
+// Записи, зашифрованные до появления мастер-ключа, расшифровываются мастер-паролем.
+func (v *Vault) Decrypt(ciphertext []byte) ([]byte, error) {
+	if v == nil {
+		return nil, ErrLocked
+	}
+
+	h, err := crypto.ParseHeader(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	if h.Scheme == crypto.SchemeEnvelope {
+		return crypto.OpenEnvelope(ciphertext, v.masterKey)
+	}
+	return crypto.DecryptData(ciphertext, v.masterPassword)
+}
+
+// SearchToken возвращает токен значения для поиска на сервере без расшифровки (см. crypto.SearchToken)
+func (v *Vault) SearchToken(value string) (string, error) {
+	if v == nil {
+		return "", ErrLocked
+	}
+	return crypto.SearchToken(value, v.masterKey)
+}
+
+// ChangeMasterPassword возвращает хранилище с тем же мастер-ключом под новым мастер-паролем и новый блок проверки.
+// Записи, зашифрованные мастер-ключом, перешифровывать не нужно; записи в устаревшем формате
+// нужно перевести через Reencrypt до смены пароля.
+func (v *Vault) ChangeMasterPassword(newMasterPassword string) (*Vault, []byte, error) {
+	if v == nil {
+		return nil, nil, ErrLocked
+	}
+	if newMasterPassword == "" {
+		return nil, nil, ErrMasterPasswordRequired
+	}
+
+	nv := &Vault{masterPassword: newMasterPassword, masterKey: v.masterKey}
+	keyCheck, err := nv.wrapMasterKey(newMasterPassword)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nv, keyCheck, nil
+}
+
+// NeedsReencrypt сообщает, зашифрована ли запись мастер-паролем напрямую (а не мастер-ключом)
+func NeedsReencrypt(ciphertext []byte) bool {
+	h, err := crypto.ParseHeader(ciphertext)
+	return err == nil && h.Scheme != crypto.SchemeEnvelope
+}
+
+// Reencrypt расшифровывает запись и шифрует её заново ключом записи под мастер-ключом
+func (v *Vault) Reencrypt(ciphertext []byte) ([]byte, error) {
+	plaintext, err := v.Decrypt(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	return v.Encrypt(plaintext)
+}
+
+// Upgrade возвращает содержимое записи в актуальном формате: записи, зашифрованные мастер-паролем
+// (в том числе через PBKDF2), перешифровываются под мастер-ключ, остальные возвращаются без изменений.
+func (v *Vault) Upgrade(ciphertext []byte) ([]byte, error) {
+	if !NeedsReencrypt(ciphertext) {
+		return ciphertext, nil
+	}
+	return v.Reencrypt(ciphertext)
+}
@@ -3,28 +3,91 @@ package config
 import (
 	"flag"
 	"os"
+	"path/filepath"
+	"strconv"
+	"time"
 )
 
 // ClientConfig — конфигурация клиента (всё, что парсится из флагов и переменных окружения).
 type ClientConfig struct {
 	// Server — адрес gRPC-сервера (флаг -server или env SERVER_ADDRESS).
 	Server string
+	// CacheDir — каталог локальной зашифрованной копии записей (флаг -cache-dir или env GOPHKEEPER_CACHE_DIR).
+	CacheDir string
+	// SyncInterval — период фоновой синхронизации, 0 — только по запросу (флаг -sync-interval или env SYNC_INTERVAL).
+	SyncInterval time.Duration
+	// EncryptMetadata — шифровать название и метаданные записей, оставляя серверу только токен поиска
+	// (флаг -encrypt-metadata или env ENCRYPT_METADATA).
+	EncryptMetadata bool
+	// ClipboardTimeout — через сколько очищать буфер обмена после копирования секрета, 0 — не очищать
+	// (флаг -clipboard-timeout или env CLIPBOARD_TIMEOUT).
+	ClipboardTimeout time.Duration
 }
 
-const defaultServer = "localhost:50051"
+const (
+	defaultServer       = "localhost:50051"
+	defaultSyncInterval = 30 * time.Second
+	// defaultClipboardTimeout — секрет остаётся в буфере обмена достаточно долго, чтобы вставить его
+	defaultClipboardTimeout = 20 * time.Second
+)
 
 // LoadClient парсит флаги и переменные окружения, заполняет и возвращает ClientConfig.
-// Флаг: -server.
-// Env: SERVER_ADDRESS (переопределяет флаг).
+// Флаги: -server, -cache-dir, -sync-interval, -encrypt-metadata, -clipboard-timeout.
+// Env: SERVER_ADDRESS, GOPHKEEPER_CACHE_DIR, SYNC_INTERVAL, ENCRYPT_METADATA, CLIPBOARD_TIMEOUT (переопределяют флаги).
 func LoadClient() *ClientConfig {
 	server := flag.String("server", defaultServer, "Server address")
+	cacheDir := flag.String("cache-dir", defaultCacheDir(), "Directory for the encrypted offline cache")
+	syncInterval := flag.Duration("sync-interval", defaultSyncInterval, "Background sync interval (0 disables)")
+	encryptMetadata := flag.Bool("encrypt-metadata", false, "Encrypt record names and metadata, keeping only a search token on the server")
+	clipboardTimeout := flag.Duration("clipboard-timeout", defaultClipboardTimeout, "Clear copied secrets from the clipboard after this delay (0 disables)")
 	flag.Parse()
 
 	cfg := &ClientConfig{
-		Server: *server,
+		Server:           *server,
+		CacheDir:         *cacheDir,
+		SyncInterval:     *syncInterval,
+		EncryptMetadata:  *encryptMetadata,
+		ClipboardTimeout: *clipboardTimeout,
 	}
 	if s := os.Getenv("SERVER_ADDRESS"); s != "" {
 		cfg.Server = s
 	}
+	if s := os.Getenv("GOPHKEEPER_CACHE_DIR"); s != "" {
+		cfg.CacheDir = s
+	}
+	if s := os.Getenv("SYNC_INTERVAL"); s != "" {
+		if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+			cfg.SyncInterval = d
+		}
+	}
+	if s := os.Getenv("ENCRYPT_METADATA"); s != "" {
+		if b, err := strconv.ParseBool(s); err == nil {
+			cfg.EncryptMetadata = b
+		}
+	}
+	if s := os.Getenv("CLIPBOARD_TIMEOUT"); s != "" {
+		if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+			cfg.ClipboardTimeout = d
+		}
+	}
+	if cfg.SyncInterval < 0 {
+		cfg.SyncInterval = 0
+	}
+	if cfg.ClipboardTimeout < 0 {
+		cfg.ClipboardTimeout = 0
+	}
+	if cfg.CacheDir == "" {
+		cfg.CacheDir = defaultCacheDir()
+	}
 	return cfg
 }
+
+// defaultCacheDir возвращает каталог кэша пользователя (~/.cache/gophkeeper на Linux),
+// а если он не определён — каталог во временной директории
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "gophkeeper")
+}
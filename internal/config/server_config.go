@@ -24,21 +24,38 @@ type ServerConfig struct {
 	JWTSecret          []byte        // секрет для подписи JWT (env JWT_SECRET)
 	AccessTokenExpiry  time.Duration // время жизни access токена (env ACCESS_TOKEN_EXPIRY)
 	RefreshTokenExpiry time.Duration // время жизни refresh токена (env REFRESH_TOKEN_EXPIRY)
+
+	// Sync
+	TombstoneRetention time.Duration // срок хранения отметок об удалении (env TOMBSTONE_RETENTION)
+
+	// Blob store
+	BlobStore   string // "fs" или "s3" (env BLOB_STORE)
+	BlobDir     string // каталог файлового хранилища (env BLOB_DIR)
+	S3Endpoint  string // адрес S3-совместимого сервиса (env S3_ENDPOINT)
+	S3Region    string // регион (env S3_REGION)
+	S3Bucket    string // бакет (env S3_BUCKET)
+	S3AccessKey string // ключ доступа (env S3_ACCESS_KEY)
+	S3SecretKey string // секретный ключ (env S3_SECRET_KEY)
 }
 
 const (
-	DBTypePostgres = "postgres"
-	DBTypeSQLite   = "sqlite"
-	defaultPort    = "50051"
-	defaultDSN     = "gophkeeper.db"
-	defaultJWT     = "your-secret-key-change-in-production"
-	defaultAccess  = 15 * time.Minute
-	defaultRefresh = 7 * 24 * time.Hour
+	DBTypePostgres            = "postgres"
+	DBTypeSQLite              = "sqlite"
+	BlobStoreFS               = "fs"
+	BlobStoreS3               = "s3"
+	defaultPort               = "50051"
+	defaultDSN                = "gophkeeper.db"
+	defaultJWT                = "your-secret-key-change-in-production"
+	defaultAccess             = 15 * time.Minute
+	defaultRefresh            = 7 * 24 * time.Hour
+	defaultTombstoneRetention = 30 * 24 * time.Hour
+	defaultBlobDir            = "blobs"
 )
 
 // Load парсит флаги и переменные окружения, заполняет и возвращает Config.
 // Флаги: -port, -dsn, -addr.
-// Env: DB_TYPE, JWT_SECRET, ACCESS_TOKEN_EXPIRY, REFRESH_TOKEN_EXPIRY.
+// Env: DB_TYPE, JWT_SECRET, ACCESS_TOKEN_EXPIRY, REFRESH_TOKEN_EXPIRY, TOMBSTONE_RETENTION,
+// BLOB_STORE, BLOB_DIR, S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY, S3_SECRET_KEY.
 func Load() *ServerConfig {
 	port := flag.String("port", defaultPort, "Server port")
 	dsn := flag.String("dsn", "", "Database connection string (default: SQLite)")
@@ -96,6 +113,32 @@ func Load() *ServerConfig {
 	} else {
 		cfg.RefreshTokenExpiry = defaultRefresh
 	}
+	if s := os.Getenv("TOMBSTONE_RETENTION"); s != "" {
+		if d, err := time.ParseDuration(s); err == nil && d > 0 {
+			cfg.TombstoneRetention = d
+		} else {
+			cfg.TombstoneRetention = defaultTombstoneRetention
+		}
+	} else {
+		cfg.TombstoneRetention = defaultTombstoneRetention
+	}
+
+	// Хранилище файлов: по умолчанию каталог на диске сервера
+	if os.Getenv("BLOB_STORE") == BlobStoreS3 {
+		cfg.BlobStore = BlobStoreS3
+	} else {
+		cfg.BlobStore = BlobStoreFS
+	}
+	if s := os.Getenv("BLOB_DIR"); s != "" {
+		cfg.BlobDir = s
+	} else {
+		cfg.BlobDir = defaultBlobDir
+	}
+	cfg.S3Endpoint = os.Getenv("S3_ENDPOINT")
+	cfg.S3Region = os.Getenv("S3_REGION")
+	cfg.S3Bucket = os.Getenv("S3_BUCKET")
+	cfg.S3AccessKey = os.Getenv("S3_ACCESS_KEY")
+	cfg.S3SecretKey = os.Getenv("S3_SECRET_KEY")
 
 	return cfg
 }
@@ -3,16 +3,23 @@ package crypto
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/pbkdf2"
 )
 
 const (
-	// SaltSize размер соли для PBKDF2
+	// SaltSize размер соли для KDF
 	SaltSize = 32
 	// NonceSize размер nonce для AES-GCM
 	NonceSize = 12
@@ -20,81 +27,329 @@ const (
 	KeySize = 32
 	// PBKDF2Iterations количество итераций PBKDF2
 	PBKDF2Iterations = 100000
+
+	// Argon2Time количество проходов Argon2id
+	Argon2Time = 3
+	// Argon2Memory объём памяти Argon2id в КиБ (64 МиБ)
+	Argon2Memory = 64 * 1024
+	// Argon2Threads степень параллелизма Argon2id
+	Argon2Threads = 4
+
+	// argon2MaxMemory ограничивает память, которую может запросить заголовок (1 ГиБ)
+	argon2MaxMemory = 1024 * 1024
+)
+
+// Версионированный формат зашифрованных данных.
+// Заголовок: magic "GK" | версия формата | схема. Заголовок входит в AAD, поэтому его нельзя подменить.
+//
+//	SchemePassword: header | KDF-параметры | salt | nonce | ciphertext
+//	SchemeEnvelope: header | nonce + обёрнутый ключ записи | nonce | ciphertext
+//
+// Данные без заголовка считаются устаревшим форматом salt | nonce | ciphertext (PBKDF2, 100000 итераций).
+// Новые данные шифруются ключом из Argon2id; PBKDF2 поддерживается для чтения старых данных.
+const (
+	FormatVersion byte = 1
+
+	// SchemePassword — ключ выводится из пароля через KDF (используется для обёртки мастер-ключа)
+	SchemePassword byte = 1
+	// SchemeEnvelope — данные зашифрованы случайным ключом записи, обёрнутым мастер-ключом
+	SchemeEnvelope byte = 2
+
+	// KDFPBKDF2SHA256 — PBKDF2 с HMAC-SHA256
+	KDFPBKDF2SHA256 byte = 1
+	// KDFArgon2id — Argon2id (memory-hard, используется по умолчанию)
+	KDFArgon2id byte = 2
+
+	headerSize     = 4
+	kdfParamsSize  = 10
+	wrappedKeySize = NonceSize + KeySize + 16 // nonce + ключ + тег GCM
+)
+
+var headerMagic = [2]byte{'G', 'K'}
+
+var (
+	ErrDataTooShort      = errors.New("encrypted data too short")
+	ErrUnsupportedFormat = errors.New("unsupported encrypted data format")
+	ErrUnsupportedKDF    = errors.New("unsupported key derivation function")
+	ErrInvalidKDFParams  = errors.New("invalid key derivation parameters")
+	ErrMasterKeyRequired = errors.New("data is encrypted with a master key")
+	ErrNotEnvelope       = errors.New("data is not envelope-encrypted")
+	ErrInvalidKeySize    = errors.New("invalid key size")
 )
 
-// EncryptData шифрует данные с использованием AES-256-GCM
-// Использует PBKDF2 для получения ключа из пароля пользователя
+// KDFParams описывает функцию вывода ключа и её параметры; сохраняется в заголовке шифротекста.
+type KDFParams struct {
+	Algorithm  byte
+	Iterations uint32
+	Memory     uint32 // КиБ, для memory-hard функций
+	Threads    uint8
+}
+
+// DefaultKDFParams возвращает параметры KDF для новых данных
+func DefaultKDFParams() KDFParams {
+	return KDFParams{Algorithm: KDFArgon2id, Iterations: Argon2Time, Memory: Argon2Memory, Threads: Argon2Threads}
+}
+
+// PBKDF2Params возвращает параметры PBKDF2, которыми шифровались данные до перехода на Argon2id
+func PBKDF2Params() KDFParams {
+	return KDFParams{Algorithm: KDFPBKDF2SHA256, Iterations: PBKDF2Iterations}
+}
+
+// Header — разобранный заголовок зашифрованных данных
+type Header struct {
+	Version byte
+	Scheme  byte
+	// KDF заполняется для SchemePassword и устаревшего формата
+	KDF KDFParams
+	// Legacy — данные в формате без заголовка
+	Legacy bool
+}
+
+// NeedsUpgrade сообщает, что данные, зашифрованные паролем, стоит перешифровать:
+// они в устаревшем формате или ключ выведен не с текущими параметрами KDF.
+func (h Header) NeedsUpgrade() bool {
+	if h.Scheme != SchemePassword {
+		return false
+	}
+	return h.Legacy || h.KDF != DefaultKDFParams()
+}
+
+// DeriveKey выводит ключ шифрования из пароля по заданным параметрам KDF
+func DeriveKey(password string, salt []byte, params KDFParams) ([]byte, error) {
+	switch params.Algorithm {
+	case KDFPBKDF2SHA256:
+		if params.Iterations == 0 {
+			return nil, ErrInvalidKDFParams
+		}
+		return pbkdf2.Key([]byte(password), salt, int(params.Iterations), KeySize, sha256.New), nil
+	case KDFArgon2id:
+		// Параметры приходят из заголовка шифротекста: argon2 паникует на нулевых значениях,
+		// а чрезмерная память позволила бы подложенным данным исчерпать ресурсы клиента
+		if params.Iterations == 0 || params.Threads == 0 ||
+			params.Memory < 8*uint32(params.Threads) || params.Memory > argon2MaxMemory {
+			return nil, ErrInvalidKDFParams
+		}
+		return argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Threads, KeySize), nil
+	default:
+		return nil, ErrUnsupportedKDF
+	}
+}
+
+// GenerateKey создаёт случайный ключ AES-256
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// ParseHeader разбирает заголовок зашифрованных данных
+func ParseHeader(encryptedData []byte) (Header, error) {
+	if len(encryptedData) < headerSize || encryptedData[0] != headerMagic[0] || encryptedData[1] != headerMagic[1] {
+		if len(encryptedData) < SaltSize+NonceSize {
+			return Header{}, ErrDataTooShort
+		}
+		return Header{
+			Legacy: true,
+			Scheme: SchemePassword,
+			KDF:    PBKDF2Params(),
+		}, nil
+	}
+
+	h := Header{Version: encryptedData[2], Scheme: encryptedData[3]}
+	if h.Version != FormatVersion {
+		return Header{}, fmt.Errorf("%w: version %d", ErrUnsupportedFormat, h.Version)
+	}
+
+	switch h.Scheme {
+	case SchemePassword:
+		if len(encryptedData) < headerSize+kdfParamsSize+SaltSize+NonceSize {
+			return Header{}, ErrDataTooShort
+		}
+		h.KDF = decodeKDFParams(encryptedData[headerSize : headerSize+kdfParamsSize])
+	case SchemeEnvelope:
+		if len(encryptedData) < headerSize+wrappedKeySize+NonceSize {
+			return Header{}, ErrDataTooShort
+		}
+	default:
+		return Header{}, fmt.Errorf("%w: scheme %d", ErrUnsupportedFormat, h.Scheme)
+	}
+	return h, nil
+}
+
+// EncryptData шифрует данные с использованием AES-256-GCM.
+// Ключ выводится из пароля через KDF; параметры KDF и соль записываются в заголовок.
 func EncryptData(data []byte, password string) ([]byte, error) {
-	// Генерируем соль
+	return EncryptDataWithParams(data, password, DefaultKDFParams())
+}
+
+// EncryptDataWithParams шифрует данные паролем с явными параметрами KDF
+func EncryptDataWithParams(data []byte, password string, params KDFParams) ([]byte, error) {
 	salt := make([]byte, SaltSize)
 	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
 		return nil, err
 	}
 
-	// Генерируем ключ из пароля
-	key := pbkdf2.Key([]byte(password), salt, PBKDF2Iterations, KeySize, sha256.New)
-
-	// Создаём AES cipher
-	block, err := aes.NewCipher(key)
+	key, err := DeriveKey(password, salt, params)
 	if err != nil {
 		return nil, err
 	}
 
-	// Создаём GCM
-	aesGCM, err := cipher.NewGCM(block)
+	prefix := make([]byte, 0, headerSize+kdfParamsSize+SaltSize)
+	prefix = append(prefix, headerMagic[0], headerMagic[1], FormatVersion, SchemePassword)
+	prefix = append(prefix, encodeKDFParams(params)...)
+	prefix = append(prefix, salt...)
+
+	return seal(key, prefix, data)
+}
+
+// DecryptData расшифровывает данные, зашифрованные паролем.
+// Схема и параметры KDF определяются по заголовку; данные без заголовка читаются в устаревшем формате.
+func DecryptData(encryptedData []byte, password string) ([]byte, error) {
+	h, err := ParseHeader(encryptedData)
 	if err != nil {
 		return nil, err
 	}
 
-	// Генерируем nonce
-	nonce := make([]byte, NonceSize)
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+	if h.Legacy {
+		salt := encryptedData[:SaltSize]
+		key, err := DeriveKey(password, salt, h.KDF)
+		if err != nil {
+			return nil, err
+		}
+		return open(key, nil, encryptedData[SaltSize:])
+	}
+
+	if h.Scheme != SchemePassword {
+		return nil, ErrMasterKeyRequired
+	}
+
+	prefixSize := headerSize + kdfParamsSize + SaltSize
+	salt := encryptedData[headerSize+kdfParamsSize : prefixSize]
+	key, err := DeriveKey(password, salt, h.KDF)
+	if err != nil {
 		return nil, err
 	}
+	return open(key, encryptedData[:prefixSize], encryptedData[prefixSize:])
+}
 
-	// Шифруем данные
-	ciphertext := aesGCM.Seal(nil, nonce, data, nil)
+// searchTokenInfo отделяет ключ токенов поиска от остальных применений мастер-ключа
+const searchTokenInfo = "gophkeeper search token"
 
-	// Формат: salt + nonce + ciphertext
-	result := make([]byte, 0, SaltSize+NonceSize+len(ciphertext))
-	result = append(result, salt...)
-	result = append(result, nonce...)
-	result = append(result, ciphertext...)
+// SearchToken возвращает детерминированный непрозрачный токен значения (hex HMAC-SHA256 под ключом,
+// выведенным из мастер-ключа через HKDF). Сервер может искать по равенству токенов, не зная значения.
+// Значение сравнивается без учёта регистра и пробелов по краям.
+func SearchToken(value string, masterKey []byte) (string, error) {
+	if len(masterKey) != KeySize {
+		return "", ErrInvalidKeySize
+	}
+	key, err := hkdf.Key(sha256.New, masterKey, nil, searchTokenInfo, KeySize)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(value))))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// SealEnvelope шифрует данные случайным ключом записи и оборачивает этот ключ мастер-ключом.
+// KDF при этом не выполняется, поэтому шифрование записей не зависит от стоимости вывода ключа из пароля.
+func SealEnvelope(data []byte, masterKey []byte) ([]byte, error) {
+	if len(masterKey) != KeySize {
+		return nil, ErrInvalidKeySize
+	}
 
-	return result, nil
+	dataKey, err := GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	header := []byte{headerMagic[0], headerMagic[1], FormatVersion, SchemeEnvelope}
+	wrapped, err := seal(masterKey, header, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	// seal вернул header | nonce | обёрнутый ключ — это и есть префикс для данных записи
+	return seal(dataKey, wrapped, data)
 }
 
-// DecryptData расшифровывает данные
-func DecryptData(encryptedData []byte, password string) ([]byte, error) {
-	if len(encryptedData) < SaltSize+NonceSize {
-		return nil, errors.New("encrypted data too short")
+// OpenEnvelope расшифровывает данные, зашифрованные SealEnvelope
+func OpenEnvelope(encryptedData []byte, masterKey []byte) ([]byte, error) {
+	if len(masterKey) != KeySize {
+		return nil, ErrInvalidKeySize
 	}
 
-	// Извлекаем соль, nonce и ciphertext
-	salt := encryptedData[:SaltSize]
-	nonce := encryptedData[SaltSize : SaltSize+NonceSize]
-	ciphertext := encryptedData[SaltSize+NonceSize:]
+	h, err := ParseHeader(encryptedData)
+	if err != nil {
+		return nil, err
+	}
+	if h.Legacy || h.Scheme != SchemeEnvelope {
+		return nil, ErrNotEnvelope
+	}
 
-	// Генерируем ключ из пароля
-	key := pbkdf2.Key([]byte(password), salt, PBKDF2Iterations, KeySize, sha256.New)
+	prefixSize := headerSize + wrappedKeySize
+	dataKey, err := open(masterKey, encryptedData[:headerSize], encryptedData[headerSize:prefixSize])
+	if err != nil {
+		return nil, err
+	}
+	return open(dataKey, encryptedData[:prefixSize], encryptedData[prefixSize:])
+}
 
-	// Создаём AES cipher
-	block, err := aes.NewCipher(key)
+// seal шифрует data ключом key и возвращает prefix | nonce | ciphertext (prefix используется как AAD)
+func seal(key, prefix, data []byte) ([]byte, error) {
+	aesGCM, err := newGCM(key)
 	if err != nil {
 		return nil, err
 	}
 
-	// Создаём GCM
-	aesGCM, err := cipher.NewGCM(block)
+	nonce := make([]byte, NonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	result := make([]byte, 0, len(prefix)+NonceSize+len(data)+aesGCM.Overhead())
+	result = append(result, prefix...)
+	result = append(result, nonce...)
+	return aesGCM.Seal(result, nonce, data, prefix), nil
+}
+
+// open расшифровывает nonce | ciphertext ключом key с дополнительными данными aad
+func open(key, aad, encrypted []byte) ([]byte, error) {
+	if len(encrypted) < NonceSize {
+		return nil, ErrDataTooShort
+	}
+
+	aesGCM, err := newGCM(key)
 	if err != nil {
 		return nil, err
 	}
 
-	// Расшифровываем данные
-	plaintext, err := aesGCM.Open(nil, nonce, ciphertext, nil)
+	return aesGCM.Open(nil, encrypted[:NonceSize], encrypted[NonceSize:], aad)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
 	}
+	return cipher.NewGCM(block)
+}
 
-	return plaintext, nil
+func encodeKDFParams(p KDFParams) []byte {
+	buf := make([]byte, kdfParamsSize)
+	buf[0] = p.Algorithm
+	binary.BigEndian.PutUint32(buf[1:5], p.Iterations)
+	binary.BigEndian.PutUint32(buf[5:9], p.Memory)
+	buf[9] = p.Threads
+	return buf
+}
+
+func decodeKDFParams(buf []byte) KDFParams {
+	return KDFParams{
+		Algorithm:  buf[0],
+		Iterations: binary.BigEndian.Uint32(buf[1:5]),
+		Memory:     binary.BigEndian.Uint32(buf[5:9]),
+		Threads:    buf[9],
+	}
 }
@@ -0,0 +1,295 @@
+package crypto_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/gophkeeper/gophkeeper/internal/crypto"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// encryptLegacy шифрует данные в формате без заголовка: salt | nonce | ciphertext
+func encryptLegacy(t *testing.T, data []byte, password string) []byte {
+	t.Helper()
+	salt := make([]byte, crypto.SaltSize)
+	nonce := make([]byte, crypto.NonceSize)
+	if _, err := rand.Read(salt); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rand.Read(nonce); err != nil {
+		t.Fatal(err)
+	}
+	key := pbkdf2.Key([]byte(password), salt, crypto.PBKDF2Iterations, crypto.KeySize, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aesGCM, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := append(append([]byte{}, salt...), nonce...)
+	return aesGCM.Seal(result, nonce, data, nil)
+}
+
+func TestEncryptDecryptData_RoundTrip(t *testing.T) {
+	encrypted, err := crypto.EncryptData([]byte("secret"), "password")
+	if err != nil {
+		t.Fatalf("EncryptData: %v", err)
+	}
+
+	h, err := crypto.ParseHeader(encrypted)
+	if err != nil {
+		t.Fatalf("ParseHeader: %v", err)
+	}
+	if h.Legacy || h.Scheme != crypto.SchemePassword || h.KDF != crypto.DefaultKDFParams() {
+		t.Errorf("header = %+v", h)
+	}
+
+	plaintext, err := crypto.DecryptData(encrypted, "password")
+	if err != nil {
+		t.Fatalf("DecryptData: %v", err)
+	}
+	if string(plaintext) != "secret" {
+		t.Errorf("plaintext = %q, want secret", plaintext)
+	}
+}
+
+func TestDecryptData_WrongPassword(t *testing.T) {
+	encrypted, err := crypto.EncryptData([]byte("secret"), "password")
+	if err != nil {
+		t.Fatalf("EncryptData: %v", err)
+	}
+	if _, err := crypto.DecryptData(encrypted, "wrong"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestDecryptData_TamperedHeader(t *testing.T) {
+	encrypted, err := crypto.EncryptData([]byte("secret"), "password")
+	if err != nil {
+		t.Fatalf("EncryptData: %v", err)
+	}
+	// Меняем соль внутри заголовка: заголовок входит в AAD, поэтому расшифровка должна провалиться
+	encrypted[20] ^= 0xff
+	if _, err := crypto.DecryptData(encrypted, "password"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestDecryptData_LegacyFormat(t *testing.T) {
+	encrypted := encryptLegacy(t, []byte("old secret"), "password")
+
+	h, err := crypto.ParseHeader(encrypted)
+	if err != nil {
+		t.Fatalf("ParseHeader: %v", err)
+	}
+	if !h.Legacy {
+		t.Error("expected legacy header")
+	}
+
+	plaintext, err := crypto.DecryptData(encrypted, "password")
+	if err != nil {
+		t.Fatalf("DecryptData: %v", err)
+	}
+	if string(plaintext) != "old secret" {
+		t.Errorf("plaintext = %q", plaintext)
+	}
+}
+
+func TestSealOpenEnvelope_RoundTrip(t *testing.T) {
+	masterKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	first, err := crypto.SealEnvelope([]byte("payload"), masterKey)
+	if err != nil {
+		t.Fatalf("SealEnvelope: %v", err)
+	}
+	second, err := crypto.SealEnvelope([]byte("payload"), masterKey)
+	if err != nil {
+		t.Fatalf("SealEnvelope: %v", err)
+	}
+	if bytes.Equal(first, second) {
+		t.Error("each record must get its own data key and nonce")
+	}
+
+	plaintext, err := crypto.OpenEnvelope(first, masterKey)
+	if err != nil {
+		t.Fatalf("OpenEnvelope: %v", err)
+	}
+	if string(plaintext) != "payload" {
+		t.Errorf("plaintext = %q, want payload", plaintext)
+	}
+}
+
+func TestOpenEnvelope_WrongKey(t *testing.T) {
+	masterKey, _ := crypto.GenerateKey()
+	otherKey, _ := crypto.GenerateKey()
+
+	encrypted, err := crypto.SealEnvelope([]byte("payload"), masterKey)
+	if err != nil {
+		t.Fatalf("SealEnvelope: %v", err)
+	}
+	if _, err := crypto.OpenEnvelope(encrypted, otherKey); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestOpenEnvelope_PasswordScheme(t *testing.T) {
+	masterKey, _ := crypto.GenerateKey()
+	encrypted, err := crypto.EncryptData([]byte("secret"), "password")
+	if err != nil {
+		t.Fatalf("EncryptData: %v", err)
+	}
+
+	if _, err := crypto.OpenEnvelope(encrypted, masterKey); !errors.Is(err, crypto.ErrNotEnvelope) {
+		t.Errorf("err = %v, want ErrNotEnvelope", err)
+	}
+}
+
+func TestDecryptData_EnvelopeRequiresMasterKey(t *testing.T) {
+	masterKey, _ := crypto.GenerateKey()
+	encrypted, err := crypto.SealEnvelope([]byte("payload"), masterKey)
+	if err != nil {
+		t.Fatalf("SealEnvelope: %v", err)
+	}
+
+	if _, err := crypto.DecryptData(encrypted, "password"); !errors.Is(err, crypto.ErrMasterKeyRequired) {
+		t.Errorf("err = %v, want ErrMasterKeyRequired", err)
+	}
+}
+
+func TestParseHeader_TooShort(t *testing.T) {
+	if _, err := crypto.ParseHeader([]byte("GK")); !errors.Is(err, crypto.ErrDataTooShort) {
+		t.Errorf("err = %v, want ErrDataTooShort", err)
+	}
+}
+
+func TestDecryptData_PBKDF2Header(t *testing.T) {
+	encrypted, err := crypto.EncryptDataWithParams([]byte("secret"), "password", crypto.PBKDF2Params())
+	if err != nil {
+		t.Fatalf("EncryptDataWithParams: %v", err)
+	}
+
+	h, err := crypto.ParseHeader(encrypted)
+	if err != nil {
+		t.Fatalf("ParseHeader: %v", err)
+	}
+	if h.KDF != crypto.PBKDF2Params() {
+		t.Errorf("KDF = %+v", h.KDF)
+	}
+
+	plaintext, err := crypto.DecryptData(encrypted, "password")
+	if err != nil {
+		t.Fatalf("DecryptData: %v", err)
+	}
+	if string(plaintext) != "secret" {
+		t.Errorf("plaintext = %q, want secret", plaintext)
+	}
+}
+
+func TestHeader_NeedsUpgrade(t *testing.T) {
+	masterKey, _ := crypto.GenerateKey()
+	current, _ := crypto.EncryptData([]byte("x"), "password")
+	pbkdf2Blob, _ := crypto.EncryptDataWithParams([]byte("x"), "password", crypto.PBKDF2Params())
+	envelope, _ := crypto.SealEnvelope([]byte("x"), masterKey)
+
+	tests := []struct {
+		name string
+		data []byte
+		want bool
+	}{
+		{"argon2id", current, false},
+		{"pbkdf2", pbkdf2Blob, true},
+		{"legacy", encryptLegacy(t, []byte("x"), "password"), true},
+		{"envelope", envelope, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := crypto.ParseHeader(tt.data)
+			if err != nil {
+				t.Fatalf("ParseHeader: %v", err)
+			}
+			if got := h.NeedsUpgrade(); got != tt.want {
+				t.Errorf("NeedsUpgrade() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeriveKey_InvalidArgon2Params(t *testing.T) {
+	salt := make([]byte, crypto.SaltSize)
+	for _, params := range []crypto.KDFParams{
+		{Algorithm: crypto.KDFArgon2id, Iterations: 0, Memory: crypto.Argon2Memory, Threads: 1},
+		{Algorithm: crypto.KDFArgon2id, Iterations: 1, Memory: crypto.Argon2Memory, Threads: 0},
+		{Algorithm: crypto.KDFArgon2id, Iterations: 1, Memory: 1 << 30, Threads: 1},
+		{Algorithm: 0xff, Iterations: 1},
+	} {
+		if _, err := crypto.DeriveKey("password", salt, params); err == nil {
+			t.Errorf("DeriveKey(%+v): expected error", params)
+		}
+	}
+}
+
+func BenchmarkDeriveKey(b *testing.B) {
+	salt := make([]byte, crypto.SaltSize)
+	for _, bm := range []struct {
+		name   string
+		params crypto.KDFParams
+	}{
+		{"PBKDF2-SHA256", crypto.PBKDF2Params()},
+		{"Argon2id", crypto.DefaultKDFParams()},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := crypto.DeriveKey("master password", salt, bm.params); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSealEnvelope(b *testing.B) {
+	masterKey, _ := crypto.GenerateKey()
+	payload := make([]byte, 4096)
+	b.SetBytes(int64(len(payload)))
+	for i := 0; i < b.N; i++ {
+		if _, err := crypto.SealEnvelope(payload, masterKey); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestSearchToken(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+
+	token, err := crypto.SearchToken("Почта", key)
+	if err != nil {
+		t.Fatalf("SearchToken: %v", err)
+	}
+	if len(token) != 64 {
+		t.Errorf("len(token) = %d, want 64 hex chars", len(token))
+	}
+	if same, _ := crypto.SearchToken("  почта ", key); same != token {
+		t.Error("токен не должен зависеть от регистра и пробелов по краям")
+	}
+	if diff, _ := crypto.SearchToken("Банк", key); diff == token {
+		t.Error("разные значения должны давать разные токены")
+	}
+	if diff, _ := crypto.SearchToken("Почта", other); diff == token {
+		t.Error("токен должен зависеть от мастер-ключа")
+	}
+	if _, err := crypto.SearchToken("Почта", key[:16]); !errors.Is(err, crypto.ErrInvalidKeySize) {
+		t.Errorf("short key err = %v, want ErrInvalidKeySize", err)
+	}
+}
@@ -17,6 +17,8 @@ var (
 // Claims представляет JWT claims
 type Claims struct {
 	UserID string `json:"user_id"`
+	// TokenVersion — поколение токенов пользователя на момент выдачи (см. models.User.TokenVersion)
+	TokenVersion int64 `json:"tv,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -28,12 +30,13 @@ func checkJWTConfig() error {
 }
 
 // GenerateAccessToken генерирует access токен
-func GenerateAccessToken(userID string) (string, error) {
+func GenerateAccessToken(userID string, tokenVersion int64) (string, error) {
 	if err := checkJWTConfig(); err != nil {
 		return "", err
 	}
 	claims := &Claims{
-		UserID: userID,
+		UserID:       userID,
+		TokenVersion: tokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenExpiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -45,12 +48,13 @@ func GenerateAccessToken(userID string) (string, error) {
 }
 
 // GenerateRefreshToken генерирует refresh токен
-func GenerateRefreshToken(userID string) (string, error) {
+func GenerateRefreshToken(userID string, tokenVersion int64) (string, error) {
 	if err := checkJWTConfig(); err != nil {
 		return "", err
 	}
 	claims := &Claims{
-		UserID: userID,
+		UserID:       userID,
+		TokenVersion: tokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(RefreshTokenExpiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gophkeeper/gophkeeper/internal/models"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -destination=mocks/mock_blob_repository.go -package=mocks github.com/gophkeeper/gophkeeper/internal/domain/repository BlobRepository
+
+// BlobRepository определяет контракт для учёта загруженных файлов: владелец, размер и ключ содержимого в BlobStore
+type BlobRepository interface {
+	// Reserve сохраняет запись о файле до загрузки содержимого blob.Hash в BlobStore; ID генерируется,
+	// если не задан. Пока запись есть, очистка не удаляет содержимое. purging = true, если удаление
+	// содержимого уже началось: загружать его нужно после того, как ContentPurging вернёт false.
+	Reserve(ctx context.Context, blob *models.Blob) (purging bool, err error)
+	// ContentPurging сообщает, удаляется ли содержимое с ключом hash из BlobStore
+	ContentPurging(ctx context.Context, hash string) (bool, error)
+	// Get возвращает файл пользователя (nil, если его нет)
+	Get(ctx context.Context, userID, blobID string) (*models.Blob, error)
+	// ListUnreferenced возвращает файлы, загруженные раньше before, на которые не ссылается ни одна запись
+	ListUnreferenced(ctx context.Context, before time.Time) ([]*models.Blob, error)
+	// Delete удаляет запись о файле; содержимое, на которое больше не ссылается ни один файл, помечается к удалению
+	Delete(ctx context.Context, blobID string) error
+	// ClaimPurges начинает удаление содержимого, помеченного раньше before и не загруженного снова,
+	// и возвращает ключи содержимого, которое нужно удалить из BlobStore
+	ClaimPurges(ctx context.Context, before time.Time) ([]string, error)
+	// FinishPurge снимает пометку после удаления содержимого с ключом hash из BlobStore
+	FinishPurge(ctx context.Context, hash string) error
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"io"
+)
+
+//go:generate go run go.uber.org/mock/mockgen -destination=mocks/mock_blob_store.go -package=mocks github.com/gophkeeper/gophkeeper/internal/domain/repository BlobStore
+
+// BlobStore хранит содержимое файлов вне БД по адресу содержимого: ключ — hex SHA-256 сохранённых байт,
+// поэтому одинаковое содержимое хранится один раз
+type BlobStore interface {
+	// Put сохраняет содержимое r целиком и возвращает его ключ и размер.
+	// Ошибка чтения r возвращается обёрнутой, частично записанное содержимое не сохраняется.
+	Put(ctx context.Context, r io.Reader) (key string, size int64, err error)
+	// Open открывает содержимое для чтения; ErrBlobNotFound, если ключа нет
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete удаляет содержимое; отсутствие ключа не считается ошибкой
+	Delete(ctx context.Context, key string) error
+}
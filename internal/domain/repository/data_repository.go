@@ -11,9 +11,18 @@ import (
 
 // DataRepository определяет контракт для работы с данными пользователя
 type DataRepository interface {
-	Save(ctx context.Context, userID string, data *models.Data) error
+	// Save создаёт запись (baseVersion == 0) или обновляет её, если текущая версия равна baseVersion.
+	// При несовпадении версий возвращает *VersionConflictError с актуальной копией записи.
+	Save(ctx context.Context, userID string, data *models.Data, baseVersion int64) error
 	Get(ctx context.Context, userID, dataID string) (*models.Data, error)
 	List(ctx context.Context, userID string, dataType models.DataType) ([]*models.Data, error)
+	// ListSummaries возвращает страницу кратких описаний записей без содержимого в порядке q.Sort
+	ListSummaries(ctx context.Context, userID string, q models.DataSummaryQuery) ([]*models.DataSummary, error)
+	// Delete мягко удаляет запись; ErrDataNotFound, если записи нет или она уже удалена
 	Delete(ctx context.Context, userID, dataID string) error
-	GetSince(ctx context.Context, userID string, since time.Time) ([]*models.Data, error)
+	// GetChanges возвращает до limit записей с номером изменения (Seq) больше afterSeq в порядке номеров,
+	// включая удалённые (tombstones с заполненным DeletedAt)
+	GetChanges(ctx context.Context, userID string, afterSeq int64, limit int) ([]*models.Data, error)
+	// PurgeDeleted окончательно удаляет tombstones, удалённые раньше before
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 }
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/gophkeeper/gophkeeper/internal/models"
+)
+
+var (
+	// ErrKeyCheckMismatch — блок проверки мастер-пароля изменён другим клиентом
+	ErrKeyCheckMismatch = errors.New("key check has been changed")
+	// ErrVersionConflict — запись изменена после того, как клиент её прочитал
+	ErrVersionConflict = errors.New("data version conflict")
+	// ErrDataNotFound — записи нет или она уже удалена
+	ErrDataNotFound = errors.New("data not found")
+	// ErrBlobNotFound — в хранилище файлов нет содержимого с таким ключом
+	ErrBlobNotFound = errors.New("blob not found in store")
+)
+
+// VersionConflictError возвращается, когда версия записи на сервере не совпала с ожидаемой.
+// Current — актуальная копия записи (nil, если запись удалена).
+type VersionConflictError struct {
+	Current *models.Data
+}
+
+func (e *VersionConflictError) Error() string {
+	return ErrVersionConflict.Error()
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
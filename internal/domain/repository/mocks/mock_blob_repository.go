@@ -0,0 +1,146 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/gophkeeper/gophkeeper/internal/domain/repository (interfaces: BlobRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_blob_repository.go -package=mocks github.com/gophkeeper/gophkeeper/internal/domain/repository BlobRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/gophkeeper/gophkeeper/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBlobRepository is a mock of BlobRepository interface.
+type MockBlobRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlobRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockBlobRepositoryMockRecorder is the mock recorder for MockBlobRepository.
+type MockBlobRepositoryMockRecorder struct {
+	mock *MockBlobRepository
+}
+
+// NewMockBlobRepository creates a new mock instance.
+func NewMockBlobRepository(ctrl *gomock.Controller) *MockBlobRepository {
+	mock := &MockBlobRepository{ctrl: ctrl}
+	mock.recorder = &MockBlobRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlobRepository) EXPECT() *MockBlobRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimPurges mocks base method.
+func (m *MockBlobRepository) ClaimPurges(ctx context.Context, before time.Time) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPurges", ctx, before)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPurges indicates an expected call of ClaimPurges.
+func (mr *MockBlobRepositoryMockRecorder) ClaimPurges(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPurges", reflect.TypeOf((*MockBlobRepository)(nil).ClaimPurges), ctx, before)
+}
+
+// ContentPurging mocks base method.
+func (m *MockBlobRepository) ContentPurging(ctx context.Context, hash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContentPurging", ctx, hash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ContentPurging indicates an expected call of ContentPurging.
+func (mr *MockBlobRepositoryMockRecorder) ContentPurging(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContentPurging", reflect.TypeOf((*MockBlobRepository)(nil).ContentPurging), ctx, hash)
+}
+
+// Delete mocks base method.
+func (m *MockBlobRepository) Delete(ctx context.Context, blobID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, blobID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBlobRepositoryMockRecorder) Delete(ctx, blobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBlobRepository)(nil).Delete), ctx, blobID)
+}
+
+// FinishPurge mocks base method.
+func (m *MockBlobRepository) FinishPurge(ctx context.Context, hash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishPurge", ctx, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishPurge indicates an expected call of FinishPurge.
+func (mr *MockBlobRepositoryMockRecorder) FinishPurge(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishPurge", reflect.TypeOf((*MockBlobRepository)(nil).FinishPurge), ctx, hash)
+}
+
+// Get mocks base method.
+func (m *MockBlobRepository) Get(ctx context.Context, userID, blobID string) (*models.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID, blobID)
+	ret0, _ := ret[0].(*models.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockBlobRepositoryMockRecorder) Get(ctx, userID, blobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBlobRepository)(nil).Get), ctx, userID, blobID)
+}
+
+// ListUnreferenced mocks base method.
+func (m *MockBlobRepository) ListUnreferenced(ctx context.Context, before time.Time) ([]*models.Blob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnreferenced", ctx, before)
+	ret0, _ := ret[0].([]*models.Blob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnreferenced indicates an expected call of ListUnreferenced.
+func (mr *MockBlobRepositoryMockRecorder) ListUnreferenced(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnreferenced", reflect.TypeOf((*MockBlobRepository)(nil).ListUnreferenced), ctx, before)
+}
+
+// Reserve mocks base method.
+func (m *MockBlobRepository) Reserve(ctx context.Context, blob *models.Blob) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, blob)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockBlobRepositoryMockRecorder) Reserve(ctx, blob any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockBlobRepository)(nil).Reserve), ctx, blob)
+}
@@ -71,19 +71,19 @@ func (mr *MockDataRepositoryMockRecorder) Get(ctx, userID, dataID any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDataRepository)(nil).Get), ctx, userID, dataID)
 }
 
-// GetSince mocks base method.
-func (m *MockDataRepository) GetSince(ctx context.Context, userID string, since time.Time) ([]*models.Data, error) {
+// GetChanges mocks base method.
+func (m *MockDataRepository) GetChanges(ctx context.Context, userID string, afterSeq int64, limit int) ([]*models.Data, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSince", ctx, userID, since)
+	ret := m.ctrl.Call(m, "GetChanges", ctx, userID, afterSeq, limit)
 	ret0, _ := ret[0].([]*models.Data)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSince indicates an expected call of GetSince.
-func (mr *MockDataRepositoryMockRecorder) GetSince(ctx, userID, since any) *gomock.Call {
+// GetChanges indicates an expected call of GetChanges.
+func (mr *MockDataRepositoryMockRecorder) GetChanges(ctx, userID, afterSeq, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSince", reflect.TypeOf((*MockDataRepository)(nil).GetSince), ctx, userID, since)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChanges", reflect.TypeOf((*MockDataRepository)(nil).GetChanges), ctx, userID, afterSeq, limit)
 }
 
 // List mocks base method.
@@ -101,16 +101,46 @@ func (mr *MockDataRepositoryMockRecorder) List(ctx, userID, dataType any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDataRepository)(nil).List), ctx, userID, dataType)
 }
 
+// ListSummaries mocks base method.
+func (m *MockDataRepository) ListSummaries(ctx context.Context, userID string, q models.DataSummaryQuery) ([]*models.DataSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSummaries", ctx, userID, q)
+	ret0, _ := ret[0].([]*models.DataSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSummaries indicates an expected call of ListSummaries.
+func (mr *MockDataRepositoryMockRecorder) ListSummaries(ctx, userID, q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSummaries", reflect.TypeOf((*MockDataRepository)(nil).ListSummaries), ctx, userID, q)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockDataRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockDataRepositoryMockRecorder) PurgeDeleted(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockDataRepository)(nil).PurgeDeleted), ctx, before)
+}
+
 // Save mocks base method.
-func (m *MockDataRepository) Save(ctx context.Context, userID string, data *models.Data) error {
+func (m *MockDataRepository) Save(ctx context.Context, userID string, data *models.Data, baseVersion int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Save", ctx, userID, data)
+	ret := m.ctrl.Call(m, "Save", ctx, userID, data, baseVersion)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Save indicates an expected call of Save.
-func (mr *MockDataRepositoryMockRecorder) Save(ctx, userID, data any) *gomock.Call {
+func (mr *MockDataRepositoryMockRecorder) Save(ctx, userID, data, baseVersion any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockDataRepository)(nil).Save), ctx, userID, data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockDataRepository)(nil).Save), ctx, userID, data, baseVersion)
 }
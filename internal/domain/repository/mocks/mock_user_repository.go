@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	repository "github.com/gophkeeper/gophkeeper/internal/domain/repository"
 	models "github.com/gophkeeper/gophkeeper/internal/models"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,6 +42,21 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// ChangeCredentials mocks base method.
+func (m *MockUserRepository) ChangeCredentials(ctx context.Context, userID string, change repository.CredentialsChange) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeCredentials", ctx, userID, change)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeCredentials indicates an expected call of ChangeCredentials.
+func (mr *MockUserRepositoryMockRecorder) ChangeCredentials(ctx, userID, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeCredentials", reflect.TypeOf((*MockUserRepository)(nil).ChangeCredentials), ctx, userID, change)
+}
+
 // Create mocks base method.
 func (m *MockUserRepository) Create(ctx context.Context, login, passwordHash string) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -85,3 +101,18 @@ func (mr *MockUserRepositoryMockRecorder) GetByLogin(ctx, login any) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByLogin", reflect.TypeOf((*MockUserRepository)(nil).GetByLogin), ctx, login)
 }
+
+// SetKeyCheck mocks base method.
+func (m *MockUserRepository) SetKeyCheck(ctx context.Context, userID string, previous, keyCheck []byte) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetKeyCheck", ctx, userID, previous, keyCheck)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetKeyCheck indicates an expected call of SetKeyCheck.
+func (mr *MockUserRepositoryMockRecorder) SetKeyCheck(ctx, userID, previous, keyCheck any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKeyCheck", reflect.TypeOf((*MockUserRepository)(nil).SetKeyCheck), ctx, userID, previous, keyCheck)
+}
@@ -13,4 +13,21 @@ type UserRepository interface {
 	Create(ctx context.Context, login, passwordHash string) (*models.User, error)
 	GetByLogin(ctx context.Context, login string) (*models.User, error)
 	GetByID(ctx context.Context, userID string) (*models.User, error)
+	// SetKeyCheck заменяет блок проверки мастер-пароля, только если текущее значение равно previous.
+	// Возвращает false, если значение не совпало (блок уже задан или изменён другим клиентом).
+	SetKeyCheck(ctx context.Context, userID string, previous, keyCheck []byte) (bool, error)
+	// ChangeCredentials атомарно применяет смену пароля и отзывает выданные токены.
+	// Возвращает новое поколение токенов; ErrKeyCheckMismatch или ErrVersionConflict, если данные устарели.
+	ChangeCredentials(ctx context.Context, userID string, change CredentialsChange) (int64, error)
+}
+
+// CredentialsChange описывает смену пароля входа и/или мастер-пароля.
+// Пустые поля не изменяются.
+type CredentialsChange struct {
+	PasswordHash string
+	// KeyCheck заменяет блок проверки, только если текущее значение равно PreviousKeyCheck
+	KeyCheck         []byte
+	PreviousKeyCheck []byte
+	// Records — перешифрованные записи; Version каждой записи — версия, от которой клиент отталкивался
+	Records []*models.Data
 }
@@ -0,0 +1,2 @@
+-- Поколение токенов: увеличивается при смене пароля и отзывает выданные ранее refresh-токены
+ALTER TABLE users ADD COLUMN IF NOT EXISTS token_version BIGINT NOT NULL DEFAULT 0;
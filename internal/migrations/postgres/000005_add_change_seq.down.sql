@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_data_user_seq;
+ALTER TABLE data DROP COLUMN IF EXISTS seq;
+ALTER TABLE users DROP COLUMN IF EXISTS change_seq;
@@ -0,0 +1,14 @@
+-- Последовательность изменений: у пользователя — счётчик, у каждой записи — номер её последнего изменения.
+-- Курсор синхронизации хранит номер, поэтому изменения не теряются при совпадении временных меток.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS change_seq BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE data ADD COLUMN IF NOT EXISTS seq BIGINT NOT NULL DEFAULT 0;
+
+-- Нумеруем существующие записи в порядке изменения
+UPDATE data SET seq = numbered.seq
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY updated_at, id) AS seq FROM data
+) AS numbered
+WHERE data.id = numbered.id;
+UPDATE users SET change_seq = (SELECT COALESCE(MAX(seq), 0) FROM data WHERE data.user_id = users.id);
+
+CREATE INDEX IF NOT EXISTS idx_data_user_seq ON data(user_id, seq);
@@ -0,0 +1,5 @@
+-- Название и метаданные, зашифрованные клиентом, и непрозрачный токен названия для поиска
+ALTER TABLE data ADD COLUMN IF NOT EXISTS encrypted_meta BYTEA;
+ALTER TABLE data ADD COLUMN IF NOT EXISTS search_token VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_data_user_search_token ON data(user_id, search_token);
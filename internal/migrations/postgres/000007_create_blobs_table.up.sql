@@ -0,0 +1,14 @@
+-- Содержимое файлов, зашифрованное клиентом (PostgreSQL); записи BINARY ссылаются на него через blob_id
+CREATE TABLE IF NOT EXISTS blobs (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    content BYTEA NOT NULL,
+    size BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_blobs_user_id ON blobs(user_id);
+
+ALTER TABLE data ADD COLUMN IF NOT EXISTS blob_id VARCHAR(36) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_data_blob_id ON data(blob_id);
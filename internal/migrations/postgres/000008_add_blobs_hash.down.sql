@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_blobs_hash;
+ALTER TABLE blobs DROP COLUMN IF EXISTS hash;
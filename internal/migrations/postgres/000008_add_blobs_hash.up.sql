@@ -0,0 +1,4 @@
+-- Содержимое файлов переносится в хранилище файлов (BlobStore); в таблице остаётся ключ содержимого
+ALTER TABLE blobs ADD COLUMN IF NOT EXISTS hash VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_blobs_hash ON blobs(hash);
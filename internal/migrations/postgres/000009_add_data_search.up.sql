@@ -0,0 +1,25 @@
+-- Поиск записей на сервере (PostgreSQL): название в нижнем регистре, ключи метаданных и теги.
+-- name_lower сравнивается побайтово (COLLATE "C"), чтобы поиск по префиксу шёл диапазоном по индексу.
+-- У существующих записей name_lower остаётся NULL, пока сервер не заполнит индекс при старте.
+ALTER TABLE data ADD COLUMN IF NOT EXISTS name_lower TEXT COLLATE "C";
+
+CREATE INDEX IF NOT EXISTS idx_data_user_name_lower ON data(user_id, name_lower);
+CREATE INDEX IF NOT EXISTS idx_data_user_updated_at ON data(user_id, updated_at);
+
+CREATE TABLE IF NOT EXISTS data_meta_keys (
+    data_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    key TEXT NOT NULL,
+    PRIMARY KEY (data_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_meta_keys_user_key ON data_meta_keys(user_id, key);
+
+CREATE TABLE IF NOT EXISTS data_tags (
+    data_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (data_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_tags_user_tag ON data_tags(user_id, tag);
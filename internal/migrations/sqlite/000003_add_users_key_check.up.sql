@@ -0,0 +1,2 @@
+-- Блок проверки мастер-пароля (шифруется на клиенте, сервер хранит его как есть)
+ALTER TABLE users ADD COLUMN key_check BLOB;
@@ -0,0 +1,2 @@
+-- Поколение токенов: увеличивается при смене пароля и отзывает выданные ранее refresh-токены
+ALTER TABLE users ADD COLUMN token_version INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_data_user_seq;
+ALTER TABLE data DROP COLUMN seq;
+ALTER TABLE users DROP COLUMN change_seq;
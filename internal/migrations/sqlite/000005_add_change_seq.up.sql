@@ -0,0 +1,14 @@
+-- Последовательность изменений: у пользователя — счётчик, у каждой записи — номер её последнего изменения.
+-- Курсор синхронизации хранит номер, поэтому изменения не теряются при совпадении временных меток.
+ALTER TABLE users ADD COLUMN change_seq INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE data ADD COLUMN seq INTEGER NOT NULL DEFAULT 0;
+
+-- Нумеруем существующие записи в порядке изменения
+UPDATE data SET seq = (
+    SELECT COUNT(*) FROM data AS d
+    WHERE d.user_id = data.user_id
+      AND (d.updated_at < data.updated_at OR (d.updated_at = data.updated_at AND d.id <= data.id))
+);
+UPDATE users SET change_seq = (SELECT COALESCE(MAX(seq), 0) FROM data WHERE data.user_id = users.id);
+
+CREATE INDEX IF NOT EXISTS idx_data_user_seq ON data(user_id, seq);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_data_user_search_token;
+ALTER TABLE data DROP COLUMN search_token;
+ALTER TABLE data DROP COLUMN encrypted_meta;
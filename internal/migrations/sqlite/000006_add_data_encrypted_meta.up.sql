@@ -0,0 +1,5 @@
+-- Название и метаданные, зашифрованные клиентом, и непрозрачный токен названия для поиска
+ALTER TABLE data ADD COLUMN encrypted_meta BLOB;
+ALTER TABLE data ADD COLUMN search_token TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_data_user_search_token ON data(user_id, search_token);
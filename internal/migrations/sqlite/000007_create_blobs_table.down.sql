@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_data_blob_id;
+ALTER TABLE data DROP COLUMN blob_id;
+DROP TABLE IF EXISTS blobs;
@@ -0,0 +1,14 @@
+-- Содержимое файлов, зашифрованное клиентом (SQLite); записи BINARY ссылаются на него через blob_id
+CREATE TABLE IF NOT EXISTS blobs (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    content BLOB NOT NULL,
+    size INTEGER NOT NULL,
+    created_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_blobs_user_id ON blobs(user_id);
+
+ALTER TABLE data ADD COLUMN blob_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_data_blob_id ON data(blob_id);
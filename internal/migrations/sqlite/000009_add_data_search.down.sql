@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS data_tags;
+DROP TABLE IF EXISTS data_meta_keys;
+DROP INDEX IF EXISTS idx_data_user_updated_at;
+DROP INDEX IF EXISTS idx_data_user_name_lower;
+ALTER TABLE data DROP COLUMN name_lower;
@@ -0,0 +1,7 @@
+-- Пометки содержимого к удалению (SQLite): очистка сначала помечает содержимое, на которое больше
+-- не ссылается ни один файл, и удаляет его при следующем проходе, если его не загрузили снова
+CREATE TABLE IF NOT EXISTS blob_purges (
+    hash TEXT PRIMARY KEY,
+    deleting BOOLEAN NOT NULL DEFAULT FALSE,
+    marked_at DATETIME NOT NULL
+);
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Blob представляет файл, зашифрованный клиентом. Запись BINARY ссылается на него по ID, само содержимое
+// лежит в хранилище файлов (BlobStore) под ключом Hash.
+type Blob struct {
+	ID     string `gorm:"primaryKey;size:36" json:"id"`
+	UserID string `gorm:"size:36;not null;index" json:"user_id"`
+	Hash   string `gorm:"size:64;not null;default:'';index" json:"-"` // ключ содержимого в BlobStore (hex SHA-256)
+	// Content — содержимое файлов, загруженных до появления BlobStore (у них пустой Hash); у остальных пусто
+	Content   []byte    `gorm:"not null" json:"-"`
+	Size      int64     `gorm:"not null" json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BeforeCreate генерирует UUID для новых блобов
+func (b *Blob) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == "" {
+		b.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// TableName возвращает имя таблицы
+func (Blob) TableName() string {
+	return "blobs"
+}
+
+// BlobPurge — пометка содержимого к удалению из хранилища файлов. Очистка помечает содержимое,
+// на которое больше не ссылается ни один файл, и удаляет его при следующем проходе (Deleting — удаление
+// уже началось). Загрузка того же содержимого снимает пометку, пока удаление не началось.
+type BlobPurge struct {
+	Hash     string    `gorm:"primaryKey;size:64"`
+	Deleting bool      `gorm:"not null;default:false"`
+	MarkedAt time.Time `gorm:"not null"`
+}
+
+// TableName возвращает имя таблицы
+func (BlobPurge) TableName() string {
+	return "blob_purges"
+}
@@ -15,6 +15,8 @@ const (
 	DataTypeText          DataType = "text"
 	DataTypeBinary        DataType = "binary"
 	DataTypeBankCard      DataType = "bank_card"
+	DataTypeOTP           DataType = "otp"
+	DataTypeSSHKey        DataType = "ssh_key"
 )
 
 // Data представляет хранимые данные пользователя
@@ -23,9 +25,14 @@ type Data struct {
 	UserID        string         `gorm:"size:36;not null;index" json:"user_id"`
 	Type          DataType       `gorm:"size:50;not null;index" json:"type"`
 	Name          string         `gorm:"not null" json:"name"`
-	EncryptedData []byte         `gorm:"not null" json:"-"` // blob в SQLite, bytea в PostgreSQL
-	Metadata      string         `gorm:"type:text" json:"metadata"` // JSON строка для метаданных
+	NameLower     string         `json:"-"`                                          // название в нижнем регистре для поиска (заполняет storage)
+	EncryptedData []byte         `gorm:"not null" json:"-"`                          // blob в SQLite, bytea в PostgreSQL
+	Metadata      string         `gorm:"type:text" json:"metadata"`                  // JSON строка для метаданных
+	EncryptedMeta []byte         `json:"-"`                                          // название и метаданные, зашифрованные клиентом
+	SearchToken   string         `gorm:"size:64;not null;default:''" json:"-"`       // токен названия для поиска
+	BlobID        string         `gorm:"size:36;not null;default:''" json:"blob_id"` // содержимое файла записи BINARY (таблица blobs)
 	Version       int64          `gorm:"default:1" json:"version"`
+	Seq           int64          `gorm:"not null;default:0" json:"-"` // номер изменения в последовательности пользователя
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
@@ -44,8 +51,82 @@ func (Data) TableName() string {
 	return "data"
 }
 
+// MetadataKeyTags — ключ метаданных со списком тегов через запятую
+const MetadataKeyTags = "tags"
+
+// DataMetaKey — ключ метаданных записи для поиска по наличию ключа (в нижнем регистре)
+type DataMetaKey struct {
+	DataID string `gorm:"primaryKey;size:36"`
+	UserID string `gorm:"size:36;not null;index"`
+	Key    string `gorm:"primaryKey"`
+}
+
+// TableName возвращает имя таблицы
+func (DataMetaKey) TableName() string {
+	return "data_meta_keys"
+}
+
+// DataTag — тег записи из метаданных tags (в нижнем регистре)
+type DataTag struct {
+	DataID string `gorm:"primaryKey;size:36"`
+	UserID string `gorm:"size:36;not null;index"`
+	Tag    string `gorm:"primaryKey"`
+}
+
+// TableName возвращает имя таблицы
+func (DataTag) TableName() string {
+	return "data_tags"
+}
+
 // MetadataItem представляет элемент метаданных
 type MetadataItem struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
 }
+
+// DataSort — поле сортировки списка записей
+type DataSort string
+
+const (
+	DataSortUpdatedAt DataSort = "updated_at"
+	DataSortName      DataSort = "name"
+	DataSortCreatedAt DataSort = "created_at"
+)
+
+// DataFilter — условия поиска записей; заданные условия объединяются через И.
+// Строки сравниваются без учёта регистра.
+type DataFilter struct {
+	Types        []DataType // пусто — все типы
+	NamePrefix   string
+	NameContains string
+	// SearchToken — токен зашифрованного названия (точное совпадение)
+	SearchToken string
+	// MetadataKeys — ключи метаданных, которые должны быть у записи
+	MetadataKeys []string
+	// Tags — теги, которые должны быть у записи
+	Tags []string
+	// UpdatedSince — записи, изменённые не раньше этого момента (нулевое — без ограничения)
+	UpdatedSince time.Time
+}
+
+// DataSummaryQuery — параметры выборки страницы кратких описаний записей
+type DataSummaryQuery struct {
+	Filter DataFilter
+	Sort   DataSort
+	Desc   bool
+	Offset int
+	Limit  int
+}
+
+// DataSummary — краткое описание записи без зашифрованного содержимого
+type DataSummary struct {
+	ID            string
+	Type          DataType
+	Name          string
+	EncryptedMeta []byte
+	BlobID        string
+	Version       int64
+	Size          int64 // размер зашифрованного содержимого
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
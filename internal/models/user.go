@@ -12,6 +12,9 @@ type User struct {
 	ID           string         `gorm:"primaryKey;size:36" json:"id"`
 	Login        string         `gorm:"uniqueIndex;not null" json:"login"`
 	PasswordHash string         `gorm:"not null" json:"-"`
+	KeyCheck     []byte         `json:"-"`                           // блок проверки мастер-пароля, формируется клиентом
+	TokenVersion int64          `gorm:"not null;default:0" json:"-"` // поколение токенов, растёт при смене пароля
+	ChangeSeq    int64          `gorm:"not null;default:0" json:"-"` // номер последнего изменения данных пользователя
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
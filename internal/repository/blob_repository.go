@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	domainrepo "github.com/gophkeeper/gophkeeper/internal/domain/repository"
+	"github.com/gophkeeper/gophkeeper/internal/models"
+	"github.com/gophkeeper/gophkeeper/internal/storage"
+)
+
+// blobRepo реализует domain/repository.BlobRepository
+type blobRepo struct {
+	storage *storage.Storage
+}
+
+// NewBlobRepository создаёт репозиторий файлов
+func NewBlobRepository(storage *storage.Storage) domainrepo.BlobRepository {
+	return &blobRepo{storage: storage}
+}
+
+// Reserve сохраняет запись о файле до загрузки содержимого
+func (r *blobRepo) Reserve(ctx context.Context, blob *models.Blob) (bool, error) {
+	return r.storage.ReserveBlob(blob)
+}
+
+// ContentPurging сообщает, удаляется ли содержимое
+func (r *blobRepo) ContentPurging(ctx context.Context, hash string) (bool, error) {
+	return r.storage.BlobPurging(hash)
+}
+
+// Get возвращает запись о файле по ID
+func (r *blobRepo) Get(ctx context.Context, userID, blobID string) (*models.Blob, error) {
+	return r.storage.GetBlob(userID, blobID)
+}
+
+// ListUnreferenced возвращает файлы, на которые не ссылаются записи
+func (r *blobRepo) ListUnreferenced(ctx context.Context, before time.Time) ([]*models.Blob, error) {
+	return r.storage.ListUnreferencedBlobs(before)
+}
+
+// Delete удаляет запись о файле
+func (r *blobRepo) Delete(ctx context.Context, blobID string) error {
+	return r.storage.DeleteBlob(blobID)
+}
+
+// ClaimPurges начинает удаление помеченного содержимого
+func (r *blobRepo) ClaimPurges(ctx context.Context, before time.Time) ([]string, error) {
+	return r.storage.ClaimBlobPurges(before)
+}
+
+// FinishPurge снимает пометку удалённого содержимого
+func (r *blobRepo) FinishPurge(ctx context.Context, hash string) error {
+	return r.storage.FinishBlobPurge(hash)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	domainrepo "github.com/gophkeeper/gophkeeper/internal/domain/repository"
@@ -19,9 +20,13 @@ func NewDataRepository(storage *storage.Storage) domainrepo.DataRepository {
 	return &dataRepo{storage: storage}
 }
 
-// Save сохраняет или обновляет данные
-func (r *dataRepo) Save(ctx context.Context, userID string, data *models.Data) error {
-	return r.storage.SaveData(userID, data)
+// Save сохраняет или обновляет данные с проверкой версии
+func (r *dataRepo) Save(ctx context.Context, userID string, data *models.Data, baseVersion int64) error {
+	current, err := r.storage.SaveData(userID, data, baseVersion)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		return &domainrepo.VersionConflictError{Current: current}
+	}
+	return err
 }
 
 // Get возвращает данные по ID
@@ -34,12 +39,26 @@ func (r *dataRepo) List(ctx context.Context, userID string, dataType models.Data
 	return r.storage.ListData(userID, dataType)
 }
 
-// Delete удаляет данные
+// ListSummaries возвращает страницу кратких описаний записей
+func (r *dataRepo) ListSummaries(ctx context.Context, userID string, q models.DataSummaryQuery) ([]*models.DataSummary, error) {
+	return r.storage.ListDataSummaries(userID, q)
+}
+
+// Delete удаляет данные; ErrDataNotFound, если записи нет или она уже удалена
 func (r *dataRepo) Delete(ctx context.Context, userID, dataID string) error {
-	return r.storage.DeleteData(userID, dataID)
+	err := r.storage.DeleteData(userID, dataID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return domainrepo.ErrDataNotFound
+	}
+	return err
+}
+
+// GetChanges возвращает страницу изменений после номера afterSeq, включая удалённые записи
+func (r *dataRepo) GetChanges(ctx context.Context, userID string, afterSeq int64, limit int) ([]*models.Data, error) {
+	return r.storage.GetDataChanges(userID, afterSeq, limit)
 }
 
-// GetSince возвращает данные, изменённые после указанного времени
-func (r *dataRepo) GetSince(ctx context.Context, userID string, since time.Time) ([]*models.Data, error) {
-	return r.storage.GetDataSince(userID, since)
+// PurgeDeleted окончательно удаляет tombstones старше before
+func (r *dataRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return r.storage.PurgeDeletedData(before)
 }
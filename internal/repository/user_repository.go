@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	domainrepo "github.com/gophkeeper/gophkeeper/internal/domain/repository"
 	"github.com/gophkeeper/gophkeeper/internal/models"
@@ -32,3 +33,20 @@ func (r *userRepo) GetByLogin(ctx context.Context, login string) (*models.User,
 func (r *userRepo) GetByID(ctx context.Context, userID string) (*models.User, error) {
 	return r.storage.GetUserByID(userID)
 }
+
+// SetKeyCheck заменяет блок проверки мастер-пароля (compare-and-swap по previous)
+func (r *userRepo) SetKeyCheck(ctx context.Context, userID string, previous, keyCheck []byte) (bool, error) {
+	return r.storage.SetUserKeyCheck(userID, previous, keyCheck)
+}
+
+// ChangeCredentials меняет пароль, блок проверки и перешифрованные записи в одной транзакции
+func (r *userRepo) ChangeCredentials(ctx context.Context, userID string, change domainrepo.CredentialsChange) (int64, error) {
+	version, err := r.storage.ChangeUserCredentials(userID, change.PasswordHash, change.PreviousKeyCheck, change.KeyCheck, change.Records)
+	switch {
+	case errors.Is(err, storage.ErrKeyCheckMismatch):
+		return 0, domainrepo.ErrKeyCheckMismatch
+	case errors.Is(err, storage.ErrVersionConflict):
+		return 0, domainrepo.ErrVersionConflict
+	}
+	return version, err
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/gophkeeper/gophkeeper/proto"
+	"github.com/gophkeeper/gophkeeper/internal/models"
 	"github.com/gophkeeper/gophkeeper/internal/usecase/auth"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -99,6 +100,7 @@ func (s *AuthService) Login(ctx context.Context, req *proto.LoginRequest) (*prot
 		AccessToken:  out.AccessToken,
 		RefreshToken: out.RefreshToken,
 		ExpiresIn:    out.ExpiresIn,
+		KeyCheck:     out.KeyCheck,
 	}, nil
 }
 
@@ -128,3 +130,113 @@ func (s *AuthService) RefreshToken(ctx context.Context, req *proto.RefreshTokenR
 		ExpiresIn:    out.ExpiresIn,
 	}, nil
 }
+
+// SetKeyCheck сохраняет блок проверки мастер-пароля текущего пользователя
+func (s *AuthService) SetKeyCheck(ctx context.Context, req *proto.SetKeyCheckRequest) (*proto.SetKeyCheckResponse, error) {
+	userID, err := GetUserIDFromContext(ctx)
+	if err != nil {
+		return &proto.SetKeyCheckResponse{
+			Success: false,
+			Message: "authentication required",
+		}, err
+	}
+
+	if req == nil {
+		return &proto.SetKeyCheckResponse{Success: false, Message: "request is required"}, status.Error(codes.InvalidArgument, "request is required")
+	}
+
+	err = s.authUC.SetKeyCheck(ctx, auth.SetKeyCheckInput{
+		UserID:   userID,
+		KeyCheck: req.KeyCheck,
+		Previous: req.PreviousKeyCheck,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrKeyCheckRequired):
+			return &proto.SetKeyCheckResponse{
+				Success: false,
+				Message: "key check is required",
+			}, status.Error(codes.InvalidArgument, "key check is required")
+		case errors.Is(err, auth.ErrKeyCheckMismatch):
+			return &proto.SetKeyCheckResponse{
+				Success: false,
+				Message: "key check has been changed",
+			}, status.Error(codes.FailedPrecondition, "key check has been changed")
+		default:
+			return &proto.SetKeyCheckResponse{
+				Success: false,
+				Message: "internal error",
+			}, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &proto.SetKeyCheckResponse{
+		Success: true,
+		Message: "key check saved",
+	}, nil
+}
+
+// ChangePassword меняет пароль входа и/или мастер-пароль текущего пользователя
+func (s *AuthService) ChangePassword(ctx context.Context, req *proto.ChangePasswordRequest) (*proto.ChangePasswordResponse, error) {
+	userID, err := GetUserIDFromContext(ctx)
+	if err != nil {
+		return &proto.ChangePasswordResponse{
+			Success: false,
+			Message: "authentication required",
+		}, err
+	}
+
+	if req == nil {
+		return &proto.ChangePasswordResponse{Success: false, Message: "request is required"}, status.Error(codes.InvalidArgument, "request is required")
+	}
+
+	records := make([]*models.Data, 0, len(req.Data))
+	for _, d := range req.Data {
+		records = append(records, &models.Data{
+			ID:            d.Id,
+			EncryptedData: d.EncryptedData,
+			Version:       d.Version,
+		})
+	}
+
+	out, err := s.authUC.ChangePassword(ctx, auth.ChangePasswordInput{
+		UserID:           userID,
+		OldPassword:      req.OldPassword,
+		NewPassword:      req.NewPassword,
+		KeyCheck:         req.KeyCheck,
+		PreviousKeyCheck: req.PreviousKeyCheck,
+		Records:          records,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrPasswordRequired), errors.Is(err, auth.ErrNothingToChange):
+			return &proto.ChangePasswordResponse{
+				Success: false,
+				Message: err.Error(),
+			}, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, auth.ErrInvalidCredentials):
+			return &proto.ChangePasswordResponse{
+				Success: false,
+				Message: "invalid password",
+			}, status.Error(codes.PermissionDenied, "invalid password")
+		case errors.Is(err, auth.ErrKeyCheckMismatch), errors.Is(err, auth.ErrRecordsChanged):
+			return &proto.ChangePasswordResponse{
+				Success: false,
+				Message: err.Error(),
+			}, status.Error(codes.Aborted, err.Error())
+		default:
+			return &proto.ChangePasswordResponse{
+				Success: false,
+				Message: "internal error",
+			}, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &proto.ChangePasswordResponse{
+		Success:      true,
+		Message:      "password changed",
+		AccessToken:  out.AccessToken,
+		RefreshToken: out.RefreshToken,
+		ExpiresIn:    out.ExpiresIn,
+	}, nil
+}
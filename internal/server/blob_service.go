@@ -0,0 +1,103 @@
+package server
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gophkeeper/gophkeeper/internal/usecase/blob"
+	"github.com/gophkeeper/gophkeeper/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// blobChunkSize — размер частей, которыми сервер отдаёт содержимое файла
+const blobChunkSize = 64 << 10
+
+// UploadBlob принимает зашифрованное содержимое файла частями и передаёт его в хранилище файлов
+func (s *DataService) UploadBlob(stream grpc.ClientStreamingServer[proto.BlobChunk, proto.UploadBlobResponse]) error {
+	userID, err := GetUserIDFromContext(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	out, err := s.blobUC.UploadBlob(stream.Context(), blob.UploadBlobInput{
+		UserID:  userID,
+		Content: &blobStreamReader{stream: stream},
+	})
+	if err != nil {
+		if errors.Is(err, blob.ErrBlobTooLarge) {
+			return status.Error(codes.ResourceExhausted, "blob is too large")
+		}
+		if st, ok := status.FromError(err); ok {
+			// Поток прерван на стороне клиента
+			return st.Err()
+		}
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	return stream.SendAndClose(&proto.UploadBlobResponse{
+		Success: true,
+		Message: "blob uploaded successfully",
+		BlobId:  out.BlobID,
+		Size:    out.Size,
+	})
+}
+
+// DownloadBlob отдаёт зашифрованное содержимое файла частями по blobChunkSize
+func (s *DataService) DownloadBlob(req *proto.DownloadBlobRequest, stream grpc.ServerStreamingServer[proto.BlobChunk]) error {
+	userID, err := GetUserIDFromContext(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	out, err := s.blobUC.DownloadBlob(stream.Context(), blob.DownloadBlobInput{
+		UserID: userID,
+		BlobID: req.BlobId,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, blob.ErrBlobIDRequired):
+			return status.Error(codes.InvalidArgument, "blob_id is required")
+		case errors.Is(err, blob.ErrBlobNotFound):
+			return status.Error(codes.NotFound, "blob not found")
+		}
+		return status.Error(codes.Internal, "internal error")
+	}
+	defer out.Content.Close()
+
+	buf := make([]byte, blobChunkSize)
+	for {
+		n, err := out.Content.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&proto.BlobChunk{Content: buf[:n]}); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return status.Error(codes.Internal, "internal error")
+		}
+	}
+}
+
+// blobStreamReader читает содержимое из потока частей UploadBlob как io.Reader
+type blobStreamReader struct {
+	stream grpc.ClientStreamingServer[proto.BlobChunk, proto.UploadBlobResponse]
+	buf    []byte
+}
+
+func (r *blobStreamReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = chunk.Content
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
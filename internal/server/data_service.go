@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/gophkeeper/gophkeeper/internal/models"
+	"github.com/gophkeeper/gophkeeper/internal/usecase/blob"
 	"github.com/gophkeeper/gophkeeper/internal/usecase/data"
 	"github.com/gophkeeper/gophkeeper/proto"
 	"google.golang.org/grpc/codes"
@@ -20,45 +21,43 @@ import (
 type DataService struct {
 	proto.UnimplementedDataServiceServer
 	dataUC *data.DataUseCase
+	blobUC *blob.BlobUseCase
 }
 
 // NewDataService создаёт новый сервис данных
-func NewDataService(dataUC *data.DataUseCase) *DataService {
+func NewDataService(dataUC *data.DataUseCase, blobUC *blob.BlobUseCase) *DataService {
 	return &DataService{
 		dataUC: dataUC,
+		blobUC: blobUC,
 	}
 }
 
+// dataTypes сопоставляет типы записей в API и в хранилище; новый тип добавляется одной строкой
+var dataTypes = map[proto.DataType]models.DataType{
+	proto.DataType_LOGIN_PASSWORD: models.DataTypeLoginPassword,
+	proto.DataType_TEXT:           models.DataTypeText,
+	proto.DataType_BINARY:         models.DataTypeBinary,
+	proto.DataType_BANK_CARD:      models.DataTypeBankCard,
+	proto.DataType_OTP:            models.DataTypeOTP,
+	proto.DataType_SSH_KEY:        models.DataTypeSSHKey,
+}
+
 // convertProtoDataType конвертирует proto DataType в models.DataType
 func convertProtoDataType(protoType proto.DataType) models.DataType {
-	switch protoType {
-	case proto.DataType_LOGIN_PASSWORD:
-		return models.DataTypeLoginPassword
-	case proto.DataType_TEXT:
-		return models.DataTypeText
-	case proto.DataType_BINARY:
-		return models.DataTypeBinary
-	case proto.DataType_BANK_CARD:
-		return models.DataTypeBankCard
-	default:
-		return models.DataTypeText
+	if t, ok := dataTypes[protoType]; ok {
+		return t
 	}
+	return models.DataTypeText
 }
 
 // convertModelsDataType конвертирует models.DataType в proto.DataType
 func convertModelsDataType(modelType models.DataType) proto.DataType {
-	switch modelType {
-	case models.DataTypeLoginPassword:
-		return proto.DataType_LOGIN_PASSWORD
-	case models.DataTypeText:
-		return proto.DataType_TEXT
-	case models.DataTypeBinary:
-		return proto.DataType_BINARY
-	case models.DataTypeBankCard:
-		return proto.DataType_BANK_CARD
-	default:
-		return proto.DataType_UNKNOWN
+	for protoType, t := range dataTypes {
+		if t == modelType {
+			return protoType
+		}
 	}
+	return proto.DataType_UNKNOWN
 }
 
 // convertProtoDataToModel конвертирует proto.Data в models.Data
@@ -93,6 +92,9 @@ func convertProtoDataToModel(protoData *proto.Data, userID string) (*models.Data
 		Name:          protoData.Name,
 		EncryptedData: encryptedData,
 		Metadata:      metadataJSON,
+		EncryptedMeta: protoData.EncryptedMeta,
+		SearchToken:   protoData.SearchToken,
+		BlobID:        protoData.BlobId,
 		Version:       protoData.Version,
 		CreatedAt:     createdAt,
 		UpdatedAt:     updatedAt,
@@ -115,6 +117,9 @@ func convertModelDataToProto(modelData *models.Data) (*proto.Data, error) {
 		Name:          modelData.Name,
 		EncryptedData: modelData.EncryptedData,
 		Metadata:      metadataItems,
+		EncryptedMeta: modelData.EncryptedMeta,
+		SearchToken:   modelData.SearchToken,
+		BlobId:        modelData.BlobID,
 		CreatedAt:     modelData.CreatedAt.Unix(),
 		UpdatedAt:     modelData.UpdatedAt.Unix(),
 		Version:       modelData.Version,
@@ -147,6 +152,51 @@ func modelDataSliceToProtoSeq(items []*models.Data) iter.Seq[*proto.Data] {
 	}
 }
 
+// convertProtoSort конвертирует proto DataSortField в models.DataSort
+func convertProtoSort(sort proto.DataSortField) models.DataSort {
+	switch sort {
+	case proto.DataSortField_SORT_NAME:
+		return models.DataSortName
+	case proto.DataSortField_SORT_CREATED_AT:
+		return models.DataSortCreatedAt
+	default:
+		return models.DataSortUpdatedAt
+	}
+}
+
+// summariesToProtoSeq возвращает итератор по []*models.DataSummary → *proto.DataSummary
+func summariesToProtoSeq(items []*models.DataSummary) iter.Seq[*proto.DataSummary] {
+	return func(yield func(*proto.DataSummary) bool) {
+		for _, d := range items {
+			summary := &proto.DataSummary{
+				Id:            d.ID,
+				Type:          convertModelsDataType(d.Type),
+				Name:          d.Name,
+				CreatedAt:     d.CreatedAt.Unix(),
+				UpdatedAt:     d.UpdatedAt.Unix(),
+				Version:       d.Version,
+				Size:          d.Size,
+				EncryptedMeta: d.EncryptedMeta,
+				BlobId:        d.BlobID,
+			}
+			if !yield(summary) {
+				return
+			}
+		}
+	}
+}
+
+// tombstonesToProtoSeq возвращает итератор по удалённым []*models.Data → *proto.Tombstone
+func tombstonesToProtoSeq(items []*models.Data) iter.Seq[*proto.Tombstone] {
+	return func(yield func(*proto.Tombstone) bool) {
+		for _, d := range items {
+			if !yield(&proto.Tombstone{Id: d.ID, DeletedAt: d.DeletedAt.Time.Unix(), Version: d.Version}) {
+				return
+			}
+		}
+	}
+}
+
 // SaveData сохраняет данные пользователя
 func (s *DataService) SaveData(ctx context.Context, req *proto.SaveDataRequest) (*proto.SaveDataResponse, error) {
 	userID, err := GetUserIDFromContext(ctx)
@@ -173,10 +223,18 @@ func (s *DataService) SaveData(ctx context.Context, req *proto.SaveDataRequest)
 	}
 
 	out, err := s.dataUC.SaveData(ctx, data.SaveDataInput{
-		UserID: userID,
-		Data:   modelData,
+		UserID:      userID,
+		Data:        modelData,
+		BaseVersion: req.BaseVersion,
 	})
 	if err != nil {
+		var conflict *data.ConflictError
+		if errors.As(err, &conflict) {
+			return &proto.SaveDataResponse{
+				Success: false,
+				Message: "data has been modified by another client",
+			}, conflictStatus(conflict.Current)
+		}
 		return &proto.SaveDataResponse{
 			Success: false,
 			Message: fmt.Sprintf("error saving data: %v", err),
@@ -191,6 +249,22 @@ func (s *DataService) SaveData(ctx context.Context, req *proto.SaveDataRequest)
 	}, nil
 }
 
+// conflictStatus формирует статус ABORTED с актуальной копией записи в деталях
+func conflictStatus(current *models.Data) error {
+	st := status.New(codes.Aborted, "data version conflict")
+	if current == nil {
+		return st.Err()
+	}
+	protoData, err := convertModelDataToProto(current)
+	if err != nil {
+		return st.Err()
+	}
+	if withDetails, err := st.WithDetails(protoData); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
 // GetData получает данные по ID
 func (s *DataService) GetData(ctx context.Context, req *proto.GetDataRequest) (*proto.GetDataResponse, error) {
 	userID, err := GetUserIDFromContext(ctx)
@@ -275,6 +349,105 @@ func (s *DataService) ListData(ctx context.Context, req *proto.ListDataRequest)
 	}, nil
 }
 
+// ListDataSummaries возвращает страницу кратких описаний записей без зашифрованного содержимого
+func (s *DataService) ListDataSummaries(ctx context.Context, req *proto.ListDataSummariesRequest) (*proto.ListDataSummariesResponse, error) {
+	userID, err := GetUserIDFromContext(ctx)
+	if err != nil {
+		return &proto.ListDataSummariesResponse{
+			Success: false,
+			Message: "authentication required",
+		}, err
+	}
+
+	var dataType models.DataType
+	if req.Type != proto.DataType_UNKNOWN {
+		dataType = convertProtoDataType(req.Type)
+	}
+
+	out, err := s.dataUC.ListDataSummaries(ctx, data.ListDataSummariesInput{
+		UserID:     userID,
+		DataType:   dataType,
+		Sort:       convertProtoSort(req.Sort),
+		Descending: req.Descending,
+		PageSize:   int(req.PageSize),
+		PageToken:  req.PageToken,
+	})
+	if err != nil {
+		if errors.Is(err, data.ErrInvalidPageToken) {
+			return &proto.ListDataSummariesResponse{
+				Success: false,
+				Message: "invalid page token",
+			}, status.Error(codes.InvalidArgument, "invalid page token")
+		}
+		return &proto.ListDataSummariesResponse{
+			Success: false,
+			Message: fmt.Sprintf("error listing data: %v", err),
+		}, status.Error(codes.Internal, "internal error")
+	}
+
+	return &proto.ListDataSummariesResponse{
+		Success:       true,
+		Message:       "data listed successfully",
+		Summaries:     slices.Collect(summariesToProtoSeq(out.Items)),
+		NextPageToken: out.NextPageToken,
+	}, nil
+}
+
+// SearchData возвращает страницу кратких описаний записей, подходящих под условия поиска
+func (s *DataService) SearchData(ctx context.Context, req *proto.SearchDataRequest) (*proto.SearchDataResponse, error) {
+	userID, err := GetUserIDFromContext(ctx)
+	if err != nil {
+		return &proto.SearchDataResponse{
+			Success: false,
+			Message: "authentication required",
+		}, err
+	}
+
+	filter := models.DataFilter{
+		NamePrefix:   req.NamePrefix,
+		NameContains: req.NameContains,
+		SearchToken:  req.SearchToken,
+		MetadataKeys: req.MetadataKeys,
+		Tags:         req.Tags,
+	}
+	for _, t := range req.Types {
+		if t != proto.DataType_UNKNOWN {
+			filter.Types = append(filter.Types, convertProtoDataType(t))
+		}
+	}
+	if req.UpdatedSince > 0 {
+		filter.UpdatedSince = time.Unix(req.UpdatedSince, 0)
+	}
+
+	out, err := s.dataUC.SearchData(ctx, data.SearchDataInput{
+		UserID:     userID,
+		Filter:     filter,
+		Sort:       convertProtoSort(req.Sort),
+		Descending: req.Descending,
+		PageSize:   int(req.PageSize),
+		PageToken:  req.PageToken,
+	})
+	if err != nil {
+		if errors.Is(err, data.ErrInvalidPageToken) {
+			return &proto.SearchDataResponse{
+				Success: false,
+				Message: "invalid page token",
+			}, status.Error(codes.InvalidArgument, "invalid page token")
+		}
+		return &proto.SearchDataResponse{
+			Success: false,
+			Message: fmt.Sprintf("error searching data: %v", err),
+		}, status.Error(codes.Internal, "internal error")
+	}
+
+	return &proto.SearchDataResponse{
+		Success:       true,
+		Message:       "data searched successfully",
+		Summaries:     slices.Collect(summariesToProtoSeq(out.Items)),
+		NextPageToken: out.NextPageToken,
+	}, nil
+}
+
 // DeleteData удаляет данные
 func (s *DataService) DeleteData(ctx context.Context, req *proto.DeleteDataRequest) (*proto.DeleteDataResponse, error) {
 	userID, err := GetUserIDFromContext(ctx)
@@ -296,6 +469,12 @@ func (s *DataService) DeleteData(ctx context.Context, req *proto.DeleteDataReque
 		UserID: userID,
 		DataID: req.DataId,
 	}); err != nil {
+		if errors.Is(err, data.ErrDataNotFound) {
+			return &proto.DeleteDataResponse{
+				Success: false,
+				Message: "data not found",
+			}, status.Error(codes.NotFound, "data not found")
+		}
 		return &proto.DeleteDataResponse{
 			Success: false,
 			Message: "error deleting data",
@@ -318,16 +497,18 @@ func (s *DataService) SyncData(ctx context.Context, req *proto.SyncDataRequest)
 		}, err
 	}
 
-	var since time.Time
-	if req.LastSyncTime > 0 {
-		since = time.Unix(req.LastSyncTime, 0)
-	}
-
 	out, err := s.dataUC.SyncData(ctx, data.SyncDataInput{
-		UserID:       userID,
-		LastSyncTime: since,
+		UserID:   userID,
+		Cursor:   req.Cursor,
+		PageSize: int(req.PageSize),
 	})
 	if err != nil {
+		if errors.Is(err, data.ErrInvalidCursor) {
+			return &proto.SyncDataResponse{
+				Success: false,
+				Message: "invalid sync cursor",
+			}, status.Error(codes.InvalidArgument, "invalid sync cursor")
+		}
 		return &proto.SyncDataResponse{
 			Success: false,
 			Message: fmt.Sprintf("error syncing data: %v", err),
@@ -335,11 +516,15 @@ func (s *DataService) SyncData(ctx context.Context, req *proto.SyncDataRequest)
 	}
 
 	protoDataList := slices.Collect(modelDataSliceToProtoSeq(out.Items))
+	tombstones := slices.Collect(tombstonesToProtoSeq(out.Deleted))
 
 	return &proto.SyncDataResponse{
-		Success:  true,
-		Message:  "data synced successfully",
-		Data:     protoDataList,
-		SyncTime: out.SyncTime.Unix(),
+		Success:    true,
+		Message:    "data synced successfully",
+		Data:       protoDataList,
+		Deleted:    tombstones,
+		FullResync: out.FullResync,
+		NextCursor: out.NextCursor,
+		HasMore:    out.HasMore,
 	}, nil
 }
@@ -38,11 +38,60 @@ func LoggingInterceptor(ctx context.Context, req interface{}, info *grpc.UnarySe
 
 // AuthInterceptor перехватывает запросы, проверяет JWT и кладёт userID в контекст.
 func AuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// LoggingStreamInterceptor логирует потоковые вызовы так же, как LoggingInterceptor — унарные.
+func LoggingStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	duration := time.Since(start)
+
+	if err != nil {
+		if st, ok := status.FromError(err); ok {
+			log.Printf("grpc stream=%s duration=%s code=%s msg=%s",
+				info.FullMethod, duration, st.Code(), st.Message())
+		} else {
+			log.Printf("grpc stream=%s duration=%s error=%v", info.FullMethod, duration, err)
+		}
+		return err
+	}
+
+	log.Printf("grpc stream=%s duration=%s code=OK", info.FullMethod, duration)
+	return nil
+}
+
+// AuthStreamInterceptor проверяет JWT потоковых вызовов и кладёт userID в контекст потока.
+func AuthStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticatedStream подменяет контекст потока контекстом с userID
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate проверяет токен из метаданных запроса и возвращает контекст с userID.
+// Методы входа и регистрации пропускаются без проверки.
+func authenticate(ctx context.Context, method string) (context.Context, error) {
 	// Пропускаем аутентификацию для методов AuthService
-	if info.FullMethod == "/gophkeeper.AuthService/Register" ||
-		info.FullMethod == "/gophkeeper.AuthService/Login" ||
-		info.FullMethod == "/gophkeeper.AuthService/RefreshToken" {
-		return handler(ctx, req)
+	if method == "/gophkeeper.AuthService/Register" ||
+		method == "/gophkeeper.AuthService/Login" ||
+		method == "/gophkeeper.AuthService/RefreshToken" {
+		return ctx, nil
 	}
 
 	// Для остальных методов проверяем токен и извлекаем userID
@@ -66,8 +115,7 @@ func AuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServe
 		return nil, status.Error(codes.Unauthenticated, "invalid token")
 	}
 
-	ctx = context.WithValue(ctx, userIDContextKey, claims.UserID)
-	return handler(ctx, req)
+	return context.WithValue(ctx, userIDContextKey, claims.UserID), nil
 }
 
 // GetUserIDFromContext возвращает user ID, записанный в контекст AuthInterceptor.
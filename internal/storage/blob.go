@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gophkeeper/gophkeeper/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReserveBlob сохраняет запись о файле до загрузки его содержимого и снимает пометку содержимого
+// к удалению; ID генерируется, если не задан. purging = true, если удаление содержимого уже началось:
+// загружать его нужно после того, как BlobPurging вернёт false.
+func (s *Storage) ReserveBlob(blob *models.Blob) (purging bool, err error) {
+	// NOT NULL: пустой слайс вместо nil для content
+	if blob.Content == nil {
+		blob.Content = []byte{}
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(blob).Error; err != nil {
+			return err
+		}
+		if blob.Hash == "" {
+			return nil
+		}
+		// Удаление пометки и пометка удаления в ClaimBlobPurges меняют одну строку,
+		// поэтому одна из транзакций видит результат другой
+		if err := tx.Where("hash = ? AND deleting = ?", blob.Hash, false).Delete(&models.BlobPurge{}).Error; err != nil {
+			return err
+		}
+		purging, err = blobPurging(tx, blob.Hash)
+		return err
+	})
+	return purging, err
+}
+
+// BlobPurging сообщает, удаляется ли содержимое hash из хранилища файлов
+func (s *Storage) BlobPurging(hash string) (bool, error) {
+	return blobPurging(s.db, hash)
+}
+
+func blobPurging(tx *gorm.DB, hash string) (bool, error) {
+	var count int64
+	if err := tx.Model(&models.BlobPurge{}).Where("hash = ? AND deleting = ?", hash, true).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetBlob получает запись о файле по ID; nil, если её нет
+func (s *Storage) GetBlob(userID, blobID string) (*models.Blob, error) {
+	var blob models.Blob
+	if err := s.db.Where("id = ? AND user_id = ?", blobID, userID).First(&blob).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &blob, nil
+}
+
+// ListUnreferencedBlobs возвращает файлы, загруженные раньше before, на которые не ссылается ни одна
+// неудалённая запись владельца: заменённые при редактировании, оставшиеся от удалённых записей
+// и загруженные без последующего сохранения записи. Содержимое старых файлов не загружается.
+func (s *Storage) ListUnreferencedBlobs(before time.Time) ([]*models.Blob, error) {
+	var blobs []*models.Blob
+	if err := s.db.
+		Omit("content").
+		Where("created_at < ?", before).
+		Where("NOT EXISTS (SELECT 1 FROM data WHERE data.blob_id = blobs.id AND data.user_id = blobs.user_id AND data.deleted_at IS NULL)").
+		Find(&blobs).Error; err != nil {
+		return nil, err
+	}
+	return blobs, nil
+}
+
+// DeleteBlob удаляет запись о файле. Если на его содержимое больше не ссылается ни один файл,
+// содержимое помечается к удалению.
+func (s *Storage) DeleteBlob(blobID string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var blob models.Blob
+		if err := tx.Omit("content").Where("id = ?", blobID).First(&blob).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		if err := tx.Delete(&blob).Error; err != nil {
+			return err
+		}
+		if blob.Hash == "" {
+			return nil
+		}
+		var count int64
+		if err := tx.Model(&models.Blob{}).Where("hash = ?", blob.Hash).Limit(1).Count(&count).Error; err != nil || count > 0 {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.BlobPurge{Hash: blob.Hash, MarkedAt: time.Now()}).Error
+	})
+}
+
+// ClaimBlobPurges начинает удаление содержимого, помеченного раньше before, на которое так и не сослался
+// ни один файл, и возвращает ключи всего содержимого, удаление которого начато и не завершено
+// (в том числе прерванного прошлой очисткой). Пометки содержимого, загруженного снова, снимаются.
+func (s *Storage) ClaimBlobPurges(before time.Time) ([]string, error) {
+	var hashes []string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		const referenced = "EXISTS (SELECT 1 FROM blobs WHERE blobs.hash = blob_purges.hash)"
+		if err := tx.Where("deleting = ? AND "+referenced, false).Delete(&models.BlobPurge{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.BlobPurge{}).
+			Where("deleting = ? AND marked_at < ? AND NOT "+referenced, false, before).
+			Update("deleting", true).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.BlobPurge{}).Where("deleting = ?", true).Order("hash").Pluck("hash", &hashes).Error
+	})
+	return hashes, err
+}
+
+// FinishBlobPurge снимает пометку после удаления содержимого hash из хранилища файлов
+func (s *Storage) FinishBlobPurge(hash string) error {
+	return s.db.Where("hash = ? AND deleting = ?", hash, true).Delete(&models.BlobPurge{}).Error
+}
@@ -0,0 +1,104 @@
+package storage_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gophkeeper/gophkeeper/internal/models"
+	"github.com/gophkeeper/gophkeeper/internal/storage"
+)
+
+// reserve сохраняет запись о файле с содержимым hash
+func reserve(t *testing.T, s *storage.Storage, userID, hash string) (*models.Blob, bool) {
+	t.Helper()
+	b := &models.Blob{UserID: userID, Hash: hash, Size: 1}
+	purging, err := s.ReserveBlob(b)
+	if err != nil {
+		t.Fatalf("ReserveBlob: %v", err)
+	}
+	return b, purging
+}
+
+// claim начинает удаление всего помеченного содержимого
+func claim(t *testing.T, s *storage.Storage) []string {
+	t.Helper()
+	hashes, err := s.ClaimBlobPurges(time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatalf("ClaimBlobPurges: %v", err)
+	}
+	return hashes
+}
+
+func TestBlobPurge_UnreferencedContent(t *testing.T) {
+	s, userID := newStorage(t)
+	b, _ := reserve(t, s, userID, "h1")
+
+	if err := s.DeleteBlob(b.ID); err != nil {
+		t.Fatalf("DeleteBlob: %v", err)
+	}
+	if hashes, _ := s.ClaimBlobPurges(time.Now().Add(-time.Hour)); len(hashes) != 0 {
+		t.Errorf("claimed = %v before the delay, want none", hashes)
+	}
+	if hashes := claim(t, s); !slices.Equal(hashes, []string{"h1"}) {
+		t.Fatalf("claimed = %v, want h1", hashes)
+	}
+	if purging, _ := s.BlobPurging("h1"); !purging {
+		t.Error("BlobPurging = false while content is being deleted")
+	}
+	if err := s.FinishBlobPurge("h1"); err != nil {
+		t.Fatalf("FinishBlobPurge: %v", err)
+	}
+	if hashes := claim(t, s); len(hashes) != 0 {
+		t.Errorf("claimed = %v after finish, want none", hashes)
+	}
+}
+
+func TestBlobPurge_ContentStillReferenced(t *testing.T) {
+	s, userID := newStorage(t)
+	b, _ := reserve(t, s, userID, "h1")
+	reserve(t, s, userID, "h1")
+
+	if err := s.DeleteBlob(b.ID); err != nil {
+		t.Fatalf("DeleteBlob: %v", err)
+	}
+	if hashes := claim(t, s); len(hashes) != 0 {
+		t.Errorf("claimed = %v, want none: another blob uses h1", hashes)
+	}
+}
+
+// Загрузка того же содержимого после пометки отменяет удаление
+func TestBlobPurge_UploadAfterMark(t *testing.T) {
+	s, userID := newStorage(t)
+	b, _ := reserve(t, s, userID, "h1")
+	_ = s.DeleteBlob(b.ID)
+
+	if _, purging := reserve(t, s, userID, "h1"); purging {
+		t.Error("purging = true, want the mark dropped")
+	}
+	if hashes := claim(t, s); len(hashes) != 0 {
+		t.Errorf("claimed = %v, want none after upload", hashes)
+	}
+}
+
+// Загрузка во время удаления узнаёт о нём и ждёт его конца, чтобы сохранить содержимое заново
+func TestBlobPurge_UploadWhileDeleting(t *testing.T) {
+	s, userID := newStorage(t)
+	b, _ := reserve(t, s, userID, "h1")
+	_ = s.DeleteBlob(b.ID)
+	claim(t, s)
+
+	if _, purging := reserve(t, s, userID, "h1"); !purging {
+		t.Fatal("purging = false while content is being deleted")
+	}
+	if err := s.FinishBlobPurge("h1"); err != nil {
+		t.Fatalf("FinishBlobPurge: %v", err)
+	}
+	if purging, _ := s.BlobPurging("h1"); purging {
+		t.Error("BlobPurging = true after finish")
+	}
+	// Новая запись ссылается на содержимое, поэтому его не помечают снова
+	if hashes := claim(t, s); len(hashes) != 0 {
+		t.Errorf("claimed = %v, want none", hashes)
+	}
+}
@@ -1,35 +1,124 @@
 package storage
 
 import (
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/gophkeeper/gophkeeper/internal/models"
 	"gorm.io/gorm"
 )
 
-// SaveData сохраняет данные пользователя
-func (s *Storage) SaveData(userID string, data *models.Data) error {
+// SaveData создаёт или обновляет данные пользователя с оптимистичной блокировкой.
+// baseVersion == 0 — создание новой записи; иначе запись обновляется, только если её текущая версия
+// равна baseVersion. Проверка и обновление выполняются одним условным UPDATE внутри транзакции,
+// поэтому параллельные изменения не перезаписывают друг друга ни в SQLite, ни в PostgreSQL.
+// При конфликте возвращает ErrVersionConflict и актуальную копию записи (nil, если запись удалена).
+func (s *Storage) SaveData(userID string, data *models.Data, baseVersion int64) (*models.Data, error) {
 	// NOT NULL: пустой слайс вместо nil для encrypted_data
 	if data.EncryptedData == nil {
 		data.EncryptedData = []byte{}
 	}
 
-	// Проверяем существование записи
-	var existingData models.Data
-	err := s.db.Where("id = ? AND user_id = ?", data.ID, userID).First(&existingData).Error
-
-	if err == nil {
-		// Обновляем существующую запись
-		data.Version = existingData.Version + 1
-		data.UpdatedAt = time.Now()
-		return s.db.Model(&existingData).Updates(data).Error
-	} else if err == gorm.ErrRecordNotFound {
-		// Создаём новую запись
+	data.NameLower = strings.ToLower(data.Name)
+
+	var current *models.Data
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		seq, err := nextChangeSeq(tx, userID)
+		if err != nil {
+			return err
+		}
+		data.Seq = seq
+
+		if baseVersion == 0 {
+			if data.ID != "" {
+				// С удалёнными: ID удалённой записи занят её tombstone, и создание с ним — конфликт
+				// с удалённой записью (current = nil), а не ошибка первичного ключа
+				existing, err := findData(tx.Unscoped(), userID, data.ID)
+				if err != nil {
+					return err
+				}
+				if existing != nil {
+					if !existing.DeletedAt.Valid {
+						current = existing
+					}
+					return ErrVersionConflict
+				}
+			}
+			data.UserID = userID
+			data.Version = 1
+			if err := tx.Create(data).Error; err != nil {
+				return err
+			}
+			return writeSearchIndex(tx, data)
+		}
+
+		now := time.Now()
+		result := tx.Model(&models.Data{}).
+			Where("id = ? AND user_id = ? AND version = ?", data.ID, userID, baseVersion).
+			Updates(map[string]interface{}{
+				"type":           data.Type,
+				"name":           data.Name,
+				"name_lower":     data.NameLower,
+				"encrypted_data": data.EncryptedData,
+				"metadata":       data.Metadata,
+				"encrypted_meta": data.EncryptedMeta,
+				"search_token":   data.SearchToken,
+				"blob_id":        data.BlobID,
+				"version":        baseVersion + 1,
+				"seq":            seq,
+				"updated_at":     now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			existing, err := findData(tx, userID, data.ID)
+			if err != nil {
+				return err
+			}
+			current = existing
+			return ErrVersionConflict
+		}
+
 		data.UserID = userID
-		return s.db.Create(data).Error
+		data.Version = baseVersion + 1
+		data.UpdatedAt = now
+		return writeSearchIndex(tx, data)
+	})
+	if errors.Is(err, ErrVersionConflict) {
+		return current, err
+	}
+	return nil, err
+}
+
+// nextChangeSeq увеличивает счётчик изменений пользователя и возвращает новое значение.
+// UPDATE блокирует строку пользователя до конца транзакции, поэтому номера выдаются
+// в порядке фиксации транзакций и курсор синхронизации не пропускает изменения.
+func nextChangeSeq(tx *gorm.DB, userID string) (int64, error) {
+	if err := tx.Model(&models.User{}).
+		Where("id = ?", userID).
+		Update("change_seq", gorm.Expr("change_seq + 1")).Error; err != nil {
+		return 0, err
 	}
 
-	return err
+	var seq int64
+	if err := tx.Model(&models.User{}).Where("id = ?", userID).Pluck("change_seq", &seq).Error; err != nil {
+		return 0, err
+	}
+	return seq, nil
+}
+
+// findData возвращает запись пользователя или nil, если её нет
+func findData(tx *gorm.DB, userID, dataID string) (*models.Data, error) {
+	var data models.Data
+	if err := tx.Where("id = ? AND user_id = ?", dataID, userID).First(&data).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &data, nil
 }
 
 // GetData получает данные по ID
@@ -57,16 +146,106 @@ func (s *Storage) ListData(userID string, dataType models.DataType) ([]*models.D
 	return dataList, nil
 }
 
-// DeleteData удаляет данные
+// summarySortColumns — столбцы, по которым разрешена сортировка кратких описаний
+var summarySortColumns = map[models.DataSort]string{
+	models.DataSortUpdatedAt: "updated_at",
+	models.DataSortName:      "name",
+	models.DataSortCreatedAt: "created_at",
+}
+
+// ListDataSummaries возвращает страницу кратких описаний записей пользователя без содержимого,
+// отобранных по q.Filter.
+// Размер содержимого считается в БД (LENGTH для blob в SQLite и bytea в PostgreSQL возвращает байты),
+// при равных значениях поля сортировки порядок задаёт id, чтобы страницы не пересекались.
+func (s *Storage) ListDataSummaries(userID string, q models.DataSummaryQuery) ([]*models.DataSummary, error) {
+	column, ok := summarySortColumns[q.Sort]
+	if !ok {
+		column = summarySortColumns[models.DataSortUpdatedAt]
+	}
+	direction := "ASC"
+	if q.Desc {
+		direction = "DESC"
+	}
+
+	query := s.db.Model(&models.Data{}).
+		Select("id, type, name, encrypted_meta, blob_id, version, created_at, updated_at, LENGTH(encrypted_data) AS size")
+	query = applyDataFilter(query, userID, q.Filter)
+
+	var summaries []*models.DataSummary
+	if err := query.
+		Order(column + " " + direction).
+		Order("id " + direction).
+		Offset(q.Offset).
+		Limit(q.Limit).
+		Scan(&summaries).Error; err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+// DeleteData мягко удаляет данные. Запись остаётся в таблице как tombstone: версия увеличивается,
+// а номер изменения обновляется, чтобы удаление попало в синхронизацию других клиентов.
+// Если записи нет или она уже удалена, возвращает ErrDataNotFound, а номер изменения не расходуется.
 func (s *Storage) DeleteData(userID, dataID string) error {
-	return s.db.Where("id = ? AND user_id = ?", dataID, userID).Delete(&models.Data{}).Error
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		seq, err := nextChangeSeq(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		result := tx.Model(&models.Data{}).
+			Where("id = ? AND user_id = ?", dataID, userID).
+			Updates(map[string]interface{}{
+				"deleted_at": now,
+				"updated_at": now,
+				"version":    gorm.Expr("version + 1"),
+				"seq":        seq,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// Откат транзакции возвращает и счётчик изменений
+			return ErrDataNotFound
+		}
+		return nil
+	})
 }
 
-// GetDataSince получает данные, изменённые после указанного времени (для синхронизации)
-func (s *Storage) GetDataSince(userID string, since time.Time) ([]*models.Data, error) {
+// GetDataChanges возвращает до limit записей с номером изменения больше afterSeq в порядке номеров,
+// включая удалённые записи (у них заполнен DeletedAt)
+func (s *Storage) GetDataChanges(userID string, afterSeq int64, limit int) ([]*models.Data, error) {
 	var dataList []*models.Data
-	if err := s.db.Where("user_id = ? AND updated_at > ?", userID, since).Find(&dataList).Error; err != nil {
+	if err := s.db.Unscoped().
+		Where("user_id = ? AND seq > ?", userID, afterSeq).
+		Order("seq").
+		Limit(limit).
+		Find(&dataList).Error; err != nil {
 		return nil, err
 	}
 	return dataList, nil
 }
+
+// PurgeDeletedData окончательно удаляет записи, удалённые раньше before, вместе с их поисковым индексом.
+// Возвращает число удалённых строк.
+func (s *Storage) PurgeDeletedData(before time.Time) (int64, error) {
+	var purged int64
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		expired := tx.Unscoped().Model(&models.Data{}).
+			Select("id").
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before)
+		if err := tx.Where("data_id IN (?)", expired).Delete(&models.DataMetaKey{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("data_id IN (?)", expired).Delete(&models.DataTag{}).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+			Delete(&models.Data{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
+}
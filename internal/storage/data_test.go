@@ -0,0 +1,116 @@
+package storage_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/gophkeeper/gophkeeper/internal/migrations"
+	"github.com/gophkeeper/gophkeeper/internal/models"
+	"github.com/gophkeeper/gophkeeper/internal/storage"
+)
+
+// newStorage открывает SQLite во временном каталоге с выполненными миграциями и создаёт пользователя
+func newStorage(t *testing.T) (*storage.Storage, string) {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "test.db")
+	s, err := storage.NewStorage(dsn, "sqlite")
+	if err != nil {
+		t.Fatalf("NewStorage: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	if err := migrations.RunUp(s.GetDB(), dsn, "sqlite"); err != nil {
+		t.Fatalf("RunUp: %v", err)
+	}
+	user, err := s.CreateUser("alice", "hash")
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	return s, user.ID
+}
+
+func TestSaveData_RecreateDeletedIDIsConflict(t *testing.T) {
+	s, userID := newStorage(t)
+
+	data := &models.Data{ID: "data-1", Type: models.DataTypeText, Name: "note", EncryptedData: []byte("v1")}
+	if _, err := s.SaveData(userID, data, 0); err != nil {
+		t.Fatalf("SaveData: %v", err)
+	}
+	if err := s.DeleteData(userID, "data-1"); err != nil {
+		t.Fatalf("DeleteData: %v", err)
+	}
+
+	again := &models.Data{ID: "data-1", Type: models.DataTypeText, Name: "note", EncryptedData: []byte("v2")}
+	current, err := s.SaveData(userID, again, 0)
+	if !errors.Is(err, storage.ErrVersionConflict) {
+		t.Fatalf("SaveData over tombstone: err = %v, want ErrVersionConflict", err)
+	}
+	if current != nil {
+		t.Errorf("current = %+v, want nil for a deleted record", current)
+	}
+}
+
+func TestSaveData_CreateExistingIDIsConflict(t *testing.T) {
+	s, userID := newStorage(t)
+
+	data := &models.Data{ID: "data-1", Type: models.DataTypeText, Name: "note", EncryptedData: []byte("v1")}
+	if _, err := s.SaveData(userID, data, 0); err != nil {
+		t.Fatalf("SaveData: %v", err)
+	}
+
+	again := &models.Data{ID: "data-1", Type: models.DataTypeText, Name: "other", EncryptedData: []byte("v2")}
+	current, err := s.SaveData(userID, again, 0)
+	if !errors.Is(err, storage.ErrVersionConflict) {
+		t.Fatalf("err = %v, want ErrVersionConflict", err)
+	}
+	if current == nil || current.Name != "note" || current.Version != 1 {
+		t.Errorf("current = %+v, want the stored record", current)
+	}
+}
+
+func TestDeleteData_MissingID(t *testing.T) {
+	s, userID := newStorage(t)
+
+	if err := s.DeleteData(userID, "missing"); !errors.Is(err, storage.ErrDataNotFound) {
+		t.Fatalf("DeleteData(missing): err = %v, want ErrDataNotFound", err)
+	}
+	changes, err := s.GetDataChanges(userID, 0, 10)
+	if err != nil {
+		t.Fatalf("GetDataChanges: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("changes = %d, want 0", len(changes))
+	}
+
+	// Номер изменения не израсходован: следующая запись получает первый номер
+	data := &models.Data{ID: "data-1", Type: models.DataTypeText, Name: "note", EncryptedData: []byte("v1")}
+	if _, err := s.SaveData(userID, data, 0); err != nil {
+		t.Fatalf("SaveData: %v", err)
+	}
+	if data.Seq != 1 {
+		t.Errorf("Seq = %d, want 1", data.Seq)
+	}
+}
+
+func TestDeleteData_AlreadyDeleted(t *testing.T) {
+	s, userID := newStorage(t)
+
+	data := &models.Data{ID: "data-1", Type: models.DataTypeText, Name: "note", EncryptedData: []byte("v1")}
+	if _, err := s.SaveData(userID, data, 0); err != nil {
+		t.Fatalf("SaveData: %v", err)
+	}
+	if err := s.DeleteData(userID, "data-1"); err != nil {
+		t.Fatalf("DeleteData: %v", err)
+	}
+	if err := s.DeleteData(userID, "data-1"); !errors.Is(err, storage.ErrDataNotFound) {
+		t.Fatalf("second DeleteData: err = %v, want ErrDataNotFound", err)
+	}
+
+	changes, err := s.GetDataChanges(userID, 0, 10)
+	if err != nil {
+		t.Fatalf("GetDataChanges: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Seq != 2 || changes[0].Version != 2 {
+		t.Errorf("changes = %+v, want one tombstone with seq 2 and version 2", changes)
+	}
+}
@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gophkeeper/gophkeeper/internal/models"
+	"gorm.io/gorm"
+)
+
+// searchBackfillBatch — сколько записей без поискового индекса обрабатывается за одну транзакцию
+const searchBackfillBatch = 500
+
+// searchTerms возвращает ключи метаданных и теги записи в нижнем регистре без повторов.
+// Теги — значения ключа tags через запятую. Метаданные, которые не удалось разобрать, не индексируются.
+func searchTerms(metadata string) (keys, tags []string) {
+	var items []models.MetadataItem
+	if err := json.Unmarshal([]byte(metadata), &items); err != nil {
+		return nil, nil
+	}
+	for _, item := range items {
+		key := strings.ToLower(strings.TrimSpace(item.Key))
+		if key == "" {
+			continue
+		}
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+		if key != models.MetadataKeyTags {
+			continue
+		}
+		for tag := range strings.SplitSeq(item.Value, ",") {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if tag != "" && !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return keys, tags
+}
+
+// writeSearchIndex заменяет ключи метаданных и теги записи в таблицах поиска
+func writeSearchIndex(tx *gorm.DB, data *models.Data) error {
+	if err := tx.Where("data_id = ?", data.ID).Delete(&models.DataMetaKey{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("data_id = ?", data.ID).Delete(&models.DataTag{}).Error; err != nil {
+		return err
+	}
+
+	keys, tags := searchTerms(data.Metadata)
+	if len(keys) > 0 {
+		rows := make([]models.DataMetaKey, 0, len(keys))
+		for _, key := range keys {
+			rows = append(rows, models.DataMetaKey{DataID: data.ID, UserID: data.UserID, Key: key})
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			return err
+		}
+	}
+	if len(tags) > 0 {
+		rows := make([]models.DataTag, 0, len(tags))
+		for _, tag := range tags {
+			rows = append(rows, models.DataTag{DataID: data.ID, UserID: data.UserID, Tag: tag})
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyDataFilter добавляет к запросу по таблице data условия поиска.
+// Префикс названия ищется диапазоном по индексу (user_id, name_lower); name_lower сравнивается
+// побайтово в обеих БД, поэтому все строки с префиксом p лежат в [p, p+U+10FFFF).
+func applyDataFilter(query *gorm.DB, userID string, f models.DataFilter) *gorm.DB {
+	query = query.Where("user_id = ?", userID)
+	if len(f.Types) > 0 {
+		query = query.Where("type IN ?", f.Types)
+	}
+	if f.NamePrefix != "" {
+		prefix := strings.ToLower(f.NamePrefix)
+		query = query.Where("name_lower >= ? AND name_lower < ?", prefix, prefix+string(utf8.MaxRune))
+	}
+	if f.NameContains != "" {
+		query = query.Where(`name_lower LIKE ? ESCAPE '\'`, "%"+escapeLike(strings.ToLower(f.NameContains))+"%")
+	}
+	if f.SearchToken != "" {
+		query = query.Where("search_token = ?", f.SearchToken)
+	}
+	for _, key := range f.MetadataKeys {
+		query = query.Where("id IN (?)", query.Session(&gorm.Session{NewDB: true}).
+			Model(&models.DataMetaKey{}).Select("data_id").
+			Where("user_id = ? AND key = ?", userID, strings.ToLower(strings.TrimSpace(key))))
+	}
+	for _, tag := range f.Tags {
+		query = query.Where("id IN (?)", query.Session(&gorm.Session{NewDB: true}).
+			Model(&models.DataTag{}).Select("data_id").
+			Where("user_id = ? AND tag = ?", userID, strings.ToLower(strings.TrimSpace(tag))))
+	}
+	if !f.UpdatedSince.IsZero() {
+		query = query.Where("updated_at >= ?", f.UpdatedSince)
+	}
+	return query
+}
+
+// escapeLike экранирует символы шаблона LIKE
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// BackfillSearchIndex заполняет поисковый индекс записей, сохранённых до его появления
+// (name_lower IS NULL), включая удалённые. Возвращает число обработанных записей.
+func (s *Storage) BackfillSearchIndex() (int, error) {
+	total := 0
+	for {
+		var batch []*models.Data
+		if err := s.db.Unscoped().
+			Select("id, user_id, name, metadata").
+			Where("name_lower IS NULL").
+			Limit(searchBackfillBatch).
+			Find(&batch).Error; err != nil {
+			return total, err
+		}
+		if len(batch) == 0 {
+			return total, nil
+		}
+
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			for _, d := range batch {
+				if err := writeSearchIndex(tx, d); err != nil {
+					return err
+				}
+				if err := tx.Unscoped().Model(&models.Data{}).
+					Where("id = ?", d.ID).
+					UpdateColumn("name_lower", strings.ToLower(d.Name)).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+		total += len(batch)
+	}
+}
@@ -2,11 +2,21 @@ package storage
 
 import (
 	"errors"
+	"time"
 
 	"github.com/gophkeeper/gophkeeper/internal/models"
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrKeyCheckMismatch — текущий блок проверки не совпал с ожидаемым
+	ErrKeyCheckMismatch = errors.New("key check mismatch")
+	// ErrVersionConflict — версия записи в БД отличается от ожидаемой
+	ErrVersionConflict = errors.New("version conflict")
+	// ErrDataNotFound — записи нет или она уже удалена
+	ErrDataNotFound = errors.New("data not found")
+)
+
 // CreateUser создаёт нового пользователя
 func (s *Storage) CreateUser(login, passwordHash string) (*models.User, error) {
 	user := &models.User{
@@ -44,3 +54,81 @@ func (s *Storage) GetUserByID(userID string) (*models.User, error) {
 	}
 	return &user, nil
 }
+
+// SetUserKeyCheck заменяет блок проверки мастер-пароля, если текущее значение совпадает с previous
+// (пустой previous — блок ещё не задан). Возвращает false, если значение успело измениться.
+func (s *Storage) SetUserKeyCheck(userID string, previous, keyCheck []byte) (bool, error) {
+	query := s.db.Model(&models.User{}).Where("id = ?", userID)
+	if len(previous) == 0 {
+		query = query.Where("key_check IS NULL OR length(key_check) = 0")
+	} else {
+		query = query.Where("key_check = ?", previous)
+	}
+
+	result := query.Update("key_check", keyCheck)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ChangeUserCredentials в одной транзакции обновляет хеш пароля, блок проверки (по previousKeyCheck)
+// и перешифрованные записи (по их версиям), а также увеличивает поколение токенов пользователя.
+// Пустые passwordHash и keyCheck не изменяются. Возвращает новое поколение токенов.
+func (s *Storage) ChangeUserCredentials(userID, passwordHash string, previousKeyCheck, keyCheck []byte, records []*models.Data) (int64, error) {
+	var tokenVersion int64
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			"token_version": gorm.Expr("token_version + 1"),
+			"updated_at":    time.Now(),
+		}
+		if passwordHash != "" {
+			updates["password_hash"] = passwordHash
+		}
+
+		query := tx.Model(&models.User{}).Where("id = ?", userID)
+		if len(keyCheck) > 0 {
+			updates["key_check"] = keyCheck
+			if len(previousKeyCheck) == 0 {
+				query = query.Where("key_check IS NULL OR length(key_check) = 0")
+			} else {
+				query = query.Where("key_check = ?", previousKeyCheck)
+			}
+		}
+
+		result := query.Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrKeyCheckMismatch
+		}
+
+		for _, record := range records {
+			seq, err := nextChangeSeq(tx, userID)
+			if err != nil {
+				return err
+			}
+			result := tx.Model(&models.Data{}).
+				Where("id = ? AND user_id = ? AND version = ?", record.ID, userID, record.Version).
+				Updates(map[string]interface{}{
+					"encrypted_data": record.EncryptedData,
+					"version":        gorm.Expr("version + 1"),
+					"seq":            seq,
+					"updated_at":     time.Now(),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrVersionConflict
+			}
+		}
+
+		return tx.Model(&models.User{}).Where("id = ?", userID).Pluck("token_version", &tokenVersion).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return tokenVersion, nil
+}
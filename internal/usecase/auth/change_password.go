@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gophkeeper/gophkeeper/internal/crypto"
+	"github.com/gophkeeper/gophkeeper/internal/domain/repository"
+	"github.com/gophkeeper/gophkeeper/internal/models"
+)
+
+var (
+	ErrPasswordRequired = errors.New("current password is required")
+	ErrNothingToChange  = errors.New("nothing to change")
+	ErrRecordsChanged   = errors.New("records have been changed, reload and retry")
+)
+
+// ChangePasswordInput входные данные для смены пароля входа и/или мастер-пароля
+type ChangePasswordInput struct {
+	UserID      string
+	OldPassword string
+	// NewPassword — новый пароль входа (пусто — не менять)
+	NewPassword string
+	// KeyCheck — блок проверки для нового мастер-пароля (пусто — не менять)
+	KeyCheck         []byte
+	PreviousKeyCheck []byte
+	// Records — записи, перешифрованные клиентом; Version — версия, от которой клиент отталкивался
+	Records []*models.Data
+}
+
+// ChangePasswordOutput результат смены пароля (новые токены, старые больше не действуют)
+type ChangePasswordOutput struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int64
+}
+
+// ChangePassword проверяет текущий пароль и атомарно применяет смену пароля и перешифрованные записи.
+// Все ранее выданные refresh токены после этого отклоняются.
+func (uc *AuthUseCase) ChangePassword(ctx context.Context, in ChangePasswordInput) (*ChangePasswordOutput, error) {
+	if in.OldPassword == "" {
+		return nil, ErrPasswordRequired
+	}
+	if in.NewPassword == "" && len(in.KeyCheck) == 0 && len(in.Records) == 0 {
+		return nil, ErrNothingToChange
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, in.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !crypto.CheckPassword(in.OldPassword, user.PasswordHash) {
+		return nil, ErrInvalidCredentials
+	}
+
+	change := repository.CredentialsChange{
+		KeyCheck:         in.KeyCheck,
+		PreviousKeyCheck: in.PreviousKeyCheck,
+		Records:          in.Records,
+	}
+	if in.NewPassword != "" {
+		change.PasswordHash, err = crypto.HashPassword(in.NewPassword)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tokenVersion, err := uc.userRepo.ChangeCredentials(ctx, user.ID, change)
+	switch {
+	case errors.Is(err, repository.ErrKeyCheckMismatch):
+		return nil, ErrKeyCheckMismatch
+	case errors.Is(err, repository.ErrVersionConflict):
+		return nil, ErrRecordsChanged
+	case err != nil:
+		return nil, err
+	}
+
+	accessToken, err := crypto.GenerateAccessToken(user.ID, tokenVersion)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := crypto.GenerateRefreshToken(user.ID, tokenVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChangePasswordOutput{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(crypto.AccessTokenExpiry.Seconds()),
+	}, nil
+}
@@ -0,0 +1,158 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gophkeeper/gophkeeper/internal/crypto"
+	"github.com/gophkeeper/gophkeeper/internal/domain/repository"
+	"github.com/gophkeeper/gophkeeper/internal/domain/repository/mocks"
+	"github.com/gophkeeper/gophkeeper/internal/models"
+	"github.com/gophkeeper/gophkeeper/internal/usecase/auth"
+	"go.uber.org/mock/gomock"
+)
+
+func userWithPassword(t *testing.T, password string) *models.User {
+	t.Helper()
+	hash, err := crypto.HashPassword(password)
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	return &models.User{ID: "user-1", Login: "testuser", PasswordHash: hash}
+}
+
+func TestChangePassword_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	records := []*models.Data{{ID: "d1", EncryptedData: []byte("re-encrypted"), Version: 3}}
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), "user-1").
+		Return(userWithPassword(t, "old"), nil)
+	userRepo.EXPECT().
+		ChangeCredentials(gomock.Any(), "user-1", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, change repository.CredentialsChange) (int64, error) {
+			if !crypto.CheckPassword("new", change.PasswordHash) {
+				t.Error("PasswordHash should match the new password")
+			}
+			if string(change.KeyCheck) != "new-check" || string(change.PreviousKeyCheck) != "old-check" {
+				t.Errorf("key check = %q/%q", change.KeyCheck, change.PreviousKeyCheck)
+			}
+			if len(change.Records) != 1 || change.Records[0].ID != "d1" {
+				t.Errorf("Records = %v", change.Records)
+			}
+			return 1, nil
+		})
+
+	uc := auth.NewAuthUseCase(userRepo)
+	out, err := uc.ChangePassword(context.Background(), auth.ChangePasswordInput{
+		UserID:           "user-1",
+		OldPassword:      "old",
+		NewPassword:      "new",
+		KeyCheck:         []byte("new-check"),
+		PreviousKeyCheck: []byte("old-check"),
+		Records:          records,
+	})
+
+	if err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	claims, err := crypto.ValidateToken(out.RefreshToken)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if claims.TokenVersion != 1 {
+		t.Errorf("TokenVersion = %d, want 1", claims.TokenVersion)
+	}
+}
+
+func TestChangePassword_WrongOldPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), "user-1").
+		Return(userWithPassword(t, "old"), nil)
+	// ChangeCredentials не должен вызываться
+
+	uc := auth.NewAuthUseCase(userRepo)
+	_, err := uc.ChangePassword(context.Background(), auth.ChangePasswordInput{
+		UserID:      "user-1",
+		OldPassword: "wrong",
+		NewPassword: "new",
+	})
+
+	if !errors.Is(err, auth.ErrInvalidCredentials) {
+		t.Errorf("err = %v, want ErrInvalidCredentials", err)
+	}
+}
+
+func TestChangePassword_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	// Репозиторий не вызывается
+
+	uc := auth.NewAuthUseCase(userRepo)
+
+	t.Run("empty_old_password", func(t *testing.T) {
+		_, err := uc.ChangePassword(context.Background(), auth.ChangePasswordInput{
+			UserID:      "user-1",
+			NewPassword: "new",
+		})
+		if !errors.Is(err, auth.ErrPasswordRequired) {
+			t.Errorf("err = %v, want ErrPasswordRequired", err)
+		}
+	})
+	t.Run("nothing_to_change", func(t *testing.T) {
+		_, err := uc.ChangePassword(context.Background(), auth.ChangePasswordInput{
+			UserID:      "user-1",
+			OldPassword: "old",
+		})
+		if !errors.Is(err, auth.ErrNothingToChange) {
+			t.Errorf("err = %v, want ErrNothingToChange", err)
+		}
+	})
+}
+
+func TestChangePassword_Conflicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		repoErr error
+		want    error
+	}{
+		{"key_check", repository.ErrKeyCheckMismatch, auth.ErrKeyCheckMismatch},
+		{"records", repository.ErrVersionConflict, auth.ErrRecordsChanged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mocks.NewMockUserRepository(ctrl)
+			userRepo.EXPECT().
+				GetByID(gomock.Any(), "user-1").
+				Return(userWithPassword(t, "old"), nil)
+			userRepo.EXPECT().
+				ChangeCredentials(gomock.Any(), "user-1", gomock.Any()).
+				Return(int64(0), tt.repoErr)
+
+			uc := auth.NewAuthUseCase(userRepo)
+			_, err := uc.ChangePassword(context.Background(), auth.ChangePasswordInput{
+				UserID:      "user-1",
+				OldPassword: "old",
+				KeyCheck:    []byte("new-check"),
+			})
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ type LoginUserOutput struct {
 	AccessToken  string
 	RefreshToken string
 	ExpiresIn    int64
+	KeyCheck     []byte
 }
 
 // LoginUser выполняет вход пользователя и возвращает токены
@@ -43,11 +44,11 @@ func (uc *AuthUseCase) LoginUser(ctx context.Context, in LoginUserInput) (*Login
 		return nil, ErrInvalidCredentials
 	}
 
-	accessToken, err := crypto.GenerateAccessToken(user.ID)
+	accessToken, err := crypto.GenerateAccessToken(user.ID, user.TokenVersion)
 	if err != nil {
 		return nil, err
 	}
-	refreshToken, err := crypto.GenerateRefreshToken(user.ID)
+	refreshToken, err := crypto.GenerateRefreshToken(user.ID, user.TokenVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -57,5 +58,6 @@ func (uc *AuthUseCase) LoginUser(ctx context.Context, in LoginUserInput) (*Login
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		ExpiresIn:    int64(crypto.AccessTokenExpiry.Seconds()),
+		KeyCheck:     user.KeyCheck,
 	}, nil
 }
@@ -20,7 +20,7 @@ func TestLoginUser_Success(t *testing.T) {
 	if err != nil {
 		t.Fatalf("HashPassword: %v", err)
 	}
-	user := &models.User{ID: "user-1", Login: "testuser", PasswordHash: hash}
+	user := &models.User{ID: "user-1", Login: "testuser", PasswordHash: hash, KeyCheck: []byte("check")}
 
 	userRepo := mocks.NewMockUserRepository(ctrl)
 	userRepo.EXPECT().
@@ -45,6 +45,9 @@ func TestLoginUser_Success(t *testing.T) {
 	if out.ExpiresIn <= 0 {
 		t.Error("ExpiresIn should be positive")
 	}
+	if string(out.KeyCheck) != "check" {
+		t.Errorf("KeyCheck = %q, want check", out.KeyCheck)
+	}
 }
 
 func TestLoginUser_LoginPasswordRequired(t *testing.T) {
@@ -24,7 +24,8 @@ type RefreshTokenOutput struct {
 	ExpiresIn    int64
 }
 
-// RefreshToken обновляет access токен по refresh токену
+// RefreshToken обновляет access токен по refresh токену.
+// Токены, выданные до последней смены пароля, отклоняются.
 func (uc *AuthUseCase) RefreshToken(ctx context.Context, in RefreshTokenInput) (*RefreshTokenOutput, error) {
 	if in.RefreshToken == "" {
 		return nil, ErrRefreshTokenRequired
@@ -35,11 +36,19 @@ func (uc *AuthUseCase) RefreshToken(ctx context.Context, in RefreshTokenInput) (
 		return nil, ErrInvalidRefreshToken
 	}
 
-	accessToken, err := crypto.GenerateAccessToken(claims.UserID)
+	user, err := uc.userRepo.GetByID(ctx, claims.UserID)
 	if err != nil {
 		return nil, err
 	}
-	refreshToken, err := crypto.GenerateRefreshToken(claims.UserID)
+	if user == nil || user.TokenVersion != claims.TokenVersion {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	accessToken, err := crypto.GenerateAccessToken(user.ID, user.TokenVersion)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := crypto.GenerateRefreshToken(user.ID, user.TokenVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gophkeeper/gophkeeper/internal/crypto"
 	"github.com/gophkeeper/gophkeeper/internal/domain/repository/mocks"
+	"github.com/gophkeeper/gophkeeper/internal/models"
 	"github.com/gophkeeper/gophkeeper/internal/usecase/auth"
 	"go.uber.org/mock/gomock"
 )
@@ -25,13 +26,15 @@ func TestRefreshToken_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	refreshToken, err := crypto.GenerateRefreshToken("user-1")
+	refreshToken, err := crypto.GenerateRefreshToken("user-1", 2)
 	if err != nil {
 		t.Fatalf("GenerateRefreshToken: %v", err)
 	}
 
 	userRepo := mocks.NewMockUserRepository(ctrl)
-	// Репозиторий не используется в RefreshToken
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), "user-1").
+		Return(&models.User{ID: "user-1", TokenVersion: 2}, nil)
 
 	uc := auth.NewAuthUseCase(userRepo)
 	out, err := uc.RefreshToken(context.Background(), auth.RefreshTokenInput{
@@ -86,3 +89,51 @@ func TestRefreshToken_InvalidRefreshToken(t *testing.T) {
 		t.Errorf("err = %v, want ErrInvalidRefreshToken", err)
 	}
 }
+
+func TestRefreshToken_RevokedAfterPasswordChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	refreshToken, err := crypto.GenerateRefreshToken("user-1", 0)
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken: %v", err)
+	}
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), "user-1").
+		Return(&models.User{ID: "user-1", TokenVersion: 1}, nil)
+
+	uc := auth.NewAuthUseCase(userRepo)
+	_, err = uc.RefreshToken(context.Background(), auth.RefreshTokenInput{
+		RefreshToken: refreshToken,
+	})
+
+	if !errors.Is(err, auth.ErrInvalidRefreshToken) {
+		t.Errorf("err = %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+func TestRefreshToken_UserNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	refreshToken, err := crypto.GenerateRefreshToken("user-1", 0)
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken: %v", err)
+	}
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().
+		GetByID(gomock.Any(), "user-1").
+		Return(nil, nil)
+
+	uc := auth.NewAuthUseCase(userRepo)
+	_, err = uc.RefreshToken(context.Background(), auth.RefreshTokenInput{
+		RefreshToken: refreshToken,
+	})
+
+	if !errors.Is(err, auth.ErrInvalidRefreshToken) {
+		t.Errorf("err = %v, want ErrInvalidRefreshToken", err)
+	}
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrKeyCheckRequired = errors.New("key check is required")
+	ErrKeyCheckMismatch = errors.New("key check has been changed")
+)
+
+// SetKeyCheckInput входные данные для сохранения блока проверки мастер-пароля
+type SetKeyCheckInput struct {
+	UserID   string
+	KeyCheck []byte
+	// Previous — значение, которое клиент считает текущим (пусто, если блок ещё не задан)
+	Previous []byte
+}
+
+// SetKeyCheck сохраняет блок проверки мастер-пароля пользователя.
+// Сервер не может проверить блок сам, поэтому замена выполняется только при совпадении Previous.
+func (uc *AuthUseCase) SetKeyCheck(ctx context.Context, in SetKeyCheckInput) error {
+	if len(in.KeyCheck) == 0 {
+		return ErrKeyCheckRequired
+	}
+
+	updated, err := uc.userRepo.SetKeyCheck(ctx, in.UserID, in.Previous, in.KeyCheck)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrKeyCheckMismatch
+	}
+	return nil
+}
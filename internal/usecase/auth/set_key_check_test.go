@@ -0,0 +1,86 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gophkeeper/gophkeeper/internal/domain/repository/mocks"
+	"github.com/gophkeeper/gophkeeper/internal/usecase/auth"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSetKeyCheck_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().
+		SetKeyCheck(gomock.Any(), "user-1", []byte(nil), []byte("check")).
+		Return(true, nil)
+
+	uc := auth.NewAuthUseCase(userRepo)
+	err := uc.SetKeyCheck(context.Background(), auth.SetKeyCheckInput{
+		UserID:   "user-1",
+		KeyCheck: []byte("check"),
+	})
+
+	if err != nil {
+		t.Fatalf("SetKeyCheck: %v", err)
+	}
+}
+
+func TestSetKeyCheck_Required(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	uc := auth.NewAuthUseCase(userRepo)
+
+	err := uc.SetKeyCheck(context.Background(), auth.SetKeyCheckInput{UserID: "user-1"})
+	if !errors.Is(err, auth.ErrKeyCheckRequired) {
+		t.Errorf("err = %v, want ErrKeyCheckRequired", err)
+	}
+}
+
+func TestSetKeyCheck_Mismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().
+		SetKeyCheck(gomock.Any(), "user-1", []byte("old"), []byte("new")).
+		Return(false, nil)
+
+	uc := auth.NewAuthUseCase(userRepo)
+	err := uc.SetKeyCheck(context.Background(), auth.SetKeyCheckInput{
+		UserID:   "user-1",
+		KeyCheck: []byte("new"),
+		Previous: []byte("old"),
+	})
+
+	if !errors.Is(err, auth.ErrKeyCheckMismatch) {
+		t.Errorf("err = %v, want ErrKeyCheckMismatch", err)
+	}
+}
+
+func TestSetKeyCheck_RepoError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	wantErr := errors.New("db error")
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().
+		SetKeyCheck(gomock.Any(), "user-1", gomock.Any(), gomock.Any()).
+		Return(false, wantErr)
+
+	uc := auth.NewAuthUseCase(userRepo)
+	err := uc.SetKeyCheck(context.Background(), auth.SetKeyCheckInput{
+		UserID:   "user-1",
+		KeyCheck: []byte("check"),
+	})
+
+	if err != wantErr {
+		t.Errorf("err = %v, want %v", err, wantErr)
+	}
+}
@@ -0,0 +1,30 @@
+package blob
+
+import (
+	"time"
+
+	"github.com/gophkeeper/gophkeeper/internal/domain/repository"
+)
+
+// DefaultMaxBlobSize — наибольший размер загружаемого содержимого файла (с учётом шифрования)
+const DefaultMaxBlobSize = 64 << 20
+
+// BlobUseCase объединяет сценарии работы с файлами: учёт в БД (BlobRepository), содержимое — в BlobStore
+type BlobUseCase struct {
+	blobRepo  repository.BlobRepository
+	blobStore repository.BlobStore
+	maxSize   int64
+	// retention — сколько хранится содержимое, на которое не ссылается ни одна запись:
+	// клиент мог загрузить файл и сохранить запись позже, из очереди офлайн-изменений
+	retention time.Duration
+}
+
+// NewBlobUseCase создаёт use case файлов
+func NewBlobUseCase(blobRepo repository.BlobRepository, blobStore repository.BlobStore, maxSize int64, retention time.Duration) *BlobUseCase {
+	return &BlobUseCase{
+		blobRepo:  blobRepo,
+		blobStore: blobStore,
+		maxSize:   maxSize,
+		retention: retention,
+	}
+}
@@ -0,0 +1,62 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/gophkeeper/gophkeeper/internal/domain/repository"
+)
+
+var (
+	ErrBlobIDRequired = errors.New("blob_id is required")
+	ErrBlobNotFound   = errors.New("blob not found")
+)
+
+// DownloadBlobInput входные данные для выгрузки содержимого файла
+type DownloadBlobInput struct {
+	UserID string
+	BlobID string
+}
+
+// DownloadBlobOutput результат выгрузки; Content нужно закрыть
+type DownloadBlobOutput struct {
+	Content io.ReadCloser
+	Size    int64
+}
+
+// DownloadBlob возвращает содержимое файла пользователя
+func (uc *BlobUseCase) DownloadBlob(ctx context.Context, in DownloadBlobInput) (*DownloadBlobOutput, error) {
+	if in.BlobID == "" {
+		return nil, ErrBlobIDRequired
+	}
+
+	blob, err := uc.blobRepo.Get(ctx, in.UserID, in.BlobID)
+	if err != nil {
+		return nil, err
+	}
+	if blob == nil {
+		return nil, ErrBlobNotFound
+	}
+
+	// Файлы, загруженные до появления хранилища, лежат в самой таблице
+	if blob.Hash == "" {
+		return &DownloadBlobOutput{
+			Content: io.NopCloser(bytes.NewReader(blob.Content)),
+			Size:    blob.Size,
+		}, nil
+	}
+
+	content, err := uc.blobStore.Open(ctx, blob.Hash)
+	if errors.Is(err, repository.ErrBlobNotFound) {
+		return nil, ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &DownloadBlobOutput{
+		Content: content,
+		Size:    blob.Size,
+	}, nil
+}
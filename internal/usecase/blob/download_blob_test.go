@@ -0,0 +1,111 @@
+package blob_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gophkeeper/gophkeeper/internal/domain/repository"
+	"github.com/gophkeeper/gophkeeper/internal/domain/repository/mocks"
+	"github.com/gophkeeper/gophkeeper/internal/models"
+	"github.com/gophkeeper/gophkeeper/internal/usecase/blob"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDownloadBlob_FromStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blobRepo := mocks.NewMockBlobRepository(ctrl)
+	blobRepo.EXPECT().
+		Get(gomock.Any(), "user-1", "blob-1").
+		Return(&models.Blob{ID: "blob-1", UserID: "user-1", Hash: "hash-1", Size: 6}, nil)
+	blobStore := mocks.NewMockBlobStore(ctrl)
+	blobStore.EXPECT().
+		Open(gomock.Any(), "hash-1").
+		Return(io.NopCloser(strings.NewReader("sealed")), nil)
+
+	uc := blob.NewBlobUseCase(blobRepo, blobStore, blob.DefaultMaxBlobSize, time.Hour)
+	out, err := uc.DownloadBlob(context.Background(), blob.DownloadBlobInput{UserID: "user-1", BlobID: "blob-1"})
+
+	if err != nil {
+		t.Fatalf("DownloadBlob: %v", err)
+	}
+	defer out.Content.Close()
+	content, _ := io.ReadAll(out.Content)
+	if string(content) != "sealed" || out.Size != 6 {
+		t.Errorf("content = %q (%d), want sealed", content, out.Size)
+	}
+}
+
+func TestDownloadBlob_LegacyContentInTable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blobRepo := mocks.NewMockBlobRepository(ctrl)
+	blobRepo.EXPECT().
+		Get(gomock.Any(), "user-1", "blob-1").
+		Return(&models.Blob{ID: "blob-1", UserID: "user-1", Content: []byte("sealed"), Size: 6}, nil)
+
+	uc := blob.NewBlobUseCase(blobRepo, mocks.NewMockBlobStore(ctrl), blob.DefaultMaxBlobSize, time.Hour)
+	out, err := uc.DownloadBlob(context.Background(), blob.DownloadBlobInput{UserID: "user-1", BlobID: "blob-1"})
+
+	if err != nil {
+		t.Fatalf("DownloadBlob: %v", err)
+	}
+	content, _ := io.ReadAll(out.Content)
+	if string(content) != "sealed" {
+		t.Errorf("content = %q, want sealed", content)
+	}
+}
+
+func TestDownloadBlob_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blobRepo := mocks.NewMockBlobRepository(ctrl)
+	blobRepo.EXPECT().
+		Get(gomock.Any(), "user-1", "blob-1").
+		Return(nil, nil)
+
+	uc := blob.NewBlobUseCase(blobRepo, mocks.NewMockBlobStore(ctrl), blob.DefaultMaxBlobSize, time.Hour)
+	_, err := uc.DownloadBlob(context.Background(), blob.DownloadBlobInput{UserID: "user-1", BlobID: "blob-1"})
+
+	if !errors.Is(err, blob.ErrBlobNotFound) {
+		t.Errorf("err = %v, want ErrBlobNotFound", err)
+	}
+}
+
+func TestDownloadBlob_MissingInStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blobRepo := mocks.NewMockBlobRepository(ctrl)
+	blobRepo.EXPECT().
+		Get(gomock.Any(), "user-1", "blob-1").
+		Return(&models.Blob{ID: "blob-1", UserID: "user-1", Hash: "hash-1"}, nil)
+	blobStore := mocks.NewMockBlobStore(ctrl)
+	blobStore.EXPECT().Open(gomock.Any(), "hash-1").Return(nil, repository.ErrBlobNotFound)
+
+	uc := blob.NewBlobUseCase(blobRepo, blobStore, blob.DefaultMaxBlobSize, time.Hour)
+	_, err := uc.DownloadBlob(context.Background(), blob.DownloadBlobInput{UserID: "user-1", BlobID: "blob-1"})
+
+	if !errors.Is(err, blob.ErrBlobNotFound) {
+		t.Errorf("err = %v, want ErrBlobNotFound", err)
+	}
+}
+
+func TestDownloadBlob_IDRequired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := blob.NewBlobUseCase(mocks.NewMockBlobRepository(ctrl), mocks.NewMockBlobStore(ctrl), blob.DefaultMaxBlobSize, time.Hour)
+	_, err := uc.DownloadBlob(context.Background(), blob.DownloadBlobInput{UserID: "user-1"})
+
+	if !errors.Is(err, blob.ErrBlobIDRequired) {
+		t.Errorf("err = %v, want ErrBlobIDRequired", err)
+	}
+}
@@ -0,0 +1,42 @@
+package blob
+
+import (
+	"context"
+	"time"
+)
+
+// contentPurgeDelay — сколько содержимое остаётся помеченным к удалению, прежде чем его удалят.
+// За это время завершается загрузка того же содержимого, начавшаяся до пометки: она снимает пометку.
+const contentPurgeDelay = time.Minute
+
+// PurgeUnreferenced удаляет файлы, на которые дольше срока хранения не ссылается ни одна запись, и возвращает
+// их число. Содержимое, на которое больше не ссылается ни один файл, сначала помечается к удалению и удаляется
+// из хранилища при следующем проходе, если его не загрузили снова.
+func (uc *BlobUseCase) PurgeUnreferenced(ctx context.Context) (int64, error) {
+	blobs, err := uc.blobRepo.ListUnreferenced(ctx, time.Now().Add(-uc.retention))
+	if err != nil {
+		return 0, err
+	}
+
+	var purged int64
+	for _, b := range blobs {
+		if err := uc.blobRepo.Delete(ctx, b.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+
+	hashes, err := uc.blobRepo.ClaimPurges(ctx, time.Now().Add(-contentPurgeDelay))
+	if err != nil {
+		return purged, err
+	}
+	for _, hash := range hashes {
+		if err := uc.blobStore.Delete(ctx, hash); err != nil {
+			return purged, err
+		}
+		if err := uc.blobRepo.FinishPurge(ctx, hash); err != nil {
+			return purged, err
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,79 @@
+package blob_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gophkeeper/gophkeeper/internal/domain/repository/mocks"
+	"github.com/gophkeeper/gophkeeper/internal/models"
+	"github.com/gophkeeper/gophkeeper/internal/usecase/blob"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPurgeUnreferenced_DeletesRowsAndClaimedContent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	retention := 24 * time.Hour
+	blobRepo := mocks.NewMockBlobRepository(ctrl)
+	blobStore := mocks.NewMockBlobStore(ctrl)
+	blobRepo.EXPECT().
+		ListUnreferenced(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, before time.Time) ([]*models.Blob, error) {
+			if age := time.Since(before); age < retention || age > retention+time.Minute {
+				t.Errorf("before = %v, want about now - %v", before, retention)
+			}
+			return []*models.Blob{
+				{ID: "b1", Hash: "h1"},
+				{ID: "b2"}, // содержимое в таблице
+			}, nil
+		})
+	blobRepo.EXPECT().Delete(gomock.Any(), "b1").Return(nil)
+	blobRepo.EXPECT().Delete(gomock.Any(), "b2").Return(nil)
+	// Содержимое, помеченное на прошлых проходах; помеченное сейчас удаляется в следующий раз
+	blobRepo.EXPECT().
+		ClaimPurges(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, before time.Time) ([]string, error) {
+			if !before.Before(time.Now()) {
+				t.Errorf("before = %v, want in the past", before)
+			}
+			return []string{"h0", "h2"}, nil
+		})
+	gomock.InOrder(
+		blobStore.EXPECT().Delete(gomock.Any(), "h0").Return(nil),
+		blobRepo.EXPECT().FinishPurge(gomock.Any(), "h0").Return(nil),
+	)
+	gomock.InOrder(
+		blobStore.EXPECT().Delete(gomock.Any(), "h2").Return(nil),
+		blobRepo.EXPECT().FinishPurge(gomock.Any(), "h2").Return(nil),
+	)
+
+	uc := blob.NewBlobUseCase(blobRepo, blobStore, blob.DefaultMaxBlobSize, retention)
+	n, err := uc.PurgeUnreferenced(context.Background())
+
+	if err != nil {
+		t.Fatalf("PurgeUnreferenced: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("n = %d, want 2", n)
+	}
+}
+
+// Если содержимое не удалось удалить, пометка остаётся и удаление повторяется на следующем проходе
+func TestPurgeUnreferenced_KeepsClaimWhenStoreFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blobRepo := mocks.NewMockBlobRepository(ctrl)
+	blobStore := mocks.NewMockBlobStore(ctrl)
+	blobRepo.EXPECT().ListUnreferenced(gomock.Any(), gomock.Any()).Return(nil, nil)
+	blobRepo.EXPECT().ClaimPurges(gomock.Any(), gomock.Any()).Return([]string{"h1"}, nil)
+	blobStore.EXPECT().Delete(gomock.Any(), "h1").Return(errors.New("s3 down"))
+
+	uc := blob.NewBlobUseCase(blobRepo, blobStore, blob.DefaultMaxBlobSize, time.Hour)
+	if _, err := uc.PurgeUnreferenced(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,143 @@
+package blob
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gophkeeper/gophkeeper/internal/models"
+)
+
+var ErrBlobTooLarge = errors.New("blob is too large")
+
+// purgePollInterval — как часто загрузка проверяет, закончилось ли удаление того же содержимого
+const purgePollInterval = 100 * time.Millisecond
+
+// UploadBlobInput входные данные для загрузки содержимого файла
+type UploadBlobInput struct {
+	UserID string
+	// Content — содержимое, зашифрованное клиентом; читается до конца
+	Content io.Reader
+}
+
+// UploadBlobOutput результат загрузки
+type UploadBlobOutput struct {
+	BlobID string
+	Size   int64
+}
+
+// UploadBlob сохраняет содержимое файла в хранилище и регистрирует файл за пользователем.
+// Содержимое больше лимита отклоняется с ErrBlobTooLarge, не попадая в хранилище.
+//
+// Содержимое сначала читается во временный файл: по его хешу запись о файле сохраняется раньше,
+// чем содержимое попадёт в хранилище, и очистка (PurgeUnreferenced) не удалит то же содержимое,
+// загруженное кем-то раньше, — в том числе на другом сервере с общими БД и хранилищем.
+func (uc *BlobUseCase) UploadBlob(ctx context.Context, in UploadBlobInput) (*UploadBlobOutput, error) {
+	f, hash, size, err := spool(&limitedReader{r: in.Content, n: uc.maxSize})
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	blob := &models.Blob{
+		UserID: in.UserID,
+		Hash:   hash,
+		Size:   size,
+	}
+	purging, err := uc.blobRepo.Reserve(ctx, blob)
+	if err != nil {
+		return nil, err
+	}
+	if purging {
+		err = uc.waitPurge(ctx, hash)
+	}
+	if err == nil {
+		err = uc.put(ctx, f, hash)
+	}
+	if err != nil {
+		_ = uc.blobRepo.Delete(ctx, blob.ID)
+		return nil, err
+	}
+
+	return &UploadBlobOutput{
+		BlobID: blob.ID,
+		Size:   blob.Size,
+	}, nil
+}
+
+// put сохраняет содержимое в хранилище; хранилище адресует его тем же SHA-256
+func (uc *BlobUseCase) put(ctx context.Context, content io.Reader, hash string) error {
+	key, _, err := uc.blobStore.Put(ctx, content)
+	if err != nil {
+		return err
+	}
+	if key != hash {
+		return fmt.Errorf("blob store key %q does not match content hash %q", key, hash)
+	}
+	return nil
+}
+
+// waitPurge ждёт, пока очистка закончит удалять содержимое hash: загруженное раньше оно было бы удалено
+func (uc *BlobUseCase) waitPurge(ctx context.Context, hash string) error {
+	ticker := time.NewTicker(purgePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		purging, err := uc.blobRepo.ContentPurging(ctx, hash)
+		if err != nil || !purging {
+			return err
+		}
+	}
+}
+
+// spool копирует r во временный файл и считает SHA-256 содержимого.
+// Возвращённый файл открыт и перемотан в начало; удалить его должен вызывающий.
+func spool(r io.Reader) (*os.File, string, int64, error) {
+	f, err := os.CreateTemp("", "gophkeeper-upload-*")
+	if err != nil {
+		return nil, "", 0, err
+	}
+	discard := func(err error) (*os.File, string, int64, error) {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return nil, "", 0, err
+	}
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(f, h), r)
+	if err != nil {
+		return discard(fmt.Errorf("read blob: %w", err))
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return discard(err)
+	}
+	return f, hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// limitedReader читает не больше n байт и возвращает ErrBlobTooLarge, если содержимое длиннее
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n, ErrBlobTooLarge
+	}
+	return n, err
+}
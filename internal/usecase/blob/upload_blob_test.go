@@ -0,0 +1,145 @@
+package blob_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gophkeeper/gophkeeper/internal/domain/repository/mocks"
+	"github.com/gophkeeper/gophkeeper/internal/models"
+	"github.com/gophkeeper/gophkeeper/internal/usecase/blob"
+	"go.uber.org/mock/gomock"
+)
+
+func sha(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// storeContent — Put хранилища, которое адресует содержимое его SHA-256, как настоящие хранилища
+func storeContent(t *testing.T, want string) func(context.Context, io.Reader) (string, int64, error) {
+	return func(_ context.Context, r io.Reader) (string, int64, error) {
+		content, err := io.ReadAll(r)
+		if err != nil || string(content) != want {
+			t.Errorf("stored %q, %v, want %q", content, err, want)
+		}
+		return sha(string(content)), int64(len(content)), nil
+	}
+}
+
+func TestUploadBlob_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blobRepo := mocks.NewMockBlobRepository(ctrl)
+	blobStore := mocks.NewMockBlobStore(ctrl)
+	gomock.InOrder(
+		// Запись о файле сохраняется раньше содержимого: очистка не удалит его, пока оно загружается
+		blobRepo.EXPECT().
+			Reserve(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, b *models.Blob) (bool, error) {
+				if b.UserID != "user-1" || b.Hash != sha("sealed") || b.Size != 6 || len(b.Content) != 0 {
+					t.Errorf("reserved blob = %+v, want reference to the content of user-1", b)
+				}
+				b.ID = "blob-1"
+				return false, nil
+			}),
+		blobStore.EXPECT().Put(gomock.Any(), gomock.Any()).DoAndReturn(storeContent(t, "sealed")),
+	)
+
+	uc := blob.NewBlobUseCase(blobRepo, blobStore, blob.DefaultMaxBlobSize, time.Hour)
+	out, err := uc.UploadBlob(context.Background(), blob.UploadBlobInput{
+		UserID:  "user-1",
+		Content: strings.NewReader("sealed"),
+	})
+
+	if err != nil {
+		t.Fatalf("UploadBlob: %v", err)
+	}
+	if out.BlobID != "blob-1" || out.Size != 6 {
+		t.Errorf("out = %+v, want blob-1 of 6 bytes", out)
+	}
+}
+
+func TestUploadBlob_TooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Ни запись о файле, ни содержимое не сохраняются
+	uc := blob.NewBlobUseCase(mocks.NewMockBlobRepository(ctrl), mocks.NewMockBlobStore(ctrl), 4, time.Hour)
+
+	_, err := uc.UploadBlob(context.Background(), blob.UploadBlobInput{
+		UserID:  "user-1",
+		Content: strings.NewReader("12345"),
+	})
+
+	if !errors.Is(err, blob.ErrBlobTooLarge) {
+		t.Errorf("err = %v, want ErrBlobTooLarge", err)
+	}
+}
+
+func TestUploadBlob_ReserveFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blobRepo := mocks.NewMockBlobRepository(ctrl)
+	blobRepo.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(false, errors.New("db down"))
+
+	uc := blob.NewBlobUseCase(blobRepo, mocks.NewMockBlobStore(ctrl), blob.DefaultMaxBlobSize, time.Hour)
+	if _, err := uc.UploadBlob(context.Background(), blob.UploadBlobInput{UserID: "user-1", Content: strings.NewReader("sealed")}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestUploadBlob_PutFailsDropsReservation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blobRepo := mocks.NewMockBlobRepository(ctrl)
+	blobRepo.EXPECT().Reserve(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, b *models.Blob) (bool, error) {
+			b.ID = "blob-1"
+			return false, nil
+		})
+	blobRepo.EXPECT().Delete(gomock.Any(), "blob-1").Return(nil)
+	blobStore := mocks.NewMockBlobStore(ctrl)
+	blobStore.EXPECT().Put(gomock.Any(), gomock.Any()).Return("", int64(0), errors.New("disk full"))
+
+	uc := blob.NewBlobUseCase(blobRepo, blobStore, blob.DefaultMaxBlobSize, time.Hour)
+	if _, err := uc.UploadBlob(context.Background(), blob.UploadBlobInput{UserID: "user-1", Content: strings.NewReader("sealed")}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// Очистка уже удаляет то же содержимое: загрузка ждёт конца удаления и сохраняет содержимое заново
+func TestUploadBlob_WaitsForPurgeOfSameContent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blobRepo := mocks.NewMockBlobRepository(ctrl)
+	blobStore := mocks.NewMockBlobStore(ctrl)
+	gomock.InOrder(
+		blobRepo.EXPECT().Reserve(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, b *models.Blob) (bool, error) {
+				b.ID = "blob-1"
+				return true, nil
+			}),
+		blobRepo.EXPECT().ContentPurging(gomock.Any(), sha("sealed")).Return(true, nil),
+		blobRepo.EXPECT().ContentPurging(gomock.Any(), sha("sealed")).Return(false, nil),
+		blobStore.EXPECT().Put(gomock.Any(), gomock.Any()).DoAndReturn(storeContent(t, "sealed")),
+	)
+
+	uc := blob.NewBlobUseCase(blobRepo, blobStore, blob.DefaultMaxBlobSize, time.Hour)
+	out, err := uc.UploadBlob(context.Background(), blob.UploadBlobInput{UserID: "user-1", Content: strings.NewReader("sealed")})
+	if err != nil {
+		t.Fatalf("UploadBlob: %v", err)
+	}
+	if out.BlobID != "blob-1" {
+		t.Errorf("out = %+v, want blob-1", out)
+	}
+}
@@ -0,0 +1,54 @@
+package data
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid sync cursor")
+
+const (
+	cursorVersion  byte = 1
+	cursorSize          = 1 + 1 + 8 + 8 // версия | флаги | seq | время выдачи
+	cursorFlagFull byte = 1 << 0
+)
+
+// syncCursor — позиция клиента в последовательности изменений пользователя.
+// Клиент получает курсор как непрозрачную строку и возвращает её без изменений.
+type syncCursor struct {
+	// Seq — номер последнего полученного изменения
+	Seq int64
+	// IssuedAt — когда выдан курсор; старше срока хранения tombstones — нужен полный снимок
+	IssuedAt time.Time
+	// Full — идёт постраничная выдача полного снимка
+	Full bool
+}
+
+func (c syncCursor) encode() string {
+	buf := make([]byte, cursorSize)
+	buf[0] = cursorVersion
+	if c.Full {
+		buf[1] |= cursorFlagFull
+	}
+	binary.BigEndian.PutUint64(buf[2:10], uint64(c.Seq))
+	binary.BigEndian.PutUint64(buf[10:18], uint64(c.IssuedAt.Unix()))
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func decodeCursor(s string) (syncCursor, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(buf) != cursorSize || buf[0] != cursorVersion {
+		return syncCursor{}, ErrInvalidCursor
+	}
+	c := syncCursor{
+		Full:     buf[1]&cursorFlagFull != 0,
+		Seq:      int64(binary.BigEndian.Uint64(buf[2:10])),
+		IssuedAt: time.Unix(int64(binary.BigEndian.Uint64(buf[10:18])), 0),
+	}
+	if c.Seq < 0 {
+		return syncCursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
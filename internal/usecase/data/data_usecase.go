@@ -1,17 +1,26 @@
 package data
 
 import (
+	"time"
+
 	"github.com/gophkeeper/gophkeeper/internal/domain/repository"
 )
 
+// DefaultTombstoneRetention — сколько хранятся отметки об удалении записей
+const DefaultTombstoneRetention = 30 * 24 * time.Hour
+
 // DataUseCase объединяет сценарии работы с данными пользователя
 type DataUseCase struct {
 	dataRepo repository.DataRepository
+	// tombstoneRetention — срок хранения удалённых записей; клиент, не синхронизировавшийся дольше,
+	// получает полный снимок данных вместо изменений
+	tombstoneRetention time.Duration
 }
 
 // NewDataUseCase создаёт use case данных
-func NewDataUseCase(dataRepo repository.DataRepository) *DataUseCase {
+func NewDataUseCase(dataRepo repository.DataRepository, tombstoneRetention time.Duration) *DataUseCase {
 	return &DataUseCase{
-		dataRepo: dataRepo,
+		dataRepo:           dataRepo,
+		tombstoneRetention: tombstoneRetention,
 	}
 }
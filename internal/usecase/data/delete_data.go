@@ -3,6 +3,8 @@ package data
 import (
 	"context"
 	"errors"
+
+	"github.com/gophkeeper/gophkeeper/internal/domain/repository"
 )
 
 var (
@@ -15,10 +17,14 @@ type DeleteDataInput struct {
 	DataID string
 }
 
-// DeleteData удаляет данные пользователя
+// DeleteData удаляет данные пользователя; ErrDataNotFound, если записи нет или она уже удалена
 func (uc *DataUseCase) DeleteData(ctx context.Context, in DeleteDataInput) error {
 	if in.DataID == "" {
 		return ErrDataIDRequiredForDelete
 	}
-	return uc.dataRepo.Delete(ctx, in.UserID, in.DataID)
+	err := uc.dataRepo.Delete(ctx, in.UserID, in.DataID)
+	if errors.Is(err, repository.ErrDataNotFound) {
+		return ErrDataNotFound
+	}
+	return err
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/gophkeeper/gophkeeper/internal/domain/repository"
 	"github.com/gophkeeper/gophkeeper/internal/domain/repository/mocks"
 	"github.com/gophkeeper/gophkeeper/internal/usecase/data"
 	"go.uber.org/mock/gomock"
@@ -19,7 +20,7 @@ func TestDeleteData_Success(t *testing.T) {
 		Delete(gomock.Any(), "user-1", "data-1").
 		Return(nil)
 
-	uc := data.NewDataUseCase(dataRepo)
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
 	err := uc.DeleteData(context.Background(), data.DeleteDataInput{
 		UserID: "user-1",
 		DataID: "data-1",
@@ -35,7 +36,7 @@ func TestDeleteData_DataIDRequired(t *testing.T) {
 	defer ctrl.Finish()
 
 	dataRepo := mocks.NewMockDataRepository(ctrl)
-	uc := data.NewDataUseCase(dataRepo)
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
 
 	err := uc.DeleteData(context.Background(), data.DeleteDataInput{
 		UserID: "user-1",
@@ -60,7 +61,7 @@ func TestDeleteData_RepoError(t *testing.T) {
 		Delete(gomock.Any(), "user-1", "data-1").
 		Return(wantErr)
 
-	uc := data.NewDataUseCase(dataRepo)
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
 	err := uc.DeleteData(context.Background(), data.DeleteDataInput{
 		UserID: "user-1",
 		DataID: "data-1",
@@ -70,3 +71,23 @@ func TestDeleteData_RepoError(t *testing.T) {
 		t.Errorf("err = %v, want %v", err, wantErr)
 	}
 }
+
+func TestDeleteData_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataRepo := mocks.NewMockDataRepository(ctrl)
+	dataRepo.EXPECT().
+		Delete(gomock.Any(), "user-1", "missing").
+		Return(repository.ErrDataNotFound)
+
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
+	err := uc.DeleteData(context.Background(), data.DeleteDataInput{
+		UserID: "user-1",
+		DataID: "missing",
+	})
+
+	if !errors.Is(err, data.ErrDataNotFound) {
+		t.Errorf("err = %v, want ErrDataNotFound", err)
+	}
+}
@@ -21,7 +21,7 @@ func TestGetData_Success(t *testing.T) {
 		Get(gomock.Any(), "user-1", "data-1").
 		Return(item, nil)
 
-	uc := data.NewDataUseCase(dataRepo)
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
 	out, err := uc.GetData(context.Background(), data.GetDataInput{
 		UserID: "user-1",
 		DataID: "data-1",
@@ -43,7 +43,7 @@ func TestGetData_DataIDRequired(t *testing.T) {
 	defer ctrl.Finish()
 
 	dataRepo := mocks.NewMockDataRepository(ctrl)
-	uc := data.NewDataUseCase(dataRepo)
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
 
 	_, err := uc.GetData(context.Background(), data.GetDataInput{
 		UserID: "user-1",
@@ -67,7 +67,7 @@ func TestGetData_NotFound(t *testing.T) {
 		Get(gomock.Any(), "user-1", "missing").
 		Return(nil, nil)
 
-	uc := data.NewDataUseCase(dataRepo)
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
 	_, err := uc.GetData(context.Background(), data.GetDataInput{
 		UserID: "user-1",
 		DataID: "missing",
@@ -91,7 +91,7 @@ func TestGetData_RepoError(t *testing.T) {
 		Get(gomock.Any(), "user-1", "data-1").
 		Return(nil, wantErr)
 
-	uc := data.NewDataUseCase(dataRepo)
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
 	_, err := uc.GetData(context.Background(), data.GetDataInput{
 		UserID: "user-1",
 		DataID: "data-1",
@@ -0,0 +1,84 @@
+package data
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/gophkeeper/gophkeeper/internal/models"
+)
+
+const (
+	// DefaultSummaryPageSize — размер страницы кратких описаний, если клиент его не указал
+	DefaultSummaryPageSize = 100
+	// MaxSummaryPageSize — максимальный размер страницы кратких описаний
+	MaxSummaryPageSize = 500
+)
+
+// ListDataSummariesInput входные данные для страницы кратких описаний
+type ListDataSummariesInput struct {
+	UserID   string
+	DataType models.DataType
+	// Sort — поле сортировки (пусто — по времени изменения)
+	Sort       models.DataSort
+	Descending bool
+	PageSize   int
+	// PageToken — токен из предыдущего ответа (пусто — первая страница)
+	PageToken string
+}
+
+// ListDataSummariesOutput страница кратких описаний
+type ListDataSummariesOutput struct {
+	Items []*models.DataSummary
+	// NextPageToken — токен следующей страницы (пусто — страниц больше нет)
+	NextPageToken string
+}
+
+// ListDataSummaries возвращает страницу кратких описаний записей пользователя без зашифрованного содержимого.
+// Страницы задаются смещением: если между запросами записи добавляются или удаляются, граница страницы
+// может сдвинуться, поэтому список целиком перечитывается при обновлении, а актуальную запись
+// клиент получает через GetData при открытии.
+func (uc *DataUseCase) ListDataSummaries(ctx context.Context, in ListDataSummariesInput) (*ListDataSummariesOutput, error) {
+	q := models.DataSummaryQuery{Sort: in.Sort, Desc: in.Descending}
+	if in.DataType != "" {
+		q.Filter.Types = []models.DataType{in.DataType}
+	}
+	return uc.page(ctx, in.UserID, q, in.PageSize, in.PageToken)
+}
+
+// page возвращает страницу кратких описаний по условиям q, начиная с позиции из token
+func (uc *DataUseCase) page(ctx context.Context, userID string, q models.DataSummaryQuery, pageSize int, token string) (*ListDataSummariesOutput, error) {
+	if q.Sort == "" {
+		q.Sort = models.DataSortUpdatedAt
+	}
+	hash := queryHash(q)
+	if token != "" {
+		t, err := decodePageToken(token)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(t.QueryHash, hash) {
+			return nil, ErrInvalidPageToken
+		}
+		q.Offset = t.Offset
+	}
+
+	if pageSize <= 0 {
+		pageSize = DefaultSummaryPageSize
+	}
+	if pageSize > MaxSummaryPageSize {
+		pageSize = MaxSummaryPageSize
+	}
+	q.Limit = pageSize + 1
+
+	items, err := uc.dataRepo.ListSummaries(ctx, userID, q)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &ListDataSummariesOutput{Items: items}
+	if len(items) > pageSize {
+		out.Items = items[:pageSize]
+		out.NextPageToken = pageToken{Offset: q.Offset + pageSize, QueryHash: hash}.encode()
+	}
+	return out, nil
+}
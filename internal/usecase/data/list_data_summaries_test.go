@@ -0,0 +1,119 @@
+package data_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gophkeeper/gophkeeper/internal/domain/repository/mocks"
+	"github.com/gophkeeper/gophkeeper/internal/models"
+	"github.com/gophkeeper/gophkeeper/internal/usecase/data"
+	"go.uber.org/mock/gomock"
+)
+
+// summaries возвращает n кратких описаний, начиная с номера from
+func summaries(from, n int) []*models.DataSummary {
+	items := make([]*models.DataSummary, n)
+	for i := range items {
+		items[i] = &models.DataSummary{ID: fmt.Sprintf("data-%d", from+i)}
+	}
+	return items
+}
+
+func TestListDataSummaries_Pages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataRepo := mocks.NewMockDataRepository(ctrl)
+	gomock.InOrder(
+		dataRepo.EXPECT().
+			ListSummaries(gomock.Any(), "user-1", models.DataSummaryQuery{
+				Filter: models.DataFilter{Types: []models.DataType{models.DataTypeText}}, Sort: models.DataSortName, Desc: true, Offset: 0, Limit: 3,
+			}).
+			Return(summaries(0, 3), nil),
+		dataRepo.EXPECT().
+			ListSummaries(gomock.Any(), "user-1", models.DataSummaryQuery{
+				Filter: models.DataFilter{Types: []models.DataType{models.DataTypeText}}, Sort: models.DataSortName, Desc: true, Offset: 2, Limit: 3,
+			}).
+			Return(summaries(2, 1), nil),
+	)
+
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
+	in := data.ListDataSummariesInput{
+		UserID:     "user-1",
+		DataType:   models.DataTypeText,
+		Sort:       models.DataSortName,
+		Descending: true,
+		PageSize:   2,
+	}
+	first, err := uc.ListDataSummaries(context.Background(), in)
+	if err != nil {
+		t.Fatalf("first page: %v", err)
+	}
+	if len(first.Items) != 2 || first.NextPageToken == "" {
+		t.Fatalf("first page = %d items, token %q; want 2 items and a token", len(first.Items), first.NextPageToken)
+	}
+
+	in.PageToken = first.NextPageToken
+	second, err := uc.ListDataSummaries(context.Background(), in)
+	if err != nil {
+		t.Fatalf("second page: %v", err)
+	}
+	if len(second.Items) != 1 || second.Items[0].ID != "data-2" || second.NextPageToken != "" {
+		t.Errorf("second page = %d items, token %q; want data-2 only and no token", len(second.Items), second.NextPageToken)
+	}
+}
+
+func TestListDataSummaries_Defaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataRepo := mocks.NewMockDataRepository(ctrl)
+	dataRepo.EXPECT().
+		ListSummaries(gomock.Any(), "user-1", models.DataSummaryQuery{
+			Sort: models.DataSortUpdatedAt, Limit: data.MaxSummaryPageSize + 1,
+		}).
+		Return(nil, nil)
+
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
+	out, err := uc.ListDataSummaries(context.Background(), data.ListDataSummariesInput{UserID: "user-1", PageSize: 10000})
+
+	if err != nil {
+		t.Fatalf("ListDataSummaries: %v", err)
+	}
+	if len(out.Items) != 0 || out.NextPageToken != "" {
+		t.Errorf("out = %+v, want empty page", out)
+	}
+}
+
+func TestListDataSummaries_TokenForOtherQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataRepo := mocks.NewMockDataRepository(ctrl)
+	dataRepo.EXPECT().
+		ListSummaries(gomock.Any(), "user-1", gomock.Any()).
+		Return(summaries(0, 2), nil)
+
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
+	first, err := uc.ListDataSummaries(context.Background(), data.ListDataSummariesInput{UserID: "user-1", PageSize: 1})
+	if err != nil {
+		t.Fatalf("first page: %v", err)
+	}
+
+	_, err = uc.ListDataSummaries(context.Background(), data.ListDataSummariesInput{
+		UserID:    "user-1",
+		Sort:      models.DataSortName,
+		PageSize:  1,
+		PageToken: first.NextPageToken,
+	})
+	if !errors.Is(err, data.ErrInvalidPageToken) {
+		t.Errorf("other sort: err = %v, want ErrInvalidPageToken", err)
+	}
+
+	_, err = uc.ListDataSummaries(context.Background(), data.ListDataSummariesInput{UserID: "user-1", PageToken: "garbage"})
+	if !errors.Is(err, data.ErrInvalidPageToken) {
+		t.Errorf("garbage: err = %v, want ErrInvalidPageToken", err)
+	}
+}
@@ -24,7 +24,7 @@ func TestListData_Success(t *testing.T) {
 		List(gomock.Any(), "user-1", models.DataTypeText).
 		Return(items, nil)
 
-	uc := data.NewDataUseCase(dataRepo)
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
 	out, err := uc.ListData(context.Background(), data.ListDataInput{
 		UserID:   "user-1",
 		DataType: models.DataTypeText,
@@ -50,7 +50,7 @@ func TestListData_EmptyList(t *testing.T) {
 		List(gomock.Any(), "user-1", models.DataType("")).
 		Return(nil, nil)
 
-	uc := data.NewDataUseCase(dataRepo)
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
 	out, err := uc.ListData(context.Background(), data.ListDataInput{
 		UserID:   "user-1",
 		DataType: "",
@@ -74,7 +74,7 @@ func TestListData_RepoError(t *testing.T) {
 		List(gomock.Any(), "user-1", gomock.Any()).
 		Return(nil, wantErr)
 
-	uc := data.NewDataUseCase(dataRepo)
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
 	_, err := uc.ListData(context.Background(), data.ListDataInput{
 		UserID:   "user-1",
 		DataType: models.DataTypeText,
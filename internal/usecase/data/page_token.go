@@ -0,0 +1,61 @@
+package data
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/gophkeeper/gophkeeper/internal/models"
+)
+
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+const (
+	pageTokenVersion byte = 2
+	pageQueryHashLen      = 8
+	pageTokenSize         = 1 + 4 + pageQueryHashLen // версия | смещение | отпечаток запроса
+)
+
+// pageToken — позиция в списке кратких описаний. Токен привязан к отпечатку запроса (фильтр и сортировка),
+// для которого он выдан: с другими условиями он не принимается. Клиент получает токен как непрозрачную строку.
+type pageToken struct {
+	Offset    int
+	QueryHash []byte
+}
+
+func (t pageToken) encode() string {
+	buf := make([]byte, pageTokenSize)
+	buf[0] = pageTokenVersion
+	binary.BigEndian.PutUint32(buf[1:5], uint32(t.Offset))
+	copy(buf[5:], t.QueryHash)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func decodePageToken(s string) (pageToken, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(buf) != pageTokenSize || buf[0] != pageTokenVersion {
+		return pageToken{}, ErrInvalidPageToken
+	}
+	return pageToken{
+		Offset:    int(binary.BigEndian.Uint32(buf[1:5])),
+		QueryHash: buf[5:],
+	}, nil
+}
+
+// queryHash возвращает отпечаток условий выборки без смещения и размера страницы
+func queryHash(q models.DataSummaryQuery) []byte {
+	f := q.Filter
+	h := sha256.New()
+	// Порядок типов, ключей и тегов не меняет результат
+	fmt.Fprintf(h, "%q|%t|%q|%q|%q|%q|%q|%q|%d",
+		q.Sort, q.Desc,
+		slices.Sorted(slices.Values(f.Types)),
+		f.NamePrefix, f.NameContains, f.SearchToken,
+		slices.Sorted(slices.Values(f.MetadataKeys)),
+		slices.Sorted(slices.Values(f.Tags)),
+		f.UpdatedSince.UnixNano())
+	return h.Sum(nil)[:pageQueryHashLen]
+}
@@ -0,0 +1,12 @@
+package data
+
+import (
+	"context"
+	"time"
+)
+
+// PurgeDeleted окончательно удаляет записи, отметки об удалении которых старше срока хранения.
+// Возвращает число удалённых записей.
+func (uc *DataUseCase) PurgeDeleted(ctx context.Context) (int64, error) {
+	return uc.dataRepo.PurgeDeleted(ctx, time.Now().Add(-uc.tombstoneRetention))
+}
@@ -0,0 +1,37 @@
+package data_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gophkeeper/gophkeeper/internal/domain/repository/mocks"
+	"github.com/gophkeeper/gophkeeper/internal/usecase/data"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPurgeDeleted_UsesRetention(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	retention := 24 * time.Hour
+	dataRepo := mocks.NewMockDataRepository(ctrl)
+	dataRepo.EXPECT().
+		PurgeDeleted(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, before time.Time) (int64, error) {
+			if age := time.Since(before); age < retention || age > retention+time.Minute {
+				t.Errorf("before = %v, want about now - %v", before, retention)
+			}
+			return 2, nil
+		})
+
+	uc := data.NewDataUseCase(dataRepo, retention)
+	n, err := uc.PurgeDeleted(context.Background())
+
+	if err != nil {
+		t.Fatalf("PurgeDeleted: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("n = %d, want 2", n)
+	}
+}
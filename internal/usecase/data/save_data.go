@@ -4,17 +4,35 @@ import (
 	"context"
 	"errors"
 
+	"github.com/gophkeeper/gophkeeper/internal/domain/repository"
 	"github.com/gophkeeper/gophkeeper/internal/models"
 )
 
 var (
-	ErrDataRequired = errors.New("data is required")
+	ErrDataRequired    = errors.New("data is required")
+	ErrVersionConflict = errors.New("data has been modified by another client")
 )
 
+// ConflictError возвращается, когда запись изменена после того, как клиент её прочитал.
+// Current — актуальная копия записи на сервере (nil, если запись удалена).
+type ConflictError struct {
+	Current *models.Data
+}
+
+func (e *ConflictError) Error() string {
+	return ErrVersionConflict.Error()
+}
+
+func (e *ConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
+
 // SaveDataInput входные данные для сохранения
 type SaveDataInput struct {
 	UserID string
 	Data   *models.Data
+	// BaseVersion — версия, от которой клиент вносил изменения (0 — создание новой записи)
+	BaseVersion int64
 }
 
 // SaveDataOutput результат сохранения
@@ -23,13 +41,18 @@ type SaveDataOutput struct {
 	Version int64
 }
 
-// SaveData сохраняет или обновляет данные пользователя
+// SaveData сохраняет или обновляет данные пользователя.
+// Обновление применяется, только если версия на сервере совпадает с BaseVersion, иначе возвращается *ConflictError.
 func (uc *DataUseCase) SaveData(ctx context.Context, in SaveDataInput) (*SaveDataOutput, error) {
 	if in.Data == nil {
 		return nil, ErrDataRequired
 	}
 
-	if err := uc.dataRepo.Save(ctx, in.UserID, in.Data); err != nil {
+	if err := uc.dataRepo.Save(ctx, in.UserID, in.Data, in.BaseVersion); err != nil {
+		var conflict *repository.VersionConflictError
+		if errors.As(err, &conflict) {
+			return nil, &ConflictError{Current: conflict.Current}
+		}
 		return nil, err
 	}
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/gophkeeper/gophkeeper/internal/domain/repository"
 	"github.com/gophkeeper/gophkeeper/internal/domain/repository/mocks"
 	"github.com/gophkeeper/gophkeeper/internal/models"
 	"github.com/gophkeeper/gophkeeper/internal/usecase/data"
@@ -17,10 +18,10 @@ func TestSaveData_Success(t *testing.T) {
 
 	dataRepo := mocks.NewMockDataRepository(ctrl)
 	dataRepo.EXPECT().
-		Save(gomock.Any(), "user-1", gomock.Any()).
+		Save(gomock.Any(), "user-1", gomock.Any(), int64(0)).
 		Return(nil)
 
-	uc := data.NewDataUseCase(dataRepo)
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
 	item := &models.Data{ID: "data-1", Name: "test", Type: models.DataTypeText, Version: 1}
 	out, err := uc.SaveData(context.Background(), data.SaveDataInput{
 		UserID: "user-1",
@@ -43,7 +44,7 @@ func TestSaveData_DataRequired(t *testing.T) {
 	defer ctrl.Finish()
 
 	dataRepo := mocks.NewMockDataRepository(ctrl)
-	uc := data.NewDataUseCase(dataRepo)
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
 
 	_, err := uc.SaveData(context.Background(), data.SaveDataInput{
 		UserID: "user-1",
@@ -65,10 +66,10 @@ func TestSaveData_RepoError(t *testing.T) {
 	wantErr := errors.New("db error")
 	dataRepo := mocks.NewMockDataRepository(ctrl)
 	dataRepo.EXPECT().
-		Save(gomock.Any(), "user-1", gomock.Any()).
+		Save(gomock.Any(), "user-1", gomock.Any(), int64(0)).
 		Return(wantErr)
 
-	uc := data.NewDataUseCase(dataRepo)
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
 	_, err := uc.SaveData(context.Background(), data.SaveDataInput{
 		UserID: "user-1",
 		Data:   &models.Data{ID: "data-1", Name: "test", Type: models.DataTypeText},
@@ -78,3 +79,56 @@ func TestSaveData_RepoError(t *testing.T) {
 		t.Errorf("err = %v, want %v", err, wantErr)
 	}
 }
+
+func TestSaveData_PassesBaseVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataRepo := mocks.NewMockDataRepository(ctrl)
+	dataRepo.EXPECT().
+		Save(gomock.Any(), "user-1", gomock.Any(), int64(3)).
+		DoAndReturn(func(_ context.Context, _ string, d *models.Data, _ int64) error {
+			d.Version = 4
+			return nil
+		})
+
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
+	out, err := uc.SaveData(context.Background(), data.SaveDataInput{
+		UserID:      "user-1",
+		Data:        &models.Data{ID: "data-1", Name: "test", Type: models.DataTypeText},
+		BaseVersion: 3,
+	})
+
+	if err != nil {
+		t.Fatalf("SaveData: %v", err)
+	}
+	if out.Version != 4 {
+		t.Errorf("Version = %d, want 4", out.Version)
+	}
+}
+
+func TestSaveData_VersionConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	current := &models.Data{ID: "data-1", Name: "server copy", Version: 5}
+	dataRepo := mocks.NewMockDataRepository(ctrl)
+	dataRepo.EXPECT().
+		Save(gomock.Any(), "user-1", gomock.Any(), int64(3)).
+		Return(&repository.VersionConflictError{Current: current})
+
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
+	_, err := uc.SaveData(context.Background(), data.SaveDataInput{
+		UserID:      "user-1",
+		Data:        &models.Data{ID: "data-1", Name: "local copy", Type: models.DataTypeText},
+		BaseVersion: 3,
+	})
+
+	if !errors.Is(err, data.ErrVersionConflict) {
+		t.Fatalf("err = %v, want ErrVersionConflict", err)
+	}
+	var conflict *data.ConflictError
+	if !errors.As(err, &conflict) || conflict.Current != current {
+		t.Errorf("conflict = %+v, want current server copy", conflict)
+	}
+}
@@ -0,0 +1,51 @@
+package data
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gophkeeper/gophkeeper/internal/models"
+)
+
+// SearchDataInput входные данные для поиска записей
+type SearchDataInput struct {
+	UserID string
+	Filter models.DataFilter
+	// Sort — поле сортировки (пусто — по времени изменения)
+	Sort       models.DataSort
+	Descending bool
+	PageSize   int
+	// PageToken — токен из предыдущего ответа с теми же условиями (пусто — первая страница)
+	PageToken string
+}
+
+// SearchData возвращает страницу кратких описаний записей, подходящих под все условия фильтра.
+// Названия, ключи и теги сравниваются без учёта регистра; у записей с зашифрованным названием
+// оно не хранится на сервере, и найти их можно только по точному токену (Filter.SearchToken).
+func (uc *DataUseCase) SearchData(ctx context.Context, in SearchDataInput) (*ListDataSummariesOutput, error) {
+	return uc.page(ctx, in.UserID, models.DataSummaryQuery{
+		Filter: normalizeFilter(in.Filter),
+		Sort:   in.Sort,
+		Desc:   in.Descending,
+	}, in.PageSize, in.PageToken)
+}
+
+// normalizeFilter приводит строки фильтра к нижнему регистру и убирает пустые ключи и теги,
+// чтобы одинаковые по смыслу запросы давали одинаковый отпечаток для токена страницы
+func normalizeFilter(f models.DataFilter) models.DataFilter {
+	f.NamePrefix = strings.ToLower(f.NamePrefix)
+	f.NameContains = strings.ToLower(f.NameContains)
+	f.MetadataKeys = normalizeTerms(f.MetadataKeys)
+	f.Tags = normalizeTerms(f.Tags)
+	return f
+}
+
+func normalizeTerms(terms []string) []string {
+	var out []string
+	for _, t := range terms {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,89 @@
+package data_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gophkeeper/gophkeeper/internal/domain/repository/mocks"
+	"github.com/gophkeeper/gophkeeper/internal/models"
+	"github.com/gophkeeper/gophkeeper/internal/usecase/data"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSearchData_NormalizesFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	since := time.Unix(1700000000, 0)
+	dataRepo := mocks.NewMockDataRepository(ctrl)
+	dataRepo.EXPECT().
+		ListSummaries(gomock.Any(), "user-1", models.DataSummaryQuery{
+			Filter: models.DataFilter{
+				Types:        []models.DataType{models.DataTypeLoginPassword},
+				NamePrefix:   "банк",
+				MetadataKeys: []string{"url"},
+				Tags:         []string{"work", "finance"},
+				UpdatedSince: since,
+			},
+			Sort:  models.DataSortName,
+			Limit: 11,
+		}).
+		Return(summaries(0, 3), nil)
+
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
+	out, err := uc.SearchData(context.Background(), data.SearchDataInput{
+		UserID: "user-1",
+		Filter: models.DataFilter{
+			Types:        []models.DataType{models.DataTypeLoginPassword},
+			NamePrefix:   "Банк",
+			MetadataKeys: []string{" URL "},
+			Tags:         []string{"Work", "", "finance"},
+			UpdatedSince: since,
+		},
+		Sort:     models.DataSortName,
+		PageSize: 10,
+	})
+
+	if err != nil {
+		t.Fatalf("SearchData: %v", err)
+	}
+	if len(out.Items) != 3 || out.NextPageToken != "" {
+		t.Errorf("out = %d items, token %q; want 3 items and no token", len(out.Items), out.NextPageToken)
+	}
+}
+
+func TestSearchData_TokenBoundToFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataRepo := mocks.NewMockDataRepository(ctrl)
+	dataRepo.EXPECT().
+		ListSummaries(gomock.Any(), "user-1", gomock.Any()).
+		Return(summaries(0, 2), nil).
+		Times(2)
+
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
+	in := data.SearchDataInput{
+		UserID:   "user-1",
+		Filter:   models.DataFilter{Tags: []string{"work", "home"}},
+		PageSize: 1,
+	}
+	first, err := uc.SearchData(context.Background(), in)
+	if err != nil {
+		t.Fatalf("first page: %v", err)
+	}
+
+	// Тот же фильтр с тегами в другом порядке — тот же запрос
+	in.Filter.Tags = []string{"HOME", "work"}
+	in.PageToken = first.NextPageToken
+	if _, err := uc.SearchData(context.Background(), in); err != nil {
+		t.Errorf("same filter: %v", err)
+	}
+
+	in.Filter.Tags = []string{"home"}
+	if _, err := uc.SearchData(context.Background(), in); !errors.Is(err, data.ErrInvalidPageToken) {
+		t.Errorf("other filter: err = %v, want ErrInvalidPageToken", err)
+	}
+}
@@ -7,34 +7,82 @@ import (
 	"github.com/gophkeeper/gophkeeper/internal/models"
 )
 
+const (
+	// DefaultSyncPageSize — размер страницы синхронизации, если клиент его не указал
+	DefaultSyncPageSize = 500
+	// MaxSyncPageSize — максимальный размер страницы синхронизации
+	MaxSyncPageSize = 1000
+)
+
 // SyncDataInput входные данные для синхронизации
 type SyncDataInput struct {
-	UserID       string
-	LastSyncTime time.Time
+	UserID string
+	// Cursor — курсор из предыдущего ответа (пусто — первая синхронизация)
+	Cursor   string
+	PageSize int
 }
 
 // SyncDataOutput результат синхронизации
 type SyncDataOutput struct {
-	Items    []*models.Data
-	SyncTime time.Time
+	Items []*models.Data
+	// Deleted — записи, удалённые после позиции курсора (tombstones)
+	Deleted []*models.Data
+	// FullResync — страница полного снимка: после последней страницы клиент должен удалить у себя записи,
+	// которых не было в ответах (первая синхронизация или отметки об удалении уже очищены)
+	FullResync bool
+	// NextCursor — курсор для следующего запроса
+	NextCursor string
+	// HasMore — есть ещё страницы, запрашивать сразу с NextCursor
+	HasMore bool
 }
 
-// SyncData возвращает данные, изменённые после указанного времени
+// SyncData возвращает страницу изменений после позиции курсора, включая отметки об удалённых записях.
+// Изменения упорядочены по номеру в последовательности пользователя, поэтому записи, изменённые
+// в одну секунду или во время запроса, не теряются.
 func (uc *DataUseCase) SyncData(ctx context.Context, in SyncDataInput) (*SyncDataOutput, error) {
-	var items []*models.Data
-	var err error
+	now := time.Now()
 
-	if in.LastSyncTime.IsZero() {
-		items, err = uc.dataRepo.List(ctx, in.UserID, "")
-	} else {
-		items, err = uc.dataRepo.GetSince(ctx, in.UserID, in.LastSyncTime)
+	cursor := syncCursor{Full: true}
+	if in.Cursor != "" {
+		c, err := decodeCursor(in.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = c
+		if !cursor.Full && cursor.IssuedAt.Before(now.Add(-uc.tombstoneRetention)) {
+			// Отметки об удалении после позиции курсора могли быть очищены — начинаем полный снимок
+			cursor = syncCursor{Full: true}
+		}
+	}
+
+	pageSize := in.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultSyncPageSize
+	}
+	if pageSize > MaxSyncPageSize {
+		pageSize = MaxSyncPageSize
 	}
+
+	changes, err := uc.dataRepo.GetChanges(ctx, in.UserID, cursor.Seq, pageSize+1)
 	if err != nil {
 		return nil, err
 	}
 
-	return &SyncDataOutput{
-		Items:    items,
-		SyncTime: time.Now(),
-	}, nil
+	out := &SyncDataOutput{FullResync: cursor.Full}
+	if len(changes) > pageSize {
+		changes = changes[:pageSize]
+		out.HasMore = true
+	}
+
+	next := syncCursor{Seq: cursor.Seq, IssuedAt: now, Full: cursor.Full && out.HasMore}
+	for _, d := range changes {
+		if d.DeletedAt.Valid {
+			out.Deleted = append(out.Deleted, d)
+		} else {
+			out.Items = append(out.Items, d)
+		}
+		next.Seq = d.Seq
+	}
+	out.NextCursor = next.encode()
+	return out, nil
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/gophkeeper/gophkeeper/internal/models"
 	"github.com/gophkeeper/gophkeeper/internal/usecase/data"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 )
 
 func TestSyncData_AllData(t *testing.T) {
@@ -17,17 +18,16 @@ func TestSyncData_AllData(t *testing.T) {
 	defer ctrl.Finish()
 
 	items := []*models.Data{
-		{ID: "data-1", UserID: "user-1", Name: "a", Type: models.DataTypeText},
+		{ID: "data-1", UserID: "user-1", Name: "a", Type: models.DataTypeText, Seq: 4},
 	}
 	dataRepo := mocks.NewMockDataRepository(ctrl)
 	dataRepo.EXPECT().
-		List(gomock.Any(), "user-1", models.DataType("")).
+		GetChanges(gomock.Any(), "user-1", int64(0), data.DefaultSyncPageSize+1).
 		Return(items, nil)
 
-	uc := data.NewDataUseCase(dataRepo)
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
 	out, err := uc.SyncData(context.Background(), data.SyncDataInput{
-		UserID:       "user-1",
-		LastSyncTime: time.Time{},
+		UserID: "user-1",
 	})
 
 	if err != nil {
@@ -39,38 +39,164 @@ func TestSyncData_AllData(t *testing.T) {
 	if out.Items[0].ID != "data-1" {
 		t.Errorf("Items[0].ID = %q, want data-1", out.Items[0].ID)
 	}
-	if out.SyncTime.IsZero() {
-		t.Error("SyncTime should be set")
+	if !out.FullResync || out.HasMore {
+		t.Errorf("FullResync = %v, HasMore = %v", out.FullResync, out.HasMore)
+	}
+	if out.NextCursor == "" {
+		t.Error("NextCursor should be set")
 	}
 }
 
-func TestSyncData_Since(t *testing.T) {
+func TestSyncData_ResumesFromCursor(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	since := time.Now().Add(-time.Hour)
-	items := []*models.Data{
-		{ID: "data-2", UserID: "user-1", Name: "b", Type: models.DataTypeText},
-	}
 	dataRepo := mocks.NewMockDataRepository(ctrl)
-	dataRepo.EXPECT().
-		GetSince(gomock.Any(), "user-1", since).
-		Return(items, nil)
+	gomock.InOrder(
+		dataRepo.EXPECT().
+			GetChanges(gomock.Any(), "user-1", int64(0), gomock.Any()).
+			Return([]*models.Data{{ID: "data-1", Seq: 7}}, nil),
+		// Второй запрос продолжает с номера последнего полученного изменения
+		dataRepo.EXPECT().
+			GetChanges(gomock.Any(), "user-1", int64(7), gomock.Any()).
+			Return([]*models.Data{{ID: "data-2", Seq: 8}}, nil),
+	)
+
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
+	first, err := uc.SyncData(context.Background(), data.SyncDataInput{UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("SyncData: %v", err)
+	}
 
-	uc := data.NewDataUseCase(dataRepo)
 	out, err := uc.SyncData(context.Background(), data.SyncDataInput{
-		UserID:       "user-1",
-		LastSyncTime: since,
+		UserID: "user-1",
+		Cursor: first.NextCursor,
 	})
+	if err != nil {
+		t.Fatalf("SyncData: %v", err)
+	}
+	if out.FullResync {
+		t.Error("FullResync should be false for an incremental sync")
+	}
+	if len(out.Items) != 1 || out.Items[0].ID != "data-2" {
+		t.Errorf("Items = %v, want [data-2]", out.Items)
+	}
+}
+
+func TestSyncData_Paging(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
+	dataRepo := mocks.NewMockDataRepository(ctrl)
+	gomock.InOrder(
+		dataRepo.EXPECT().
+			GetChanges(gomock.Any(), "user-1", int64(0), 3).
+			Return([]*models.Data{{ID: "a", Seq: 1}, {ID: "b", Seq: 2}, {ID: "c", Seq: 3}}, nil),
+		dataRepo.EXPECT().
+			GetChanges(gomock.Any(), "user-1", int64(2), 3).
+			Return([]*models.Data{{ID: "c", Seq: 3}}, nil),
+	)
+
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
+	first, err := uc.SyncData(context.Background(), data.SyncDataInput{UserID: "user-1", PageSize: 2})
 	if err != nil {
 		t.Fatalf("SyncData: %v", err)
 	}
-	if len(out.Items) != 1 {
-		t.Errorf("len(Items) = %d, want 1", len(out.Items))
+	if !first.HasMore || len(first.Items) != 2 {
+		t.Fatalf("first page: HasMore = %v, len(Items) = %d", first.HasMore, len(first.Items))
+	}
+
+	second, err := uc.SyncData(context.Background(), data.SyncDataInput{
+		UserID:   "user-1",
+		Cursor:   first.NextCursor,
+		PageSize: 2,
+	})
+	if err != nil {
+		t.Fatalf("SyncData: %v", err)
+	}
+	if second.HasMore || len(second.Items) != 1 {
+		t.Errorf("second page: HasMore = %v, len(Items) = %d", second.HasMore, len(second.Items))
+	}
+	// Страницы одного полного снимка помечены FullResync
+	if !first.FullResync || !second.FullResync {
+		t.Error("all pages of the initial sync should be marked FullResync")
+	}
+}
+
+func TestSyncData_Tombstones(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deletedAt := time.Now().Add(-time.Minute)
+	dataRepo := mocks.NewMockDataRepository(ctrl)
+	dataRepo.EXPECT().
+		GetChanges(gomock.Any(), "user-1", int64(0), gomock.Any()).
+		Return([]*models.Data{
+			{ID: "live", UserID: "user-1", Version: 2, Seq: 1},
+			{ID: "gone", UserID: "user-1", Version: 3, Seq: 2, DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true}},
+		}, nil)
+
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
+	out, err := uc.SyncData(context.Background(), data.SyncDataInput{UserID: "user-1"})
+
+	if err != nil {
+		t.Fatalf("SyncData: %v", err)
+	}
+	if len(out.Items) != 1 || out.Items[0].ID != "live" {
+		t.Errorf("Items = %v, want [live]", out.Items)
+	}
+	if len(out.Deleted) != 1 || out.Deleted[0].ID != "gone" {
+		t.Errorf("Deleted = %v, want [gone]", out.Deleted)
+	}
+}
+
+func TestSyncData_OlderThanRetention(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataRepo := mocks.NewMockDataRepository(ctrl)
+	dataRepo.EXPECT().
+		GetChanges(gomock.Any(), "user-1", int64(0), gomock.Any()).
+		Return([]*models.Data{{ID: "data-1", Seq: 5}}, nil)
+
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
+	first, err := uc.SyncData(context.Background(), data.SyncDataInput{UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("SyncData: %v", err)
 	}
-	if out.Items[0].ID != "data-2" {
-		t.Errorf("Items[0].ID = %q, want data-2", out.Items[0].ID)
+
+	// Курсор старше срока хранения: отметки об удалении могли быть очищены — снова полный снимок с начала
+	dataRepo.EXPECT().
+		GetChanges(gomock.Any(), "user-1", int64(0), gomock.Any()).
+		Return([]*models.Data{{ID: "data-1", Seq: 5}}, nil)
+
+	short := data.NewDataUseCase(dataRepo, -time.Second)
+	out, err := short.SyncData(context.Background(), data.SyncDataInput{
+		UserID: "user-1",
+		Cursor: first.NextCursor,
+	})
+	if err != nil {
+		t.Fatalf("SyncData: %v", err)
+	}
+	if !out.FullResync {
+		t.Error("FullResync should be set")
+	}
+}
+
+func TestSyncData_InvalidCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dataRepo := mocks.NewMockDataRepository(ctrl)
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
+
+	_, err := uc.SyncData(context.Background(), data.SyncDataInput{
+		UserID: "user-1",
+		Cursor: "not-a-cursor",
+	})
+
+	if !errors.Is(err, data.ErrInvalidCursor) {
+		t.Errorf("err = %v, want ErrInvalidCursor", err)
 	}
 }
 
@@ -81,13 +207,12 @@ func TestSyncData_RepoError(t *testing.T) {
 	wantErr := errors.New("db error")
 	dataRepo := mocks.NewMockDataRepository(ctrl)
 	dataRepo.EXPECT().
-		List(gomock.Any(), "user-1", models.DataType("")).
+		GetChanges(gomock.Any(), "user-1", int64(0), gomock.Any()).
 		Return(nil, wantErr)
 
-	uc := data.NewDataUseCase(dataRepo)
+	uc := data.NewDataUseCase(dataRepo, data.DefaultTombstoneRetention)
 	_, err := uc.SyncData(context.Background(), data.SyncDataInput{
-		UserID:       "user-1",
-		LastSyncTime: time.Time{},
+		UserID: "user-1",
 	})
 
 	if err != wantErr {
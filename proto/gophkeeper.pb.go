@@ -30,6 +30,8 @@ const (
 	DataType_TEXT           DataType = 2
 	DataType_BINARY         DataType = 3
 	DataType_BANK_CARD      DataType = 4
+	DataType_OTP            DataType = 5 // одноразовые коды TOTP (RFC 6238): содержимое — otpauth:// URI или секрет Base32
+	DataType_SSH_KEY        DataType = 6 // ключ SSH: закрытый и публичный ключ в формате OpenSSH, парольная фраза и комментарий
 )
 
 // Enum value maps for DataType.
@@ -40,6 +42,8 @@ var (
 		2: "TEXT",
 		3: "BINARY",
 		4: "BANK_CARD",
+		5: "OTP",
+		6: "SSH_KEY",
 	}
 	DataType_value = map[string]int32{
 		"UNKNOWN":        0,
@@ -47,6 +51,8 @@ var (
 		"TEXT":           2,
 		"BINARY":         3,
 		"BANK_CARD":      4,
+		"OTP":            5,
+		"SSH_KEY":        6,
 	}
 )
 
@@ -77,6 +83,56 @@ func (DataType) EnumDescriptor() ([]byte, []int) {
 	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{0}
 }
 
+// Поле сортировки списка
+type DataSortField int32
+
+const (
+	DataSortField_SORT_UPDATED_AT DataSortField = 0
+	DataSortField_SORT_NAME       DataSortField = 1 // у записей с зашифрованным названием name пуст, они идут по id
+	DataSortField_SORT_CREATED_AT DataSortField = 2
+)
+
+// Enum value maps for DataSortField.
+var (
+	DataSortField_name = map[int32]string{
+		0: "SORT_UPDATED_AT",
+		1: "SORT_NAME",
+		2: "SORT_CREATED_AT",
+	}
+	DataSortField_value = map[string]int32{
+		"SORT_UPDATED_AT": 0,
+		"SORT_NAME":       1,
+		"SORT_CREATED_AT": 2,
+	}
+)
+
+func (x DataSortField) Enum() *DataSortField {
+	p := new(DataSortField)
+	*p = x
+	return p
+}
+
+func (x DataSortField) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DataSortField) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_gophkeeper_proto_enumTypes[1].Descriptor()
+}
+
+func (DataSortField) Type() protoreflect.EnumType {
+	return &file_proto_gophkeeper_proto_enumTypes[1]
+}
+
+func (x DataSortField) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DataSortField.Descriptor instead.
+func (DataSortField) EnumDescriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{1}
+}
+
 // Запрос регистрации
 type RegisterRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -252,6 +308,7 @@ type LoginResponse struct {
 	AccessToken   string                 `protobuf:"bytes,3,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
 	RefreshToken  string                 `protobuf:"bytes,4,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
 	ExpiresIn     int64                  `protobuf:"varint,5,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	KeyCheck      []byte                 `protobuf:"bytes,6,opt,name=key_check,json=keyCheck,proto3" json:"key_check,omitempty"` // блок проверки мастер-пароля (пусто, если ещё не задан)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -321,6 +378,13 @@ func (x *LoginResponse) GetExpiresIn() int64 {
 	return 0
 }
 
+func (x *LoginResponse) GetKeyCheck() []byte {
+	if x != nil {
+		return x.KeyCheck
+	}
+	return nil
+}
+
 // Запрос обновления токена
 type RefreshTokenRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -435,29 +499,29 @@ func (x *RefreshTokenResponse) GetExpiresIn() int64 {
 	return 0
 }
 
-// Метаданные
-type Metadata struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Запрос сохранения блока проверки мастер-пароля
+type SetKeyCheckRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	KeyCheck         []byte                 `protobuf:"bytes,1,opt,name=key_check,json=keyCheck,proto3" json:"key_check,omitempty"`
+	PreviousKeyCheck []byte                 `protobuf:"bytes,2,opt,name=previous_key_check,json=previousKeyCheck,proto3" json:"previous_key_check,omitempty"` // текущее значение на сервере (пусто при первой установке)
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *Metadata) Reset() {
-	*x = Metadata{}
+func (x *SetKeyCheckRequest) Reset() {
+	*x = SetKeyCheckRequest{}
 	mi := &file_proto_gophkeeper_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Metadata) String() string {
+func (x *SetKeyCheckRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Metadata) ProtoMessage() {}
+func (*SetKeyCheckRequest) ProtoMessage() {}
 
-func (x *Metadata) ProtoReflect() protoreflect.Message {
+func (x *SetKeyCheckRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gophkeeper_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -469,54 +533,48 @@ func (x *Metadata) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Metadata.ProtoReflect.Descriptor instead.
-func (*Metadata) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetKeyCheckRequest.ProtoReflect.Descriptor instead.
+func (*SetKeyCheckRequest) Descriptor() ([]byte, []int) {
 	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *Metadata) GetKey() string {
+func (x *SetKeyCheckRequest) GetKeyCheck() []byte {
 	if x != nil {
-		return x.Key
+		return x.KeyCheck
 	}
-	return ""
+	return nil
 }
 
-func (x *Metadata) GetValue() string {
+func (x *SetKeyCheckRequest) GetPreviousKeyCheck() []byte {
 	if x != nil {
-		return x.Value
+		return x.PreviousKeyCheck
 	}
-	return ""
+	return nil
 }
 
-// Данные для сохранения
-type Data struct {
+// Ответ сохранения блока проверки мастер-пароля
+type SetKeyCheckResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Type          DataType               `protobuf:"varint,2,opt,name=type,proto3,enum=gophkeeper.DataType" json:"type,omitempty"`
-	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	EncryptedData []byte                 `protobuf:"bytes,4,opt,name=encrypted_data,json=encryptedData,proto3" json:"encrypted_data,omitempty"`
-	Metadata      []*Metadata            `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     int64                  `protobuf:"varint,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	Version       int64                  `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Data) Reset() {
-	*x = Data{}
+func (x *SetKeyCheckResponse) Reset() {
+	*x = SetKeyCheckResponse{}
 	mi := &file_proto_gophkeeper_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Data) String() string {
+func (x *SetKeyCheckResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Data) ProtoMessage() {}
+func (*SetKeyCheckResponse) ProtoMessage() {}
 
-func (x *Data) ProtoReflect() protoreflect.Message {
+func (x *SetKeyCheckResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gophkeeper_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -528,90 +586,127 @@ func (x *Data) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Data.ProtoReflect.Descriptor instead.
-func (*Data) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetKeyCheckResponse.ProtoReflect.Descriptor instead.
+func (*SetKeyCheckResponse) Descriptor() ([]byte, []int) {
 	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *Data) GetId() string {
+func (x *SetKeyCheckResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Id
+		return x.Success
 	}
-	return ""
+	return false
 }
 
-func (x *Data) GetType() DataType {
+func (x *SetKeyCheckResponse) GetMessage() string {
 	if x != nil {
-		return x.Type
+		return x.Message
 	}
-	return DataType_UNKNOWN
+	return ""
 }
 
-func (x *Data) GetName() string {
+// Запрос смены пароля входа и/или мастер-пароля
+type ChangePasswordRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	OldPassword      string                 `protobuf:"bytes,1,opt,name=old_password,json=oldPassword,proto3" json:"old_password,omitempty"`
+	NewPassword      string                 `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"` // пусто — пароль входа не меняется
+	KeyCheck         []byte                 `protobuf:"bytes,3,opt,name=key_check,json=keyCheck,proto3" json:"key_check,omitempty"`          // блок проверки для нового мастер-пароля (пусто — не меняется)
+	PreviousKeyCheck []byte                 `protobuf:"bytes,4,opt,name=previous_key_check,json=previousKeyCheck,proto3" json:"previous_key_check,omitempty"`
+	Data             []*ReencryptedData     `protobuf:"bytes,5,rep,name=data,proto3" json:"data,omitempty"` // записи, перешифрованные клиентом
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ChangePasswordRequest) Reset() {
+	*x = ChangePasswordRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordRequest) ProtoMessage() {}
+
+func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[8]
 	if x != nil {
-		return x.Name
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *Data) GetEncryptedData() []byte {
+// Deprecated: Use ChangePasswordRequest.ProtoReflect.Descriptor instead.
+func (*ChangePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ChangePasswordRequest) GetOldPassword() string {
 	if x != nil {
-		return x.EncryptedData
+		return x.OldPassword
 	}
-	return nil
+	return ""
 }
 
-func (x *Data) GetMetadata() []*Metadata {
+func (x *ChangePasswordRequest) GetNewPassword() string {
 	if x != nil {
-		return x.Metadata
+		return x.NewPassword
 	}
-	return nil
+	return ""
 }
 
-func (x *Data) GetCreatedAt() int64 {
+func (x *ChangePasswordRequest) GetKeyCheck() []byte {
 	if x != nil {
-		return x.CreatedAt
+		return x.KeyCheck
 	}
-	return 0
+	return nil
 }
 
-func (x *Data) GetUpdatedAt() int64 {
+func (x *ChangePasswordRequest) GetPreviousKeyCheck() []byte {
 	if x != nil {
-		return x.UpdatedAt
+		return x.PreviousKeyCheck
 	}
-	return 0
+	return nil
 }
 
-func (x *Data) GetVersion() int64 {
+func (x *ChangePasswordRequest) GetData() []*ReencryptedData {
 	if x != nil {
-		return x.Version
+		return x.Data
 	}
-	return 0
+	return nil
 }
 
-// Запрос сохранения данных
-type SaveDataRequest struct {
+// Перешифрованное содержимое записи
+type ReencryptedData struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Data          *Data                  `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	EncryptedData []byte                 `protobuf:"bytes,2,opt,name=encrypted_data,json=encryptedData,proto3" json:"encrypted_data,omitempty"`
+	Version       int64                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"` // версия, от которой перешифровывал клиент
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SaveDataRequest) Reset() {
-	*x = SaveDataRequest{}
-	mi := &file_proto_gophkeeper_proto_msgTypes[8]
+func (x *ReencryptedData) Reset() {
+	*x = ReencryptedData{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SaveDataRequest) String() string {
+func (x *ReencryptedData) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SaveDataRequest) ProtoMessage() {}
+func (*ReencryptedData) ProtoMessage() {}
 
-func (x *SaveDataRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gophkeeper_proto_msgTypes[8]
+func (x *ReencryptedData) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -622,44 +717,59 @@ func (x *SaveDataRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SaveDataRequest.ProtoReflect.Descriptor instead.
-func (*SaveDataRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use ReencryptedData.ProtoReflect.Descriptor instead.
+func (*ReencryptedData) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *SaveDataRequest) GetData() *Data {
+func (x *ReencryptedData) GetId() string {
 	if x != nil {
-		return x.Data
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ReencryptedData) GetEncryptedData() []byte {
+	if x != nil {
+		return x.EncryptedData
 	}
 	return nil
 }
 
-// Ответ сохранения данных
-type SaveDataResponse struct {
+func (x *ReencryptedData) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// Ответ смены пароля (прежние токены больше не действуют)
+type ChangePasswordResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	DataId        string                 `protobuf:"bytes,3,opt,name=data_id,json=dataId,proto3" json:"data_id,omitempty"`
-	Version       int64                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	AccessToken   string                 `protobuf:"bytes,3,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,4,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	ExpiresIn     int64                  `protobuf:"varint,5,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SaveDataResponse) Reset() {
-	*x = SaveDataResponse{}
-	mi := &file_proto_gophkeeper_proto_msgTypes[9]
+func (x *ChangePasswordResponse) Reset() {
+	*x = ChangePasswordResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SaveDataResponse) String() string {
+func (x *ChangePasswordResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SaveDataResponse) ProtoMessage() {}
+func (*ChangePasswordResponse) ProtoMessage() {}
 
-func (x *SaveDataResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gophkeeper_proto_msgTypes[9]
+func (x *ChangePasswordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -670,62 +780,70 @@ func (x *SaveDataResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SaveDataResponse.ProtoReflect.Descriptor instead.
-func (*SaveDataResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use ChangePasswordResponse.ProtoReflect.Descriptor instead.
+func (*ChangePasswordResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *SaveDataResponse) GetSuccess() bool {
+func (x *ChangePasswordResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *SaveDataResponse) GetMessage() string {
+func (x *ChangePasswordResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-func (x *SaveDataResponse) GetDataId() string {
+func (x *ChangePasswordResponse) GetAccessToken() string {
 	if x != nil {
-		return x.DataId
+		return x.AccessToken
 	}
 	return ""
 }
 
-func (x *SaveDataResponse) GetVersion() int64 {
+func (x *ChangePasswordResponse) GetRefreshToken() string {
 	if x != nil {
-		return x.Version
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *ChangePasswordResponse) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
 	}
 	return 0
 }
 
-// Запрос получения данных
-type GetDataRequest struct {
+// Метаданные
+type Metadata struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DataId        string                 `protobuf:"bytes,1,opt,name=data_id,json=dataId,proto3" json:"data_id,omitempty"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetDataRequest) Reset() {
-	*x = GetDataRequest{}
-	mi := &file_proto_gophkeeper_proto_msgTypes[10]
+func (x *Metadata) Reset() {
+	*x = Metadata{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDataRequest) String() string {
+func (x *Metadata) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDataRequest) ProtoMessage() {}
+func (*Metadata) ProtoMessage() {}
 
-func (x *GetDataRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gophkeeper_proto_msgTypes[10]
+func (x *Metadata) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -736,44 +854,507 @@ func (x *GetDataRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDataRequest.ProtoReflect.Descriptor instead.
-func (*GetDataRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use Metadata.ProtoReflect.Descriptor instead.
+func (*Metadata) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *GetDataRequest) GetDataId() string {
+func (x *Metadata) GetKey() string {
 	if x != nil {
-		return x.DataId
+		return x.Key
 	}
 	return ""
 }
 
-// Ответ получения данных
-type GetDataResponse struct {
+func (x *Metadata) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+// Данные для сохранения.
+// Если клиент шифрует название и метаданные, name и metadata пусты, а encrypted_meta содержит
+// зашифрованный SealedMeta; search_token — непрозрачный токен названия для поиска без расшифровки.
+// У записей BINARY с файлом blob_id ссылается на содержимое, загруженное через UploadBlob.
+type Data struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Data          *Data                  `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          DataType               `protobuf:"varint,2,opt,name=type,proto3,enum=gophkeeper.DataType" json:"type,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	EncryptedData []byte                 `protobuf:"bytes,4,opt,name=encrypted_data,json=encryptedData,proto3" json:"encrypted_data,omitempty"`
+	Metadata      []*Metadata            `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Version       int64                  `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`
+	EncryptedMeta []byte                 `protobuf:"bytes,9,opt,name=encrypted_meta,json=encryptedMeta,proto3" json:"encrypted_meta,omitempty"`
+	SearchToken   string                 `protobuf:"bytes,10,opt,name=search_token,json=searchToken,proto3" json:"search_token,omitempty"`
+	BlobId        string                 `protobuf:"bytes,11,opt,name=blob_id,json=blobId,proto3" json:"blob_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetDataResponse) Reset() {
-	*x = GetDataResponse{}
-	mi := &file_proto_gophkeeper_proto_msgTypes[11]
+func (x *Data) Reset() {
+	*x = Data{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDataResponse) String() string {
+func (x *Data) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDataResponse) ProtoMessage() {}
+func (*Data) ProtoMessage() {}
 
-func (x *GetDataResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gophkeeper_proto_msgTypes[11]
-	if x != nil {
+func (x *Data) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Data.ProtoReflect.Descriptor instead.
+func (*Data) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Data) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Data) GetType() DataType {
+	if x != nil {
+		return x.Type
+	}
+	return DataType_UNKNOWN
+}
+
+func (x *Data) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Data) GetEncryptedData() []byte {
+	if x != nil {
+		return x.EncryptedData
+	}
+	return nil
+}
+
+func (x *Data) GetMetadata() []*Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Data) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Data) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *Data) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Data) GetEncryptedMeta() []byte {
+	if x != nil {
+		return x.EncryptedMeta
+	}
+	return nil
+}
+
+func (x *Data) GetSearchToken() string {
+	if x != nil {
+		return x.SearchToken
+	}
+	return ""
+}
+
+func (x *Data) GetBlobId() string {
+	if x != nil {
+		return x.BlobId
+	}
+	return ""
+}
+
+// Название и метаданные записи, которые клиент шифрует вместе (содержимое Data.encrypted_meta)
+type SealedMeta struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Metadata      []*Metadata            `protobuf:"bytes,2,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SealedMeta) Reset() {
+	*x = SealedMeta{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SealedMeta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SealedMeta) ProtoMessage() {}
+
+func (x *SealedMeta) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SealedMeta.ProtoReflect.Descriptor instead.
+func (*SealedMeta) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SealedMeta) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SealedMeta) GetMetadata() []*Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// Запрос сохранения данных.
+// При несовпадении base_version с версией на сервере возвращается статус ABORTED,
+// в деталях которого передаётся актуальная копия записи (Data), если она не удалена.
+type SaveDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          *Data                  `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	BaseVersion   int64                  `protobuf:"varint,2,opt,name=base_version,json=baseVersion,proto3" json:"base_version,omitempty"` // версия, от которой клиент вносил изменения (0 — новая запись)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveDataRequest) Reset() {
+	*x = SaveDataRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveDataRequest) ProtoMessage() {}
+
+func (x *SaveDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveDataRequest.ProtoReflect.Descriptor instead.
+func (*SaveDataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SaveDataRequest) GetData() *Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *SaveDataRequest) GetBaseVersion() int64 {
+	if x != nil {
+		return x.BaseVersion
+	}
+	return 0
+}
+
+// Ответ сохранения данных
+type SaveDataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	DataId        string                 `protobuf:"bytes,3,opt,name=data_id,json=dataId,proto3" json:"data_id,omitempty"`
+	Version       int64                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveDataResponse) Reset() {
+	*x = SaveDataResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveDataResponse) ProtoMessage() {}
+
+func (x *SaveDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveDataResponse.ProtoReflect.Descriptor instead.
+func (*SaveDataResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SaveDataResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SaveDataResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SaveDataResponse) GetDataId() string {
+	if x != nil {
+		return x.DataId
+	}
+	return ""
+}
+
+func (x *SaveDataResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// Запрос получения данных
+type GetDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DataId        string                 `protobuf:"bytes,1,opt,name=data_id,json=dataId,proto3" json:"data_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDataRequest) Reset() {
+	*x = GetDataRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDataRequest) ProtoMessage() {}
+
+func (x *GetDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDataRequest.ProtoReflect.Descriptor instead.
+func (*GetDataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetDataRequest) GetDataId() string {
+	if x != nil {
+		return x.DataId
+	}
+	return ""
+}
+
+// Ответ получения данных
+type GetDataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Data          *Data                  `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDataResponse) Reset() {
+	*x = GetDataResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDataResponse) ProtoMessage() {}
+
+func (x *GetDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDataResponse.ProtoReflect.Descriptor instead.
+func (*GetDataResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetDataResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetDataResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetDataResponse) GetData() *Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// Запрос списка данных
+type ListDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          DataType               `protobuf:"varint,1,opt,name=type,proto3,enum=gophkeeper.DataType" json:"type,omitempty"` // 0 = все типы
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDataRequest) Reset() {
+	*x = ListDataRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDataRequest) ProtoMessage() {}
+
+func (x *ListDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDataRequest.ProtoReflect.Descriptor instead.
+func (*ListDataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListDataRequest) GetType() DataType {
+	if x != nil {
+		return x.Type
+	}
+	return DataType_UNKNOWN
+}
+
+// Ответ списка данных
+type ListDataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Data          []*Data                `protobuf:"bytes,3,rep,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDataResponse) Reset() {
+	*x = ListDataResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDataResponse) ProtoMessage() {}
+
+func (x *ListDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[19]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -783,55 +1364,323 @@ func (x *GetDataResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDataResponse.ProtoReflect.Descriptor instead.
-func (*GetDataResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use ListDataResponse.ProtoReflect.Descriptor instead.
+func (*ListDataResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *GetDataResponse) GetSuccess() bool {
+func (x *ListDataResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *GetDataResponse) GetMessage() string {
+func (x *ListDataResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-func (x *GetDataResponse) GetData() *Data {
+func (x *ListDataResponse) GetData() []*Data {
 	if x != nil {
 		return x.Data
 	}
 	return nil
 }
 
-// Запрос списка данных
-type ListDataRequest struct {
+// Запрос кратких описаний записей.
+// Следующая страница запрашивается с next_page_token и теми же type, sort и descending.
+type ListDataSummariesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          DataType               `protobuf:"varint,1,opt,name=type,proto3,enum=gophkeeper.DataType" json:"type,omitempty"` // 0 = все типы
+	Type          DataType               `protobuf:"varint,1,opt,name=type,proto3,enum=gophkeeper.DataType" json:"type,omitempty"`  // 0 = все типы
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // 0 — размер по умолчанию
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // непрозрачный токен из предыдущего ответа (пусто — первая страница)
+	Sort          DataSortField          `protobuf:"varint,4,opt,name=sort,proto3,enum=gophkeeper.DataSortField" json:"sort,omitempty"`
+	Descending    bool                   `protobuf:"varint,5,opt,name=descending,proto3" json:"descending,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListDataRequest) Reset() {
-	*x = ListDataRequest{}
-	mi := &file_proto_gophkeeper_proto_msgTypes[12]
+func (x *ListDataSummariesRequest) Reset() {
+	*x = ListDataSummariesRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListDataRequest) String() string {
+func (x *ListDataSummariesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListDataRequest) ProtoMessage() {}
+func (*ListDataSummariesRequest) ProtoMessage() {}
 
-func (x *ListDataRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gophkeeper_proto_msgTypes[12]
+func (x *ListDataSummariesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDataSummariesRequest.ProtoReflect.Descriptor instead.
+func (*ListDataSummariesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListDataSummariesRequest) GetType() DataType {
+	if x != nil {
+		return x.Type
+	}
+	return DataType_UNKNOWN
+}
+
+func (x *ListDataSummariesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDataSummariesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListDataSummariesRequest) GetSort() DataSortField {
+	if x != nil {
+		return x.Sort
+	}
+	return DataSortField_SORT_UPDATED_AT
+}
+
+func (x *ListDataSummariesRequest) GetDescending() bool {
+	if x != nil {
+		return x.Descending
+	}
+	return false
+}
+
+// Краткое описание записи: всё, что нужно для строки списка, без зашифрованного содержимого
+type DataSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          DataType               `protobuf:"varint,2,opt,name=type,proto3,enum=gophkeeper.DataType" json:"type,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Version       int64                  `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	Size          int64                  `protobuf:"varint,7,opt,name=size,proto3" json:"size,omitempty"`                                       // размер зашифрованного содержимого в байтах
+	EncryptedMeta []byte                 `protobuf:"bytes,8,opt,name=encrypted_meta,json=encryptedMeta,proto3" json:"encrypted_meta,omitempty"` // зашифрованные название и метаданные, если клиент их шифрует
+	BlobId        string                 `protobuf:"bytes,9,opt,name=blob_id,json=blobId,proto3" json:"blob_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DataSummary) Reset() {
+	*x = DataSummary{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DataSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataSummary) ProtoMessage() {}
+
+func (x *DataSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataSummary.ProtoReflect.Descriptor instead.
+func (*DataSummary) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DataSummary) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DataSummary) GetType() DataType {
+	if x != nil {
+		return x.Type
+	}
+	return DataType_UNKNOWN
+}
+
+func (x *DataSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DataSummary) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *DataSummary) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *DataSummary) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *DataSummary) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DataSummary) GetEncryptedMeta() []byte {
+	if x != nil {
+		return x.EncryptedMeta
+	}
+	return nil
+}
+
+func (x *DataSummary) GetBlobId() string {
+	if x != nil {
+		return x.BlobId
+	}
+	return ""
+}
+
+// Ответ со страницей кратких описаний
+type ListDataSummariesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Summaries     []*DataSummary         `protobuf:"bytes,3,rep,name=summaries,proto3" json:"summaries,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,4,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // пусто — страниц больше нет
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDataSummariesResponse) Reset() {
+	*x = ListDataSummariesResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDataSummariesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDataSummariesResponse) ProtoMessage() {}
+
+func (x *ListDataSummariesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDataSummariesResponse.ProtoReflect.Descriptor instead.
+func (*ListDataSummariesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListDataSummariesResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListDataSummariesResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListDataSummariesResponse) GetSummaries() []*DataSummary {
+	if x != nil {
+		return x.Summaries
+	}
+	return nil
+}
+
+func (x *ListDataSummariesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// Запрос поиска записей. Заданные условия объединяются через И; строки сравниваются без учёта регистра.
+// Записи с зашифрованным названием находятся по названию только через search_token (точное совпадение).
+// Следующая страница запрашивается с next_page_token и теми же условиями.
+type SearchDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Types         []DataType             `protobuf:"varint,1,rep,packed,name=types,proto3,enum=gophkeeper.DataType" json:"types,omitempty"` // пусто — все типы
+	NamePrefix    string                 `protobuf:"bytes,2,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`
+	NameContains  string                 `protobuf:"bytes,3,opt,name=name_contains,json=nameContains,proto3" json:"name_contains,omitempty"`
+	SearchToken   string                 `protobuf:"bytes,4,opt,name=search_token,json=searchToken,proto3" json:"search_token,omitempty"`
+	MetadataKeys  []string               `protobuf:"bytes,5,rep,name=metadata_keys,json=metadataKeys,proto3" json:"metadata_keys,omitempty"`  // у записи должны быть все указанные ключи метаданных
+	Tags          []string               `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`                                      // у записи должны быть все теги (метаданные tags, значения через запятую)
+	UpdatedSince  int64                  `protobuf:"varint,7,opt,name=updated_since,json=updatedSince,proto3" json:"updated_since,omitempty"` // unix-время; 0 — без ограничения
+	PageSize      int32                  `protobuf:"varint,8,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,9,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Sort          DataSortField          `protobuf:"varint,10,opt,name=sort,proto3,enum=gophkeeper.DataSortField" json:"sort,omitempty"`
+	Descending    bool                   `protobuf:"varint,11,opt,name=descending,proto3" json:"descending,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchDataRequest) Reset() {
+	*x = SearchDataRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchDataRequest) ProtoMessage() {}
+
+func (x *SearchDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -839,46 +1688,117 @@ func (x *ListDataRequest) ProtoReflect() protoreflect.Message {
 		}
 		return ms
 	}
-	return mi.MessageOf(x)
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchDataRequest.ProtoReflect.Descriptor instead.
+func (*SearchDataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SearchDataRequest) GetTypes() []DataType {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *SearchDataRequest) GetNamePrefix() string {
+	if x != nil {
+		return x.NamePrefix
+	}
+	return ""
+}
+
+func (x *SearchDataRequest) GetNameContains() string {
+	if x != nil {
+		return x.NameContains
+	}
+	return ""
+}
+
+func (x *SearchDataRequest) GetSearchToken() string {
+	if x != nil {
+		return x.SearchToken
+	}
+	return ""
+}
+
+func (x *SearchDataRequest) GetMetadataKeys() []string {
+	if x != nil {
+		return x.MetadataKeys
+	}
+	return nil
+}
+
+func (x *SearchDataRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *SearchDataRequest) GetUpdatedSince() int64 {
+	if x != nil {
+		return x.UpdatedSince
+	}
+	return 0
+}
+
+func (x *SearchDataRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchDataRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
 }
 
-// Deprecated: Use ListDataRequest.ProtoReflect.Descriptor instead.
-func (*ListDataRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{12}
+func (x *SearchDataRequest) GetSort() DataSortField {
+	if x != nil {
+		return x.Sort
+	}
+	return DataSortField_SORT_UPDATED_AT
 }
 
-func (x *ListDataRequest) GetType() DataType {
+func (x *SearchDataRequest) GetDescending() bool {
 	if x != nil {
-		return x.Type
+		return x.Descending
 	}
-	return DataType_UNKNOWN
+	return false
 }
 
-// Ответ списка данных
-type ListDataResponse struct {
+// Ответ поиска записей
+type SearchDataResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Data          []*Data                `protobuf:"bytes,3,rep,name=data,proto3" json:"data,omitempty"`
+	Summaries     []*DataSummary         `protobuf:"bytes,3,rep,name=summaries,proto3" json:"summaries,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,4,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // пусто — страниц больше нет
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListDataResponse) Reset() {
-	*x = ListDataResponse{}
-	mi := &file_proto_gophkeeper_proto_msgTypes[13]
+func (x *SearchDataResponse) Reset() {
+	*x = SearchDataResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListDataResponse) String() string {
+func (x *SearchDataResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListDataResponse) ProtoMessage() {}
+func (*SearchDataResponse) ProtoMessage() {}
 
-func (x *ListDataResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gophkeeper_proto_msgTypes[13]
+func (x *SearchDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -889,32 +1809,39 @@ func (x *ListDataResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListDataResponse.ProtoReflect.Descriptor instead.
-func (*ListDataResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use SearchDataResponse.ProtoReflect.Descriptor instead.
+func (*SearchDataResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *ListDataResponse) GetSuccess() bool {
+func (x *SearchDataResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *ListDataResponse) GetMessage() string {
+func (x *SearchDataResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-func (x *ListDataResponse) GetData() []*Data {
+func (x *SearchDataResponse) GetSummaries() []*DataSummary {
 	if x != nil {
-		return x.Data
+		return x.Summaries
 	}
 	return nil
 }
 
+func (x *SearchDataResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 // Запрос удаления данных
 type DeleteDataRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -925,7 +1852,7 @@ type DeleteDataRequest struct {
 
 func (x *DeleteDataRequest) Reset() {
 	*x = DeleteDataRequest{}
-	mi := &file_proto_gophkeeper_proto_msgTypes[14]
+	mi := &file_proto_gophkeeper_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -937,7 +1864,7 @@ func (x *DeleteDataRequest) String() string {
 func (*DeleteDataRequest) ProtoMessage() {}
 
 func (x *DeleteDataRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gophkeeper_proto_msgTypes[14]
+	mi := &file_proto_gophkeeper_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -950,7 +1877,7 @@ func (x *DeleteDataRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteDataRequest.ProtoReflect.Descriptor instead.
 func (*DeleteDataRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{14}
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *DeleteDataRequest) GetDataId() string {
@@ -971,7 +1898,7 @@ type DeleteDataResponse struct {
 
 func (x *DeleteDataResponse) Reset() {
 	*x = DeleteDataResponse{}
-	mi := &file_proto_gophkeeper_proto_msgTypes[15]
+	mi := &file_proto_gophkeeper_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -983,7 +1910,7 @@ func (x *DeleteDataResponse) String() string {
 func (*DeleteDataResponse) ProtoMessage() {}
 
 func (x *DeleteDataResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gophkeeper_proto_msgTypes[15]
+	mi := &file_proto_gophkeeper_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -996,7 +1923,7 @@ func (x *DeleteDataResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteDataResponse.ProtoReflect.Descriptor instead.
 func (*DeleteDataResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{15}
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *DeleteDataResponse) GetSuccess() bool {
@@ -1013,17 +1940,21 @@ func (x *DeleteDataResponse) GetMessage() string {
 	return ""
 }
 
-// Запрос синхронизации
+// Запрос синхронизации.
+// Изменения выдаются страницами: пока has_more, клиент сразу запрашивает следующую страницу с next_cursor.
 type SyncDataRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	LastSyncTime  int64                  `protobuf:"varint,1,opt,name=last_sync_time,json=lastSyncTime,proto3" json:"last_sync_time,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Deprecated: Marked as deprecated in proto/gophkeeper.proto.
+	LastSyncTime  int64  `protobuf:"varint,1,opt,name=last_sync_time,json=lastSyncTime,proto3" json:"last_sync_time,omitempty"` // не используется, вместо него cursor
+	Cursor        string `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`                                    // непрозрачный курсор из предыдущего ответа (пусто — первая синхронизация)
+	PageSize      int32  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`               // 0 — размер по умолчанию
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SyncDataRequest) Reset() {
 	*x = SyncDataRequest{}
-	mi := &file_proto_gophkeeper_proto_msgTypes[16]
+	mi := &file_proto_gophkeeper_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1035,7 +1966,7 @@ func (x *SyncDataRequest) String() string {
 func (*SyncDataRequest) ProtoMessage() {}
 
 func (x *SyncDataRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gophkeeper_proto_msgTypes[16]
+	mi := &file_proto_gophkeeper_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1048,9 +1979,10 @@ func (x *SyncDataRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SyncDataRequest.ProtoReflect.Descriptor instead.
 func (*SyncDataRequest) Descriptor() ([]byte, []int) {
-	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{16}
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{27}
 }
 
+// Deprecated: Marked as deprecated in proto/gophkeeper.proto.
 func (x *SyncDataRequest) GetLastSyncTime() int64 {
 	if x != nil {
 		return x.LastSyncTime
@@ -1058,20 +1990,100 @@ func (x *SyncDataRequest) GetLastSyncTime() int64 {
 	return 0
 }
 
+func (x *SyncDataRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *SyncDataRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+// Отметка об удалённой записи
+type Tombstone struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DeletedAt     int64                  `protobuf:"varint,2,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	Version       int64                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tombstone) Reset() {
+	*x = Tombstone{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tombstone) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tombstone) ProtoMessage() {}
+
+func (x *Tombstone) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tombstone.ProtoReflect.Descriptor instead.
+func (*Tombstone) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Tombstone) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Tombstone) GetDeletedAt() int64 {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return 0
+}
+
+func (x *Tombstone) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 // Ответ синхронизации
 type SyncDataResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Data          []*Data                `protobuf:"bytes,3,rep,name=data,proto3" json:"data,omitempty"`
-	SyncTime      int64                  `protobuf:"varint,4,opt,name=sync_time,json=syncTime,proto3" json:"sync_time,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Data    []*Data                `protobuf:"bytes,3,rep,name=data,proto3" json:"data,omitempty"`
+	// Deprecated: Marked as deprecated in proto/gophkeeper.proto.
+	SyncTime      int64        `protobuf:"varint,4,opt,name=sync_time,json=syncTime,proto3" json:"sync_time,omitempty"`       // время ответа сервера, для синхронизации не используется
+	Deleted       []*Tombstone `protobuf:"bytes,5,rep,name=deleted,proto3" json:"deleted,omitempty"`                          // записи, удалённые после позиции курсора
+	FullResync    bool         `protobuf:"varint,6,opt,name=full_resync,json=fullResync,proto3" json:"full_resync,omitempty"` // страница полного снимка: после последней страницы локальные записи, которых не было в ответах, нужно удалить
+	NextCursor    string       `protobuf:"bytes,7,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`  // курсор для следующего запроса
+	HasMore       bool         `protobuf:"varint,8,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`          // есть следующие страницы
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SyncDataResponse) Reset() {
 	*x = SyncDataResponse{}
-	mi := &file_proto_gophkeeper_proto_msgTypes[17]
+	mi := &file_proto_gophkeeper_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1083,7 +2095,7 @@ func (x *SyncDataResponse) String() string {
 func (*SyncDataResponse) ProtoMessage() {}
 
 func (x *SyncDataResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gophkeeper_proto_msgTypes[17]
+	mi := &file_proto_gophkeeper_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1096,7 +2108,7 @@ func (x *SyncDataResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SyncDataResponse.ProtoReflect.Descriptor instead.
 func (*SyncDataResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{17}
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *SyncDataResponse) GetSuccess() bool {
@@ -1120,6 +2132,7 @@ func (x *SyncDataResponse) GetData() []*Data {
 	return nil
 }
 
+// Deprecated: Marked as deprecated in proto/gophkeeper.proto.
 func (x *SyncDataResponse) GetSyncTime() int64 {
 	if x != nil {
 		return x.SyncTime
@@ -1127,6 +2140,193 @@ func (x *SyncDataResponse) GetSyncTime() int64 {
 	return 0
 }
 
+func (x *SyncDataResponse) GetDeleted() []*Tombstone {
+	if x != nil {
+		return x.Deleted
+	}
+	return nil
+}
+
+func (x *SyncDataResponse) GetFullResync() bool {
+	if x != nil {
+		return x.FullResync
+	}
+	return false
+}
+
+func (x *SyncDataResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *SyncDataResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+// Часть зашифрованного содержимого файла
+type BlobChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Content       []byte                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlobChunk) Reset() {
+	*x = BlobChunk{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlobChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobChunk) ProtoMessage() {}
+
+func (x *BlobChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobChunk.ProtoReflect.Descriptor instead.
+func (*BlobChunk) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *BlobChunk) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+// Ответ загрузки файла
+type UploadBlobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	BlobId        string                 `protobuf:"bytes,3,opt,name=blob_id,json=blobId,proto3" json:"blob_id,omitempty"`
+	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"` // размер сохранённого (зашифрованного) содержимого
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadBlobResponse) Reset() {
+	*x = UploadBlobResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadBlobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadBlobResponse) ProtoMessage() {}
+
+func (x *UploadBlobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadBlobResponse.ProtoReflect.Descriptor instead.
+func (*UploadBlobResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *UploadBlobResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UploadBlobResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *UploadBlobResponse) GetBlobId() string {
+	if x != nil {
+		return x.BlobId
+	}
+	return ""
+}
+
+func (x *UploadBlobResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+// Запрос выгрузки файла
+type DownloadBlobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BlobId        string                 `protobuf:"bytes,1,opt,name=blob_id,json=blobId,proto3" json:"blob_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadBlobRequest) Reset() {
+	*x = DownloadBlobRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadBlobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadBlobRequest) ProtoMessage() {}
+
+func (x *DownloadBlobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadBlobRequest.ProtoReflect.Descriptor instead.
+func (*DownloadBlobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *DownloadBlobRequest) GetBlobId() string {
+	if x != nil {
+		return x.BlobId
+	}
+	return ""
+}
+
 var File_proto_gophkeeper_proto protoreflect.FileDescriptor
 
 const file_proto_gophkeeper_proto_rawDesc = "" +
@@ -1142,14 +2342,15 @@ const file_proto_gophkeeper_proto_rawDesc = "" +
 	"\auser_id\x18\x03 \x01(\tR\x06userId\"@\n" +
 	"\fLoginRequest\x12\x14\n" +
 	"\x05login\x18\x01 \x01(\tR\x05login\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"\xaa\x01\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"\xc7\x01\n" +
 	"\rLoginResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12!\n" +
 	"\faccess_token\x18\x03 \x01(\tR\vaccessToken\x12#\n" +
 	"\rrefresh_token\x18\x04 \x01(\tR\frefreshToken\x12\x1d\n" +
 	"\n" +
-	"expires_in\x18\x05 \x01(\x03R\texpiresIn\":\n" +
+	"expires_in\x18\x05 \x01(\x03R\texpiresIn\x12\x1b\n" +
+	"\tkey_check\x18\x06 \x01(\fR\bkeyCheck\":\n" +
 	"\x13RefreshTokenRequest\x12#\n" +
 	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\"\x97\x01\n" +
 	"\x14RefreshTokenResponse\x12\x18\n" +
@@ -1157,10 +2358,33 @@ const file_proto_gophkeeper_proto_rawDesc = "" +
 	"\faccess_token\x18\x02 \x01(\tR\vaccessToken\x12#\n" +
 	"\rrefresh_token\x18\x03 \x01(\tR\frefreshToken\x12\x1d\n" +
 	"\n" +
-	"expires_in\x18\x04 \x01(\x03R\texpiresIn\"2\n" +
+	"expires_in\x18\x04 \x01(\x03R\texpiresIn\"_\n" +
+	"\x12SetKeyCheckRequest\x12\x1b\n" +
+	"\tkey_check\x18\x01 \x01(\fR\bkeyCheck\x12,\n" +
+	"\x12previous_key_check\x18\x02 \x01(\fR\x10previousKeyCheck\"I\n" +
+	"\x13SetKeyCheckResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xd9\x01\n" +
+	"\x15ChangePasswordRequest\x12!\n" +
+	"\fold_password\x18\x01 \x01(\tR\voldPassword\x12!\n" +
+	"\fnew_password\x18\x02 \x01(\tR\vnewPassword\x12\x1b\n" +
+	"\tkey_check\x18\x03 \x01(\fR\bkeyCheck\x12,\n" +
+	"\x12previous_key_check\x18\x04 \x01(\fR\x10previousKeyCheck\x12/\n" +
+	"\x04data\x18\x05 \x03(\v2\x1b.gophkeeper.ReencryptedDataR\x04data\"b\n" +
+	"\x0fReencryptedData\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12%\n" +
+	"\x0eencrypted_data\x18\x02 \x01(\fR\rencryptedData\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x03R\aversion\"\xb3\x01\n" +
+	"\x16ChangePasswordResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12!\n" +
+	"\faccess_token\x18\x03 \x01(\tR\vaccessToken\x12#\n" +
+	"\rrefresh_token\x18\x04 \x01(\tR\frefreshToken\x12\x1d\n" +
+	"\n" +
+	"expires_in\x18\x05 \x01(\x03R\texpiresIn\"2\n" +
 	"\bMetadata\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value\"\x85\x02\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"\xe8\x02\n" +
 	"\x04Data\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12(\n" +
 	"\x04type\x18\x02 \x01(\x0e2\x14.gophkeeper.DataTypeR\x04type\x12\x12\n" +
@@ -1171,9 +2395,18 @@ const file_proto_gophkeeper_proto_rawDesc = "" +
 	"created_at\x18\x06 \x01(\x03R\tcreatedAt\x12\x1d\n" +
 	"\n" +
 	"updated_at\x18\a \x01(\x03R\tupdatedAt\x12\x18\n" +
-	"\aversion\x18\b \x01(\x03R\aversion\"7\n" +
+	"\aversion\x18\b \x01(\x03R\aversion\x12%\n" +
+	"\x0eencrypted_meta\x18\t \x01(\fR\rencryptedMeta\x12!\n" +
+	"\fsearch_token\x18\n" +
+	" \x01(\tR\vsearchToken\x12\x17\n" +
+	"\ablob_id\x18\v \x01(\tR\x06blobId\"R\n" +
+	"\n" +
+	"SealedMeta\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x120\n" +
+	"\bmetadata\x18\x02 \x03(\v2\x14.gophkeeper.MetadataR\bmetadata\"Z\n" +
 	"\x0fSaveDataRequest\x12$\n" +
-	"\x04data\x18\x01 \x01(\v2\x10.gophkeeper.DataR\x04data\"y\n" +
+	"\x04data\x18\x01 \x01(\v2\x10.gophkeeper.DataR\x04data\x12!\n" +
+	"\fbase_version\x18\x02 \x01(\x03R\vbaseVersion\"y\n" +
 	"\x10SaveDataResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x17\n" +
@@ -1190,37 +2423,121 @@ const file_proto_gophkeeper_proto_rawDesc = "" +
 	"\x10ListDataResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12$\n" +
-	"\x04data\x18\x03 \x03(\v2\x10.gophkeeper.DataR\x04data\",\n" +
+	"\x04data\x18\x03 \x03(\v2\x10.gophkeeper.DataR\x04data\"\xcf\x01\n" +
+	"\x18ListDataSummariesRequest\x12(\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x14.gophkeeper.DataTypeR\x04type\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12-\n" +
+	"\x04sort\x18\x04 \x01(\x0e2\x19.gophkeeper.DataSortFieldR\x04sort\x12\x1e\n" +
+	"\n" +
+	"descending\x18\x05 \x01(\bR\n" +
+	"descending\"\x87\x02\n" +
+	"\vDataSummary\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12(\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x14.gophkeeper.DataTypeR\x04type\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\x03R\tupdatedAt\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x03R\aversion\x12\x12\n" +
+	"\x04size\x18\a \x01(\x03R\x04size\x12%\n" +
+	"\x0eencrypted_meta\x18\b \x01(\fR\rencryptedMeta\x12\x17\n" +
+	"\ablob_id\x18\t \x01(\tR\x06blobId\"\xae\x01\n" +
+	"\x19ListDataSummariesResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x125\n" +
+	"\tsummaries\x18\x03 \x03(\v2\x17.gophkeeper.DataSummaryR\tsummaries\x12&\n" +
+	"\x0fnext_page_token\x18\x04 \x01(\tR\rnextPageToken\"\x91\x03\n" +
+	"\x11SearchDataRequest\x12*\n" +
+	"\x05types\x18\x01 \x03(\x0e2\x14.gophkeeper.DataTypeR\x05types\x12\x1f\n" +
+	"\vname_prefix\x18\x02 \x01(\tR\n" +
+	"namePrefix\x12#\n" +
+	"\rname_contains\x18\x03 \x01(\tR\fnameContains\x12!\n" +
+	"\fsearch_token\x18\x04 \x01(\tR\vsearchToken\x12#\n" +
+	"\rmetadata_keys\x18\x05 \x03(\tR\fmetadataKeys\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x12#\n" +
+	"\rupdated_since\x18\a \x01(\x03R\fupdatedSince\x12\x1b\n" +
+	"\tpage_size\x18\b \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\t \x01(\tR\tpageToken\x12-\n" +
+	"\x04sort\x18\n" +
+	" \x01(\x0e2\x19.gophkeeper.DataSortFieldR\x04sort\x12\x1e\n" +
+	"\n" +
+	"descending\x18\v \x01(\bR\n" +
+	"descending\"\xa7\x01\n" +
+	"\x12SearchDataResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x125\n" +
+	"\tsummaries\x18\x03 \x03(\v2\x17.gophkeeper.DataSummaryR\tsummaries\x12&\n" +
+	"\x0fnext_page_token\x18\x04 \x01(\tR\rnextPageToken\",\n" +
 	"\x11DeleteDataRequest\x12\x17\n" +
 	"\adata_id\x18\x01 \x01(\tR\x06dataId\"H\n" +
 	"\x12DeleteDataResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"7\n" +
-	"\x0fSyncDataRequest\x12$\n" +
-	"\x0elast_sync_time\x18\x01 \x01(\x03R\flastSyncTime\"\x89\x01\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"p\n" +
+	"\x0fSyncDataRequest\x12(\n" +
+	"\x0elast_sync_time\x18\x01 \x01(\x03B\x02\x18\x01R\flastSyncTime\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\"T\n" +
+	"\tTombstone\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"deleted_at\x18\x02 \x01(\x03R\tdeletedAt\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x03R\aversion\"\x9b\x02\n" +
 	"\x10SyncDataResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12$\n" +
-	"\x04data\x18\x03 \x03(\v2\x10.gophkeeper.DataR\x04data\x12\x1b\n" +
-	"\tsync_time\x18\x04 \x01(\x03R\bsyncTime*P\n" +
+	"\x04data\x18\x03 \x03(\v2\x10.gophkeeper.DataR\x04data\x12\x1f\n" +
+	"\tsync_time\x18\x04 \x01(\x03B\x02\x18\x01R\bsyncTime\x12/\n" +
+	"\adeleted\x18\x05 \x03(\v2\x15.gophkeeper.TombstoneR\adeleted\x12\x1f\n" +
+	"\vfull_resync\x18\x06 \x01(\bR\n" +
+	"fullResync\x12\x1f\n" +
+	"\vnext_cursor\x18\a \x01(\tR\n" +
+	"nextCursor\x12\x19\n" +
+	"\bhas_more\x18\b \x01(\bR\ahasMore\"%\n" +
+	"\tBlobChunk\x12\x18\n" +
+	"\acontent\x18\x01 \x01(\fR\acontent\"u\n" +
+	"\x12UploadBlobResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x17\n" +
+	"\ablob_id\x18\x03 \x01(\tR\x06blobId\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\".\n" +
+	"\x13DownloadBlobRequest\x12\x17\n" +
+	"\ablob_id\x18\x01 \x01(\tR\x06blobId*f\n" +
 	"\bDataType\x12\v\n" +
 	"\aUNKNOWN\x10\x00\x12\x12\n" +
 	"\x0eLOGIN_PASSWORD\x10\x01\x12\b\n" +
 	"\x04TEXT\x10\x02\x12\n" +
 	"\n" +
 	"\x06BINARY\x10\x03\x12\r\n" +
-	"\tBANK_CARD\x10\x042\xe5\x01\n" +
+	"\tBANK_CARD\x10\x04\x12\a\n" +
+	"\x03OTP\x10\x05\x12\v\n" +
+	"\aSSH_KEY\x10\x06*H\n" +
+	"\rDataSortField\x12\x13\n" +
+	"\x0fSORT_UPDATED_AT\x10\x00\x12\r\n" +
+	"\tSORT_NAME\x10\x01\x12\x13\n" +
+	"\x0fSORT_CREATED_AT\x10\x022\x8e\x03\n" +
 	"\vAuthService\x12E\n" +
 	"\bRegister\x12\x1b.gophkeeper.RegisterRequest\x1a\x1c.gophkeeper.RegisterResponse\x12<\n" +
 	"\x05Login\x12\x18.gophkeeper.LoginRequest\x1a\x19.gophkeeper.LoginResponse\x12Q\n" +
-	"\fRefreshToken\x12\x1f.gophkeeper.RefreshTokenRequest\x1a .gophkeeper.RefreshTokenResponse2\xf3\x02\n" +
+	"\fRefreshToken\x12\x1f.gophkeeper.RefreshTokenRequest\x1a .gophkeeper.RefreshTokenResponse\x12N\n" +
+	"\vSetKeyCheck\x12\x1e.gophkeeper.SetKeyCheckRequest\x1a\x1f.gophkeeper.SetKeyCheckResponse\x12W\n" +
+	"\x0eChangePassword\x12!.gophkeeper.ChangePasswordRequest\x1a\".gophkeeper.ChangePasswordResponse2\xb3\x05\n" +
 	"\vDataService\x12E\n" +
 	"\bSaveData\x12\x1b.gophkeeper.SaveDataRequest\x1a\x1c.gophkeeper.SaveDataResponse\x12B\n" +
 	"\aGetData\x12\x1a.gophkeeper.GetDataRequest\x1a\x1b.gophkeeper.GetDataResponse\x12E\n" +
-	"\bListData\x12\x1b.gophkeeper.ListDataRequest\x1a\x1c.gophkeeper.ListDataResponse\x12K\n" +
+	"\bListData\x12\x1b.gophkeeper.ListDataRequest\x1a\x1c.gophkeeper.ListDataResponse\x12`\n" +
+	"\x11ListDataSummaries\x12$.gophkeeper.ListDataSummariesRequest\x1a%.gophkeeper.ListDataSummariesResponse\x12K\n" +
+	"\n" +
+	"SearchData\x12\x1d.gophkeeper.SearchDataRequest\x1a\x1e.gophkeeper.SearchDataResponse\x12K\n" +
 	"\n" +
 	"DeleteData\x12\x1d.gophkeeper.DeleteDataRequest\x1a\x1e.gophkeeper.DeleteDataResponse\x12E\n" +
-	"\bSyncData\x12\x1b.gophkeeper.SyncDataRequest\x1a\x1c.gophkeeper.SyncDataResponseB(Z&github.com/gophkeeper/gophkeeper/protob\x06proto3"
+	"\bSyncData\x12\x1b.gophkeeper.SyncDataRequest\x1a\x1c.gophkeeper.SyncDataResponse\x12E\n" +
+	"\n" +
+	"UploadBlob\x12\x15.gophkeeper.BlobChunk\x1a\x1e.gophkeeper.UploadBlobResponse(\x01\x12H\n" +
+	"\fDownloadBlob\x12\x1f.gophkeeper.DownloadBlobRequest\x1a\x15.gophkeeper.BlobChunk0\x01B(Z&github.com/gophkeeper/gophkeeper/protob\x06proto3"
 
 var (
 	file_proto_gophkeeper_proto_rawDescOnce sync.Once
@@ -1234,58 +2551,96 @@ func file_proto_gophkeeper_proto_rawDescGZIP() []byte {
 	return file_proto_gophkeeper_proto_rawDescData
 }
 
-var file_proto_gophkeeper_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_gophkeeper_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_proto_gophkeeper_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_gophkeeper_proto_msgTypes = make([]protoimpl.MessageInfo, 33)
 var file_proto_gophkeeper_proto_goTypes = []any{
-	(DataType)(0),                // 0: gophkeeper.DataType
-	(*RegisterRequest)(nil),      // 1: gophkeeper.RegisterRequest
-	(*RegisterResponse)(nil),     // 2: gophkeeper.RegisterResponse
-	(*LoginRequest)(nil),         // 3: gophkeeper.LoginRequest
-	(*LoginResponse)(nil),        // 4: gophkeeper.LoginResponse
-	(*RefreshTokenRequest)(nil),  // 5: gophkeeper.RefreshTokenRequest
-	(*RefreshTokenResponse)(nil), // 6: gophkeeper.RefreshTokenResponse
-	(*Metadata)(nil),             // 7: gophkeeper.Metadata
-	(*Data)(nil),                 // 8: gophkeeper.Data
-	(*SaveDataRequest)(nil),      // 9: gophkeeper.SaveDataRequest
-	(*SaveDataResponse)(nil),     // 10: gophkeeper.SaveDataResponse
-	(*GetDataRequest)(nil),       // 11: gophkeeper.GetDataRequest
-	(*GetDataResponse)(nil),      // 12: gophkeeper.GetDataResponse
-	(*ListDataRequest)(nil),      // 13: gophkeeper.ListDataRequest
-	(*ListDataResponse)(nil),     // 14: gophkeeper.ListDataResponse
-	(*DeleteDataRequest)(nil),    // 15: gophkeeper.DeleteDataRequest
-	(*DeleteDataResponse)(nil),   // 16: gophkeeper.DeleteDataResponse
-	(*SyncDataRequest)(nil),      // 17: gophkeeper.SyncDataRequest
-	(*SyncDataResponse)(nil),     // 18: gophkeeper.SyncDataResponse
+	(DataType)(0),                     // 0: gophkeeper.DataType
+	(DataSortField)(0),                // 1: gophkeeper.DataSortField
+	(*RegisterRequest)(nil),           // 2: gophkeeper.RegisterRequest
+	(*RegisterResponse)(nil),          // 3: gophkeeper.RegisterResponse
+	(*LoginRequest)(nil),              // 4: gophkeeper.LoginRequest
+	(*LoginResponse)(nil),             // 5: gophkeeper.LoginResponse
+	(*RefreshTokenRequest)(nil),       // 6: gophkeeper.RefreshTokenRequest
+	(*RefreshTokenResponse)(nil),      // 7: gophkeeper.RefreshTokenResponse
+	(*SetKeyCheckRequest)(nil),        // 8: gophkeeper.SetKeyCheckRequest
+	(*SetKeyCheckResponse)(nil),       // 9: gophkeeper.SetKeyCheckResponse
+	(*ChangePasswordRequest)(nil),     // 10: gophkeeper.ChangePasswordRequest
+	(*ReencryptedData)(nil),           // 11: gophkeeper.ReencryptedData
+	(*ChangePasswordResponse)(nil),    // 12: gophkeeper.ChangePasswordResponse
+	(*Metadata)(nil),                  // 13: gophkeeper.Metadata
+	(*Data)(nil),                      // 14: gophkeeper.Data
+	(*SealedMeta)(nil),                // 15: gophkeeper.SealedMeta
+	(*SaveDataRequest)(nil),           // 16: gophkeeper.SaveDataRequest
+	(*SaveDataResponse)(nil),          // 17: gophkeeper.SaveDataResponse
+	(*GetDataRequest)(nil),            // 18: gophkeeper.GetDataRequest
+	(*GetDataResponse)(nil),           // 19: gophkeeper.GetDataResponse
+	(*ListDataRequest)(nil),           // 20: gophkeeper.ListDataRequest
+	(*ListDataResponse)(nil),          // 21: gophkeeper.ListDataResponse
+	(*ListDataSummariesRequest)(nil),  // 22: gophkeeper.ListDataSummariesRequest
+	(*DataSummary)(nil),               // 23: gophkeeper.DataSummary
+	(*ListDataSummariesResponse)(nil), // 24: gophkeeper.ListDataSummariesResponse
+	(*SearchDataRequest)(nil),         // 25: gophkeeper.SearchDataRequest
+	(*SearchDataResponse)(nil),        // 26: gophkeeper.SearchDataResponse
+	(*DeleteDataRequest)(nil),         // 27: gophkeeper.DeleteDataRequest
+	(*DeleteDataResponse)(nil),        // 28: gophkeeper.DeleteDataResponse
+	(*SyncDataRequest)(nil),           // 29: gophkeeper.SyncDataRequest
+	(*Tombstone)(nil),                 // 30: gophkeeper.Tombstone
+	(*SyncDataResponse)(nil),          // 31: gophkeeper.SyncDataResponse
+	(*BlobChunk)(nil),                 // 32: gophkeeper.BlobChunk
+	(*UploadBlobResponse)(nil),        // 33: gophkeeper.UploadBlobResponse
+	(*DownloadBlobRequest)(nil),       // 34: gophkeeper.DownloadBlobRequest
 }
 var file_proto_gophkeeper_proto_depIdxs = []int32{
-	0,  // 0: gophkeeper.Data.type:type_name -> gophkeeper.DataType
-	7,  // 1: gophkeeper.Data.metadata:type_name -> gophkeeper.Metadata
-	8,  // 2: gophkeeper.SaveDataRequest.data:type_name -> gophkeeper.Data
-	8,  // 3: gophkeeper.GetDataResponse.data:type_name -> gophkeeper.Data
-	0,  // 4: gophkeeper.ListDataRequest.type:type_name -> gophkeeper.DataType
-	8,  // 5: gophkeeper.ListDataResponse.data:type_name -> gophkeeper.Data
-	8,  // 6: gophkeeper.SyncDataResponse.data:type_name -> gophkeeper.Data
-	1,  // 7: gophkeeper.AuthService.Register:input_type -> gophkeeper.RegisterRequest
-	3,  // 8: gophkeeper.AuthService.Login:input_type -> gophkeeper.LoginRequest
-	5,  // 9: gophkeeper.AuthService.RefreshToken:input_type -> gophkeeper.RefreshTokenRequest
-	9,  // 10: gophkeeper.DataService.SaveData:input_type -> gophkeeper.SaveDataRequest
-	11, // 11: gophkeeper.DataService.GetData:input_type -> gophkeeper.GetDataRequest
-	13, // 12: gophkeeper.DataService.ListData:input_type -> gophkeeper.ListDataRequest
-	15, // 13: gophkeeper.DataService.DeleteData:input_type -> gophkeeper.DeleteDataRequest
-	17, // 14: gophkeeper.DataService.SyncData:input_type -> gophkeeper.SyncDataRequest
-	2,  // 15: gophkeeper.AuthService.Register:output_type -> gophkeeper.RegisterResponse
-	4,  // 16: gophkeeper.AuthService.Login:output_type -> gophkeeper.LoginResponse
-	6,  // 17: gophkeeper.AuthService.RefreshToken:output_type -> gophkeeper.RefreshTokenResponse
-	10, // 18: gophkeeper.DataService.SaveData:output_type -> gophkeeper.SaveDataResponse
-	12, // 19: gophkeeper.DataService.GetData:output_type -> gophkeeper.GetDataResponse
-	14, // 20: gophkeeper.DataService.ListData:output_type -> gophkeeper.ListDataResponse
-	16, // 21: gophkeeper.DataService.DeleteData:output_type -> gophkeeper.DeleteDataResponse
-	18, // 22: gophkeeper.DataService.SyncData:output_type -> gophkeeper.SyncDataResponse
-	15, // [15:23] is the sub-list for method output_type
-	7,  // [7:15] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	11, // 0: gophkeeper.ChangePasswordRequest.data:type_name -> gophkeeper.ReencryptedData
+	0,  // 1: gophkeeper.Data.type:type_name -> gophkeeper.DataType
+	13, // 2: gophkeeper.Data.metadata:type_name -> gophkeeper.Metadata
+	13, // 3: gophkeeper.SealedMeta.metadata:type_name -> gophkeeper.Metadata
+	14, // 4: gophkeeper.SaveDataRequest.data:type_name -> gophkeeper.Data
+	14, // 5: gophkeeper.GetDataResponse.data:type_name -> gophkeeper.Data
+	0,  // 6: gophkeeper.ListDataRequest.type:type_name -> gophkeeper.DataType
+	14, // 7: gophkeeper.ListDataResponse.data:type_name -> gophkeeper.Data
+	0,  // 8: gophkeeper.ListDataSummariesRequest.type:type_name -> gophkeeper.DataType
+	1,  // 9: gophkeeper.ListDataSummariesRequest.sort:type_name -> gophkeeper.DataSortField
+	0,  // 10: gophkeeper.DataSummary.type:type_name -> gophkeeper.DataType
+	23, // 11: gophkeeper.ListDataSummariesResponse.summaries:type_name -> gophkeeper.DataSummary
+	0,  // 12: gophkeeper.SearchDataRequest.types:type_name -> gophkeeper.DataType
+	1,  // 13: gophkeeper.SearchDataRequest.sort:type_name -> gophkeeper.DataSortField
+	23, // 14: gophkeeper.SearchDataResponse.summaries:type_name -> gophkeeper.DataSummary
+	14, // 15: gophkeeper.SyncDataResponse.data:type_name -> gophkeeper.Data
+	30, // 16: gophkeeper.SyncDataResponse.deleted:type_name -> gophkeeper.Tombstone
+	2,  // 17: gophkeeper.AuthService.Register:input_type -> gophkeeper.RegisterRequest
+	4,  // 18: gophkeeper.AuthService.Login:input_type -> gophkeeper.LoginRequest
+	6,  // 19: gophkeeper.AuthService.RefreshToken:input_type -> gophkeeper.RefreshTokenRequest
+	8,  // 20: gophkeeper.AuthService.SetKeyCheck:input_type -> gophkeeper.SetKeyCheckRequest
+	10, // 21: gophkeeper.AuthService.ChangePassword:input_type -> gophkeeper.ChangePasswordRequest
+	16, // 22: gophkeeper.DataService.SaveData:input_type -> gophkeeper.SaveDataRequest
+	18, // 23: gophkeeper.DataService.GetData:input_type -> gophkeeper.GetDataRequest
+	20, // 24: gophkeeper.DataService.ListData:input_type -> gophkeeper.ListDataRequest
+	22, // 25: gophkeeper.DataService.ListDataSummaries:input_type -> gophkeeper.ListDataSummariesRequest
+	25, // 26: gophkeeper.DataService.SearchData:input_type -> gophkeeper.SearchDataRequest
+	27, // 27: gophkeeper.DataService.DeleteData:input_type -> gophkeeper.DeleteDataRequest
+	29, // 28: gophkeeper.DataService.SyncData:input_type -> gophkeeper.SyncDataRequest
+	32, // 29: gophkeeper.DataService.UploadBlob:input_type -> gophkeeper.BlobChunk
+	34, // 30: gophkeeper.DataService.DownloadBlob:input_type -> gophkeeper.DownloadBlobRequest
+	3,  // 31: gophkeeper.AuthService.Register:output_type -> gophkeeper.RegisterResponse
+	5,  // 32: gophkeeper.AuthService.Login:output_type -> gophkeeper.LoginResponse
+	7,  // 33: gophkeeper.AuthService.RefreshToken:output_type -> gophkeeper.RefreshTokenResponse
+	9,  // 34: gophkeeper.AuthService.SetKeyCheck:output_type -> gophkeeper.SetKeyCheckResponse
+	12, // 35: gophkeeper.AuthService.ChangePassword:output_type -> gophkeeper.ChangePasswordResponse
+	17, // 36: gophkeeper.DataService.SaveData:output_type -> gophkeeper.SaveDataResponse
+	19, // 37: gophkeeper.DataService.GetData:output_type -> gophkeeper.GetDataResponse
+	21, // 38: gophkeeper.DataService.ListData:output_type -> gophkeeper.ListDataResponse
+	24, // 39: gophkeeper.DataService.ListDataSummaries:output_type -> gophkeeper.ListDataSummariesResponse
+	26, // 40: gophkeeper.DataService.SearchData:output_type -> gophkeeper.SearchDataResponse
+	28, // 41: gophkeeper.DataService.DeleteData:output_type -> gophkeeper.DeleteDataResponse
+	31, // 42: gophkeeper.DataService.SyncData:output_type -> gophkeeper.SyncDataResponse
+	33, // 43: gophkeeper.DataService.UploadBlob:output_type -> gophkeeper.UploadBlobResponse
+	32, // 44: gophkeeper.DataService.DownloadBlob:output_type -> gophkeeper.BlobChunk
+	31, // [31:45] is the sub-list for method output_type
+	17, // [17:31] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_proto_gophkeeper_proto_init() }
@@ -1298,8 +2653,8 @@ func file_proto_gophkeeper_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_gophkeeper_proto_rawDesc), len(file_proto_gophkeeper_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   18,
+			NumEnums:      2,
+			NumMessages:   33,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
@@ -19,9 +19,11 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AuthService_Register_FullMethodName     = "/gophkeeper.AuthService/Register"
-	AuthService_Login_FullMethodName        = "/gophkeeper.AuthService/Login"
-	AuthService_RefreshToken_FullMethodName = "/gophkeeper.AuthService/RefreshToken"
+	AuthService_Register_FullMethodName       = "/gophkeeper.AuthService/Register"
+	AuthService_Login_FullMethodName          = "/gophkeeper.AuthService/Login"
+	AuthService_RefreshToken_FullMethodName   = "/gophkeeper.AuthService/RefreshToken"
+	AuthService_SetKeyCheck_FullMethodName    = "/gophkeeper.AuthService/SetKeyCheck"
+	AuthService_ChangePassword_FullMethodName = "/gophkeeper.AuthService/ChangePassword"
 )
 
 // AuthServiceClient is the client API for AuthService service.
@@ -33,6 +35,8 @@ type AuthServiceClient interface {
 	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
 	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
 	RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*RefreshTokenResponse, error)
+	SetKeyCheck(ctx context.Context, in *SetKeyCheckRequest, opts ...grpc.CallOption) (*SetKeyCheckResponse, error)
+	ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*ChangePasswordResponse, error)
 }
 
 type authServiceClient struct {
@@ -73,6 +77,26 @@ func (c *authServiceClient) RefreshToken(ctx context.Context, in *RefreshTokenRe
 	return out, nil
 }
 
+func (c *authServiceClient) SetKeyCheck(ctx context.Context, in *SetKeyCheckRequest, opts ...grpc.CallOption) (*SetKeyCheckResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetKeyCheckResponse)
+	err := c.cc.Invoke(ctx, AuthService_SetKeyCheck_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*ChangePasswordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangePasswordResponse)
+	err := c.cc.Invoke(ctx, AuthService_ChangePassword_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AuthServiceServer is the server API for AuthService service.
 // All implementations must embed UnimplementedAuthServiceServer
 // for forward compatibility.
@@ -82,6 +106,8 @@ type AuthServiceServer interface {
 	Register(context.Context, *RegisterRequest) (*RegisterResponse, error)
 	Login(context.Context, *LoginRequest) (*LoginResponse, error)
 	RefreshToken(context.Context, *RefreshTokenRequest) (*RefreshTokenResponse, error)
+	SetKeyCheck(context.Context, *SetKeyCheckRequest) (*SetKeyCheckResponse, error)
+	ChangePassword(context.Context, *ChangePasswordRequest) (*ChangePasswordResponse, error)
 	mustEmbedUnimplementedAuthServiceServer()
 }
 
@@ -101,6 +127,12 @@ func (UnimplementedAuthServiceServer) Login(context.Context, *LoginRequest) (*Lo
 func (UnimplementedAuthServiceServer) RefreshToken(context.Context, *RefreshTokenRequest) (*RefreshTokenResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method RefreshToken not implemented")
 }
+func (UnimplementedAuthServiceServer) SetKeyCheck(context.Context, *SetKeyCheckRequest) (*SetKeyCheckResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetKeyCheck not implemented")
+}
+func (UnimplementedAuthServiceServer) ChangePassword(context.Context, *ChangePasswordRequest) (*ChangePasswordResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ChangePassword not implemented")
+}
 func (UnimplementedAuthServiceServer) mustEmbedUnimplementedAuthServiceServer() {}
 func (UnimplementedAuthServiceServer) testEmbeddedByValue()                     {}
 
@@ -176,6 +208,42 @@ func _AuthService_RefreshToken_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthService_SetKeyCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetKeyCheckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).SetKeyCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_SetKeyCheck_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).SetKeyCheck(ctx, req.(*SetKeyCheckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_ChangePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangePasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).ChangePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_ChangePassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).ChangePassword(ctx, req.(*ChangePasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AuthService_ServiceDesc is the grpc.ServiceDesc for AuthService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -195,17 +263,29 @@ var AuthService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RefreshToken",
 			Handler:    _AuthService_RefreshToken_Handler,
 		},
+		{
+			MethodName: "SetKeyCheck",
+			Handler:    _AuthService_SetKeyCheck_Handler,
+		},
+		{
+			MethodName: "ChangePassword",
+			Handler:    _AuthService_ChangePassword_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/gophkeeper.proto",
 }
 
 const (
-	DataService_SaveData_FullMethodName   = "/gophkeeper.DataService/SaveData"
-	DataService_GetData_FullMethodName    = "/gophkeeper.DataService/GetData"
-	DataService_ListData_FullMethodName   = "/gophkeeper.DataService/ListData"
-	DataService_DeleteData_FullMethodName = "/gophkeeper.DataService/DeleteData"
-	DataService_SyncData_FullMethodName   = "/gophkeeper.DataService/SyncData"
+	DataService_SaveData_FullMethodName          = "/gophkeeper.DataService/SaveData"
+	DataService_GetData_FullMethodName           = "/gophkeeper.DataService/GetData"
+	DataService_ListData_FullMethodName          = "/gophkeeper.DataService/ListData"
+	DataService_ListDataSummaries_FullMethodName = "/gophkeeper.DataService/ListDataSummaries"
+	DataService_SearchData_FullMethodName        = "/gophkeeper.DataService/SearchData"
+	DataService_DeleteData_FullMethodName        = "/gophkeeper.DataService/DeleteData"
+	DataService_SyncData_FullMethodName          = "/gophkeeper.DataService/SyncData"
+	DataService_UploadBlob_FullMethodName        = "/gophkeeper.DataService/UploadBlob"
+	DataService_DownloadBlob_FullMethodName      = "/gophkeeper.DataService/DownloadBlob"
 )
 
 // DataServiceClient is the client API for DataService service.
@@ -216,9 +296,18 @@ const (
 type DataServiceClient interface {
 	SaveData(ctx context.Context, in *SaveDataRequest, opts ...grpc.CallOption) (*SaveDataResponse, error)
 	GetData(ctx context.Context, in *GetDataRequest, opts ...grpc.CallOption) (*GetDataResponse, error)
+	// Полные записи вместе с зашифрованным содержимым; для отображения списка — ListDataSummaries
 	ListData(ctx context.Context, in *ListDataRequest, opts ...grpc.CallOption) (*ListDataResponse, error)
+	// Краткие описания записей без содержимого, постранично и с сортировкой
+	ListDataSummaries(ctx context.Context, in *ListDataSummariesRequest, opts ...grpc.CallOption) (*ListDataSummariesResponse, error)
+	// Поиск записей по названию, типу, ключам метаданных, тегам и времени изменения
+	SearchData(ctx context.Context, in *SearchDataRequest, opts ...grpc.CallOption) (*SearchDataResponse, error)
 	DeleteData(ctx context.Context, in *DeleteDataRequest, opts ...grpc.CallOption) (*DeleteDataResponse, error)
 	SyncData(ctx context.Context, in *SyncDataRequest, opts ...grpc.CallOption) (*SyncDataResponse, error)
+	// Загрузка зашифрованного содержимого файла частями; ID блоба затем указывается в Data.blob_id
+	UploadBlob(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[BlobChunk, UploadBlobResponse], error)
+	// Выгрузка зашифрованного содержимого файла частями
+	DownloadBlob(ctx context.Context, in *DownloadBlobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BlobChunk], error)
 }
 
 type dataServiceClient struct {
@@ -259,6 +348,26 @@ func (c *dataServiceClient) ListData(ctx context.Context, in *ListDataRequest, o
 	return out, nil
 }
 
+func (c *dataServiceClient) ListDataSummaries(ctx context.Context, in *ListDataSummariesRequest, opts ...grpc.CallOption) (*ListDataSummariesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDataSummariesResponse)
+	err := c.cc.Invoke(ctx, DataService_ListDataSummaries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataServiceClient) SearchData(ctx context.Context, in *SearchDataRequest, opts ...grpc.CallOption) (*SearchDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchDataResponse)
+	err := c.cc.Invoke(ctx, DataService_SearchData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *dataServiceClient) DeleteData(ctx context.Context, in *DeleteDataRequest, opts ...grpc.CallOption) (*DeleteDataResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DeleteDataResponse)
@@ -279,6 +388,38 @@ func (c *dataServiceClient) SyncData(ctx context.Context, in *SyncDataRequest, o
 	return out, nil
 }
 
+func (c *dataServiceClient) UploadBlob(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[BlobChunk, UploadBlobResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DataService_ServiceDesc.Streams[0], DataService_UploadBlob_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[BlobChunk, UploadBlobResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DataService_UploadBlobClient = grpc.ClientStreamingClient[BlobChunk, UploadBlobResponse]
+
+func (c *dataServiceClient) DownloadBlob(ctx context.Context, in *DownloadBlobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BlobChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DataService_ServiceDesc.Streams[1], DataService_DownloadBlob_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadBlobRequest, BlobChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DataService_DownloadBlobClient = grpc.ServerStreamingClient[BlobChunk]
+
 // DataServiceServer is the server API for DataService service.
 // All implementations must embed UnimplementedDataServiceServer
 // for forward compatibility.
@@ -287,9 +428,18 @@ func (c *dataServiceClient) SyncData(ctx context.Context, in *SyncDataRequest, o
 type DataServiceServer interface {
 	SaveData(context.Context, *SaveDataRequest) (*SaveDataResponse, error)
 	GetData(context.Context, *GetDataRequest) (*GetDataResponse, error)
+	// Полные записи вместе с зашифрованным содержимым; для отображения списка — ListDataSummaries
 	ListData(context.Context, *ListDataRequest) (*ListDataResponse, error)
+	// Краткие описания записей без содержимого, постранично и с сортировкой
+	ListDataSummaries(context.Context, *ListDataSummariesRequest) (*ListDataSummariesResponse, error)
+	// Поиск записей по названию, типу, ключам метаданных, тегам и времени изменения
+	SearchData(context.Context, *SearchDataRequest) (*SearchDataResponse, error)
 	DeleteData(context.Context, *DeleteDataRequest) (*DeleteDataResponse, error)
 	SyncData(context.Context, *SyncDataRequest) (*SyncDataResponse, error)
+	// Загрузка зашифрованного содержимого файла частями; ID блоба затем указывается в Data.blob_id
+	UploadBlob(grpc.ClientStreamingServer[BlobChunk, UploadBlobResponse]) error
+	// Выгрузка зашифрованного содержимого файла частями
+	DownloadBlob(*DownloadBlobRequest, grpc.ServerStreamingServer[BlobChunk]) error
 	mustEmbedUnimplementedDataServiceServer()
 }
 
@@ -309,12 +459,24 @@ func (UnimplementedDataServiceServer) GetData(context.Context, *GetDataRequest)
 func (UnimplementedDataServiceServer) ListData(context.Context, *ListDataRequest) (*ListDataResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListData not implemented")
 }
+func (UnimplementedDataServiceServer) ListDataSummaries(context.Context, *ListDataSummariesRequest) (*ListDataSummariesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListDataSummaries not implemented")
+}
+func (UnimplementedDataServiceServer) SearchData(context.Context, *SearchDataRequest) (*SearchDataResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SearchData not implemented")
+}
 func (UnimplementedDataServiceServer) DeleteData(context.Context, *DeleteDataRequest) (*DeleteDataResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method DeleteData not implemented")
 }
 func (UnimplementedDataServiceServer) SyncData(context.Context, *SyncDataRequest) (*SyncDataResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SyncData not implemented")
 }
+func (UnimplementedDataServiceServer) UploadBlob(grpc.ClientStreamingServer[BlobChunk, UploadBlobResponse]) error {
+	return status.Error(codes.Unimplemented, "method UploadBlob not implemented")
+}
+func (UnimplementedDataServiceServer) DownloadBlob(*DownloadBlobRequest, grpc.ServerStreamingServer[BlobChunk]) error {
+	return status.Error(codes.Unimplemented, "method DownloadBlob not implemented")
+}
 func (UnimplementedDataServiceServer) mustEmbedUnimplementedDataServiceServer() {}
 func (UnimplementedDataServiceServer) testEmbeddedByValue()                     {}
 
@@ -390,6 +552,42 @@ func _DataService_ListData_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DataService_ListDataSummaries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDataSummariesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).ListDataSummaries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_ListDataSummaries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).ListDataSummaries(ctx, req.(*ListDataSummariesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataService_SearchData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).SearchData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_SearchData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).SearchData(ctx, req.(*SearchDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _DataService_DeleteData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteDataRequest)
 	if err := dec(in); err != nil {
@@ -426,6 +624,24 @@ func _DataService_SyncData_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DataService_UploadBlob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DataServiceServer).UploadBlob(&grpc.GenericServerStream[BlobChunk, UploadBlobResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DataService_UploadBlobServer = grpc.ClientStreamingServer[BlobChunk, UploadBlobResponse]
+
+func _DataService_DownloadBlob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadBlobRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DataServiceServer).DownloadBlob(m, &grpc.GenericServerStream[DownloadBlobRequest, BlobChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DataService_DownloadBlobServer = grpc.ServerStreamingServer[BlobChunk]
+
 // DataService_ServiceDesc is the grpc.ServiceDesc for DataService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -445,6 +661,14 @@ var DataService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListData",
 			Handler:    _DataService_ListData_Handler,
 		},
+		{
+			MethodName: "ListDataSummaries",
+			Handler:    _DataService_ListDataSummaries_Handler,
+		},
+		{
+			MethodName: "SearchData",
+			Handler:    _DataService_SearchData_Handler,
+		},
 		{
 			MethodName: "DeleteData",
 			Handler:    _DataService_DeleteData_Handler,
@@ -454,6 +678,17 @@ var DataService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _DataService_SyncData_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadBlob",
+			Handler:       _DataService_UploadBlob_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadBlob",
+			Handler:       _DataService_DownloadBlob_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "proto/gophkeeper.proto",
 }